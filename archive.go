@@ -0,0 +1,136 @@
+package order
+
+import "slices"
+
+// RestoreStrategy decides where Restore puts an archived item back.
+type RestoreStrategy int
+
+const (
+	// RestorePosition puts the item back at the position it had when it was
+	// archived. It fails with a *PositionError if the list has become too short.
+	RestorePosition RestoreStrategy = iota
+	// RestoreNearest puts the item back at its old position, or at the end if
+	// the list has become too short.
+	RestoreNearest
+	// RestoreEnd puts the item back at the last position.
+	RestoreEnd
+)
+
+type archivedItem[T Orderable] struct {
+	item     T
+	position int
+}
+
+// Archive removes the item with the given ID from the collection, remembering
+// its position for Restore, and renumbers the remaining items.
+func (c *OrderedCollection[T]) Archive(itemID string) error {
+	index, err := c.manager.GetItemIndexByID(c.items, itemID)
+	if err != nil {
+		return err
+	}
+	c.archived = append(c.archived, archivedItem[T]{item: c.items[index], position: index + 1})
+	c.items = slices.Delete(c.items, index, index+1)
+	c.manager.normalize(c.items)
+	c.changed()
+	return nil
+}
+
+// Archived returns the archived items, in the order they were archived.
+func (c *OrderedCollection[T]) Archived() []T {
+	items := make([]T, len(c.archived))
+	for i, a := range c.archived {
+		items[i] = a.item
+	}
+	return items
+}
+
+// Restore puts an archived item back into the collection as decided by
+// strategy and renumbers the items.
+func (c *OrderedCollection[T]) Restore(itemID string, strategy RestoreStrategy) error {
+	i := slices.IndexFunc(c.archived, func(a archivedItem[T]) bool { return a.item.GetID() == itemID })
+	if i < 0 {
+		return &NotFoundError{Op: "Restore", ItemID: itemID}
+	}
+	a := c.archived[i]
+
+	position := a.position
+	switch last := len(c.items) + 1; strategy {
+	case RestoreEnd:
+		position = last
+	case RestoreNearest:
+		position = min(position, last)
+	default:
+		if position > last {
+			return &PositionError{Op: "Restore", Requested: position, Min: 1, Max: last}
+		}
+	}
+
+	c.archived = slices.Delete(c.archived, i, i+1)
+	c.items = slices.Insert(c.items, position-1, a.item)
+	c.manager.normalize(c.items)
+	c.changed()
+	return nil
+}
+
+type excludedItem[T Orderable] struct {
+	item T
+	// after is the ID of the item directly above, or "" if the item was first.
+	after    string
+	position int
+}
+
+// Exclude temporarily takes the item with the given ID out of the collection,
+// for example while a filter hides it, and renumbers the remaining items.
+// Unlike Archive it remembers the item's neighbor rather than its position, so
+// Include can put it back in the same spot relative to the other items.
+func (c *OrderedCollection[T]) Exclude(itemID string) error {
+	index, err := c.manager.GetItemIndexByID(c.items, itemID)
+	if err != nil {
+		return err
+	}
+	e := excludedItem[T]{item: c.items[index], position: index + 1}
+	if index > 0 {
+		e.after = c.items[index-1].GetID()
+	}
+	c.excluded = append(c.excluded, e)
+	c.items = slices.Delete(c.items, index, index+1)
+	c.manager.normalize(c.items)
+	c.changed()
+	return nil
+}
+
+// Excluded returns the excluded items, in the order they were excluded.
+func (c *OrderedCollection[T]) Excluded() []T {
+	items := make([]T, len(c.excluded))
+	for i, e := range c.excluded {
+		items[i] = e.item
+	}
+	return items
+}
+
+// Include puts an excluded item back directly below the item that was above it
+// when it was excluded, or first if it was first. If that neighbor is no longer
+// in the collection, the item returns to its old position, or the end if the
+// list has become too short.
+func (c *OrderedCollection[T]) Include(itemID string) error {
+	i := slices.IndexFunc(c.excluded, func(e excludedItem[T]) bool { return e.item.GetID() == itemID })
+	if i < 0 {
+		return &NotFoundError{Op: "Include", ItemID: itemID}
+	}
+	e := c.excluded[i]
+
+	index := 0
+	if e.after != "" {
+		if after, err := c.manager.GetItemIndexByID(c.items, e.after); err == nil {
+			index = after + 1
+		} else {
+			index = min(e.position, len(c.items)+1) - 1
+		}
+	}
+
+	c.excluded = slices.Delete(c.excluded, i, i+1)
+	c.items = slices.Insert(c.items, index, e.item)
+	c.manager.normalize(c.items)
+	c.changed()
+	return nil
+}
@@ -0,0 +1,81 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestArchiveRestore(t *testing.T) {
+	items := createTestItems(4)
+	all := append([]*TestItem(nil), items...)
+	c := order.NewOrderedCollection(items)
+
+	require.NoError(t, c.Archive(all[1].GetID()))
+	assert.Equal(t, []*TestItem{all[0], all[2], all[3]}, c.Items())
+	assert.Equal(t, []*TestItem{all[1]}, c.Archived())
+	assert.True(t, order.IsNormalized(c.Items()))
+
+	require.NoError(t, c.Restore(all[1].GetID(), order.RestorePosition))
+	assert.Equal(t, all, c.Items())
+	assert.Empty(t, c.Archived())
+	assert.True(t, order.IsNormalized(c.Items()))
+
+	assert.ErrorIs(t, c.Restore(all[1].GetID(), order.RestoreEnd), order.ErrItemNotFound)
+	assert.ErrorIs(t, c.Archive("missing"), order.ErrItemNotFound)
+}
+
+func TestRestoreStrategies(t *testing.T) {
+	items := createTestItems(4)
+	all := append([]*TestItem(nil), items...)
+	c := order.NewOrderedCollection(items)
+
+	require.NoError(t, c.Archive(all[3].GetID()))
+	require.NoError(t, c.Archive(all[2].GetID()))
+
+	// all[3] was at position 4, but with two items left the last slot is 3.
+	var posErr *order.PositionError
+	assert.ErrorAs(t, c.Restore(all[3].GetID(), order.RestorePosition), &posErr)
+	require.NoError(t, c.Restore(all[3].GetID(), order.RestoreNearest))
+	assert.Equal(t, []*TestItem{all[0], all[1], all[3]}, c.Items())
+
+	require.NoError(t, c.Restore(all[2].GetID(), order.RestoreEnd))
+	assert.Equal(t, []*TestItem{all[0], all[1], all[3], all[2]}, c.Items())
+	assert.True(t, order.IsNormalized(c.Items()))
+}
+
+func TestExcludeInclude(t *testing.T) {
+	items := createTestItems(5)
+	all := append([]*TestItem(nil), items...)
+	c := order.NewOrderedCollection(items)
+
+	require.NoError(t, c.Exclude(all[2].GetID()))
+	require.NoError(t, c.Exclude(all[0].GetID()))
+	assert.Equal(t, []*TestItem{all[1], all[3], all[4]}, c.Items())
+	assert.Equal(t, []*TestItem{all[2], all[0]}, c.Excluded())
+
+	// Reordering while hidden: all[2] follows its neighbor all[1].
+	_, err := order.NewOrderManager[*TestItem]().Bottom(c.Items(), all[1].GetID())
+	require.NoError(t, err)
+	require.NoError(t, c.Include(all[2].GetID()))
+	assert.Equal(t, []*TestItem{all[3], all[4], all[1], all[2]}, c.Items())
+
+	require.NoError(t, c.Include(all[0].GetID()))
+	assert.Equal(t, []*TestItem{all[0], all[3], all[4], all[1], all[2]}, c.Items())
+	assert.True(t, order.IsNormalized(c.Items()))
+	assert.ErrorIs(t, c.Include(all[0].GetID()), order.ErrItemNotFound)
+}
+
+func TestIncludeWithoutNeighbor(t *testing.T) {
+	items := createTestItems(4)
+	all := append([]*TestItem(nil), items...)
+	c := order.NewOrderedCollection(items)
+
+	require.NoError(t, c.Exclude(all[2].GetID()))
+	require.NoError(t, c.Archive(all[1].GetID()))
+	require.NoError(t, c.Include(all[2].GetID()))
+	assert.Equal(t, []*TestItem{all[0], all[3], all[2]}, c.Items())
+}
@@ -0,0 +1,84 @@
+package order
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// AtomicList is a list for many concurrent readers and few writers. Readers
+// call Load and get an immutable View of the current order without taking a
+// lock, while a writer prepares the next version on copies of the items and
+// swaps it in atomically. Rendering a list thus never waits for a move, and a
+// View stays consistent however long it is used. Writers are serialized.
+type AtomicList[T Orderable] struct {
+	current atomic.Pointer[View[T]]
+	mu      sync.Mutex // serializes writers
+	manager *OrderManager[T]
+}
+
+// View is an immutable version of an AtomicList. Neither the slice nor the
+// items may be modified.
+type View[T Orderable] struct {
+	items   []T
+	version int64
+}
+
+// Items returns the items of the view in order. The slice is shared by all
+// readers of the view and must not be modified.
+func (v *View[T]) Items() []T {
+	return v.items
+}
+
+// Len returns the number of items.
+func (v *View[T]) Len() int {
+	return len(v.items)
+}
+
+// Version counts the writes that produced the view, starting from 0.
+func (v *View[T]) Version() int64 {
+	return v.version
+}
+
+// NewAtomicList creates an AtomicList holding items, which are moved with
+// manager. The list takes ownership of items and normalizes their positions.
+func NewAtomicList[T Orderable](items []T, manager *OrderManager[T]) *AtomicList[T] {
+	l := &AtomicList[T]{manager: manager}
+	manager.NormalizePositions(items)
+	l.current.Store(&View[T]{items: items})
+	return l
+}
+
+// Load returns the current view. It never blocks.
+func (l *AtomicList[T]) Load() *View[T] {
+	return l.current.Load()
+}
+
+// Apply performs m on the next version of the list and publishes it. The
+// Affected items of the Result are the ones of the new version.
+func (l *AtomicList[T]) Apply(m Move[string]) (Result[T], error) {
+	var result Result[T]
+	err := l.Update(func(items []T) ([]T, error) {
+		var err error
+		result, err = l.manager.Apply(items, m)
+		return items, err
+	})
+	return result, err
+}
+
+// Update runs change on a copy of the current items, made as by Propose, and
+// publishes the slice it returns as the next version unless change fails.
+// change may reorder the copy and return it, or return another slice with
+// items added or removed. The positions are normalized before the version is
+// published; readers see none of it until Update returns.
+func (l *AtomicList[T]) Update(change func(items []T) ([]T, error)) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	current := l.current.Load()
+	next, err := change(copyItems(current.items, 0))
+	if err != nil {
+		return err
+	}
+	l.manager.NormalizePositions(next)
+	l.current.Store(&View[T]{items: next, version: current.version + 1})
+	return nil
+}
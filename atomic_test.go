@@ -0,0 +1,78 @@
+package order_test
+
+import (
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAtomicList(t *testing.T) {
+	l := order.NewAtomicList(ordertest.Items(3), order.NewOrderManager[*ordertest.Item]())
+	before := l.Load()
+
+	result, err := l.Apply(order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewPosition)
+
+	after := l.Load()
+	assert.Equal(t, int64(1), after.Version())
+	ordertest.AssertOrder(t, after.Items(), "item-3", "item-1", "item-2")
+	ordertest.AssertNormalized(t, after.Items())
+	assert.Same(t, after.Items()[0], result.Affected[0])
+
+	// The earlier view is unchanged, items included.
+	assert.Zero(t, before.Version())
+	ordertest.AssertOrder(t, before.Items(), "item-1", "item-2", "item-3")
+	ordertest.AssertNormalized(t, before.Items())
+
+	_, err = l.Apply(order.Move[string]{Kind: order.MoveTop, ItemID: "missing"})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	assert.Same(t, after, l.Load(), "failed writes publish nothing")
+}
+
+func TestAtomicListUpdate(t *testing.T) {
+	l := order.NewAtomicList(ordertest.Items(2), order.NewOrderManager[*ordertest.Item]())
+
+	require.NoError(t, l.Update(func(items []*ordertest.Item) ([]*ordertest.Item, error) {
+		items = append(items, &ordertest.Item{ID: "new", Position: 3})
+		return slices.Delete(items, 0, 1), nil
+	}))
+	ordertest.AssertOrder(t, l.Load().Items(), "item-2", "new")
+	assert.Equal(t, 2, l.Load().Len())
+
+	boom := errors.New("boom")
+	assert.ErrorIs(t, l.Update(func(items []*ordertest.Item) ([]*ordertest.Item, error) {
+		items[0].Position = 99
+		return nil, boom
+	}), boom)
+	assert.Equal(t, 1, l.Load().Items()[0].Position)
+}
+
+func TestAtomicListConcurrentReaders(t *testing.T) {
+	l := order.NewAtomicList(ordertest.Items(20), order.NewOrderManager[*ordertest.Item]())
+	var wg sync.WaitGroup
+	for range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 200 {
+				view := l.Load()
+				ordertest.AssertNormalized(t, view.Items())
+				assert.Len(t, view.Items(), 20)
+			}
+		}()
+	}
+	for i := range 100 {
+		_, err := l.Apply(order.Move[string]{Kind: order.MoveTop, ItemID: l.Load().Items()[i%20].ID})
+		require.NoError(t, err)
+	}
+	wg.Wait()
+	assert.Equal(t, int64(100), l.Load().Version())
+}
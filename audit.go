@@ -0,0 +1,190 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx that attributes the moves made with it to
+// actor, such as the ID of the signed-in user. The actor is recorded in the
+// OrderChangedEvent of every move.
+func WithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFrom returns the actor set with WithActor, or "" if there is none.
+func ActorFrom(ctx context.Context) string {
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// TimeRange is the interval [From, To). A zero bound leaves that side open.
+type TimeRange struct {
+	From, To time.Time
+}
+
+// Contains reports whether t is in the range.
+func (r TimeRange) Contains(t time.Time) bool {
+	return (r.From.IsZero() || !t.Before(r.From)) && (r.To.IsZero() || t.Before(r.To))
+}
+
+// AuditQuery selects events from an AuditStore. Empty fields match all
+// events.
+type AuditQuery struct {
+	TenantID string
+	ListID   string
+	// ItemID selects the events that moved the item: its own moves, and
+	// complete orders applied with ApplyOrder that changed its position.
+	// Moves of other items that only shifted it are not included.
+	ItemID    string
+	Actor     string
+	TimeRange TimeRange
+	// Limit keeps only the most recent matching events. 0 means no limit.
+	Limit int
+}
+
+// Matches reports whether the query selects event.
+func (q AuditQuery) Matches(event OrderChangedEvent) bool {
+	if q.TenantID != "" && event.TenantID != q.TenantID {
+		return false
+	}
+	if q.ListID != "" && event.ListID != q.ListID {
+		return false
+	}
+	if q.Actor != "" && event.Actor != q.Actor {
+		return false
+	}
+	if !q.TimeRange.Contains(event.Time) {
+		return false
+	}
+	if q.ItemID == "" || event.Move.ItemID == q.ItemID {
+		return true
+	}
+	return event.Move.Kind == 0 && slices.ContainsFunc(event.Changes, func(c PositionChange) bool { return c.ItemID == q.ItemID })
+}
+
+// AuditStore keeps the OrderChangedEvents of a PersistentManager as a trail
+// of who moved what and when, see WithAuditStore.
+//
+// Implementations must be safe for concurrent use.
+type AuditStore interface {
+	// Append records event.
+	Append(ctx context.Context, event OrderChangedEvent) error
+	// Query returns the events selected by q, oldest first.
+	Query(ctx context.Context, q AuditQuery) ([]OrderChangedEvent, error)
+}
+
+// WithAuditStore records the event of every move that changed a list in
+// store. By default the Store is used if it implements AuditStore, and
+// nothing is recorded otherwise. If a move was saved but its event could not
+// be recorded, the result is returned together with an *AuditError.
+func WithAuditStore(store AuditStore) PersistentOption {
+	return func(o *persistentOptions) {
+		o.audit = store
+	}
+}
+
+// History returns the events of the audit store selected by q, oldest first,
+// for example the activity of an item for a UI panel. The managers of a
+// TenantManager only return the events of their tenant. It fails with
+// ErrNoAuditStore if the manager has no audit store.
+func (pm *PersistentManager[T]) History(ctx context.Context, q AuditQuery) ([]OrderChangedEvent, error) {
+	if pm.opts.audit == nil {
+		return nil, fmt.Errorf("History: %w", ErrNoAuditStore)
+	}
+	if pm.check != nil {
+		q.TenantID = pm.tenantID
+	}
+	return pm.opts.audit.Query(ctx, q)
+}
+
+// MemoryAuditStore is an in-process AuditStore for tests and single servers
+// that can afford to keep every event in memory.
+type MemoryAuditStore struct {
+	mu     sync.RWMutex
+	events []OrderChangedEvent
+}
+
+var _ AuditStore = (*MemoryAuditStore)(nil)
+
+// NewMemoryAuditStore creates an empty MemoryAuditStore.
+func NewMemoryAuditStore() *MemoryAuditStore {
+	return &MemoryAuditStore{}
+}
+
+// Append implements AuditStore.
+func (s *MemoryAuditStore) Append(ctx context.Context, event OrderChangedEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append(s.events, event)
+	return nil
+}
+
+// Query implements AuditStore. Events are returned in the order they were
+// appended.
+func (s *MemoryAuditStore) Query(ctx context.Context, q AuditQuery) ([]OrderChangedEvent, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var events []OrderChangedEvent
+	for _, event := range s.events {
+		if q.Matches(event) {
+			events = append(events, event)
+		}
+	}
+	if q.Limit > 0 && len(events) > q.Limit {
+		events = events[len(events)-q.Limit:]
+	}
+	return events, nil
+}
+
+// OrderAt reconstructs the order of the IDs of a list as it was at t, for
+// support investigations or to restore an earlier order with ApplyOrder. It
+// takes the current order and reverse-applies the changes of the events
+// recorded after t, newest first. Items are added to and removed from lists
+// outside the manager, so an item added since t is still included at the
+// place its position puts it, and an item removed since t is only included
+// if it moved after t. It fails with ErrNoAuditStore if the manager has no
+// audit store, and with ErrInvalidOrder if the recorded changes do not lead
+// back to distinct positions, as happens when the list was reordered without
+// the manager.
+func (pm *PersistentManager[T]) OrderAt(ctx context.Context, listID string, t time.Time) ([]string, error) {
+	if pm.opts.audit == nil {
+		return nil, fmt.Errorf("OrderAt %s: %w", listID, ErrNoAuditStore)
+	}
+	items, _, err := pm.Load(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	events, err := pm.History(ctx, AuditQuery{ListID: listID, TimeRange: TimeRange{From: t.Add(time.Nanosecond)}})
+	if err != nil {
+		return nil, fmt.Errorf("OrderAt %s: %w", listID, err)
+	}
+
+	ids := make([]string, len(items))
+	positions := make(map[string]int, len(items))
+	for i, item := range items {
+		ids[i] = item.GetID()
+		positions[ids[i]] = item.GetPosition()
+	}
+	for _, event := range slices.Backward(events) {
+		for _, change := range event.Changes {
+			if _, ok := positions[change.ItemID]; !ok {
+				ids = append(ids, change.ItemID)
+			}
+			positions[change.ItemID] = change.OldPosition
+		}
+	}
+
+	slices.SortStableFunc(ids, func(a, b string) int { return positions[a] - positions[b] })
+	for i := 1; i < len(ids); i++ {
+		if positions[ids[i]] == positions[ids[i-1]] {
+			return nil, fmt.Errorf("OrderAt %s: items %s and %s both at position %d: %w", listID, ids[i-1], ids[i], positions[ids[i]], ErrInvalidOrder)
+		}
+	}
+	return ids, nil
+}
@@ -0,0 +1,195 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/mockstore"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestActor(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, order.ActorFrom(ctx))
+	assert.Equal(t, "alice", order.ActorFrom(order.WithActor(ctx, "alice")))
+}
+
+func TestTimeRange(t *testing.T) {
+	t0 := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	r := order.TimeRange{From: t0, To: t0.Add(time.Hour)}
+	assert.True(t, r.Contains(t0))
+	assert.True(t, r.Contains(t0.Add(time.Minute)))
+	assert.False(t, r.Contains(t0.Add(time.Hour)))
+	assert.False(t, r.Contains(t0.Add(-time.Minute)))
+	assert.True(t, order.TimeRange{}.Contains(t0))
+	assert.True(t, order.TimeRange{To: t0}.Contains(t0.Add(-time.Hour)))
+}
+
+func TestPersistentManagerHistory(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("a", ordertest.Items(3))
+	store.Put("b", ordertest.Items(3))
+	audit := order.NewMemoryAuditStore()
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithAuditStore(audit))
+
+	alice := order.WithActor(ctx, "alice")
+	bob := order.WithActor(ctx, "bob")
+	start := time.Now()
+	_, err := pm.Apply(alice, "a", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	_, err = pm.Apply(bob, "a", order.Move[string]{Kind: order.MoveBottom, ItemID: "item-1"})
+	require.NoError(t, err)
+	_, err = pm.Apply(alice, "b", order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"})
+	require.NoError(t, err)
+	// Moves that change nothing are not recorded.
+	_, err = pm.Apply(bob, "a", order.Move[string]{Kind: order.MoveBottom, ItemID: "item-1"})
+	require.NoError(t, err)
+	_, err = pm.ApplyOrder(bob, "a", []string{"item-1", "item-2", "item-3"}, order.ReconcilePolicy[*ordertest.Item]{})
+	require.NoError(t, err)
+
+	moved := func(events []order.OrderChangedEvent) []string {
+		var ids []string
+		for _, event := range events {
+			ids = append(ids, event.ListID+":"+event.Actor+":"+event.Move.ItemID)
+		}
+		return ids
+	}
+	events, err := pm.History(ctx, order.AuditQuery{ListID: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a:alice:item-3", "a:bob:item-1", "a:bob:"}, moved(events))
+
+	events, err = pm.History(ctx, order.AuditQuery{Actor: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a:alice:item-3", "b:alice:item-1"}, moved(events))
+
+	// The history of an item holds its own moves and full orders that moved it.
+	events, err = pm.History(ctx, order.AuditQuery{ListID: "a", ItemID: "item-1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a:bob:item-1", "a:bob:"}, moved(events))
+	events, err = pm.History(ctx, order.AuditQuery{ListID: "a", ItemID: "item-2"})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+
+	events, err = pm.History(ctx, order.AuditQuery{ListID: "a", Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a:bob:item-1", "a:bob:"}, moved(events))
+
+	events, err = pm.History(ctx, order.AuditQuery{TimeRange: order.TimeRange{To: start}})
+	require.NoError(t, err)
+	assert.Empty(t, events)
+}
+
+type failingAudit struct {
+	*order.MemoryAuditStore
+}
+
+func (failingAudit) Append(context.Context, order.OrderChangedEvent) error {
+	return errors.New("audit down")
+}
+
+func TestPersistentManagerAuditErrors(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	_, err := pm.History(ctx, order.AuditQuery{})
+	assert.ErrorIs(t, err, order.ErrNoAuditStore)
+
+	pm = order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](),
+		order.WithAuditStore(failingAudit{order.NewMemoryAuditStore()}))
+	result, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	assert.ErrorContains(t, err, "audit down")
+	// The move was saved anyway.
+	assert.True(t, result.Changed)
+	items, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, "item-3", items[0].GetID())
+}
+
+func TestPersistentManagerOrderAt(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(4))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](),
+		order.WithAuditStore(order.NewMemoryAuditStore()))
+
+	before := time.Now()
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-4"})
+	require.NoError(t, err)
+	afterTop := time.Now()
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveBelow, ItemID: "item-1", TargetID: "item-3"})
+	require.NoError(t, err)
+	_, err = pm.ApplyOrder(ctx, "list", []string{"item-2", "item-1", "item-3", "item-4"}, order.ReconcilePolicy[*ordertest.Item]{})
+	require.NoError(t, err)
+
+	ids, err := pm.OrderAt(ctx, "list", before)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item-1", "item-2", "item-3", "item-4"}, ids)
+	ids, err = pm.OrderAt(ctx, "list", afterTop)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item-4", "item-1", "item-2", "item-3"}, ids)
+	ids, err = pm.OrderAt(ctx, "list", time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item-2", "item-1", "item-3", "item-4"}, ids)
+
+	// The earlier order can be restored.
+	ids, err = pm.OrderAt(ctx, "list", before)
+	require.NoError(t, err)
+	_, err = pm.ApplyOrder(ctx, "list", ids, order.ReconcilePolicy[*ordertest.Item]{})
+	require.NoError(t, err)
+	items, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3", "item-4")
+}
+
+func TestPersistentManagerOrderAtIncompleteTrail(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	audit := order.NewMemoryAuditStore()
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithAuditStore(audit))
+
+	_, err := pm.OrderAt(ctx, "list", time.Now())
+	require.NoError(t, err)
+	// A change that does not match the list cannot be reversed.
+	require.NoError(t, audit.Append(ctx, order.OrderChangedEvent{
+		ListID:  "list",
+		Changes: order.ChangeSet{{ItemID: "item-3", OldPosition: 1, NewPosition: 3}},
+		Time:    time.Now().Add(time.Hour),
+	}))
+	_, err = pm.OrderAt(ctx, "list", time.Now())
+	assert.ErrorIs(t, err, order.ErrInvalidOrder)
+
+	_, err = order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]()).OrderAt(ctx, "list", time.Now())
+	assert.ErrorIs(t, err, order.ErrNoAuditStore)
+}
+
+func TestApplyIdempotentRecordsKeyWhenAuditFails(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](),
+		order.WithAuditStore(failingAudit{order.NewMemoryAuditStore()}))
+
+	m := order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"}
+	result, err := pm.ApplyIdempotent(ctx, "list", "key", m)
+	var auditErr *order.AuditError
+	require.ErrorAs(t, err, &auditErr)
+	assert.Equal(t, result.OperationID, auditErr.OperationID)
+
+	// The retried request replays the saved move instead of moving it again.
+	again, err := pm.ApplyIdempotent(ctx, "list", "key", m)
+	require.NoError(t, err)
+	assert.True(t, again.Replayed)
+	assert.Equal(t, result.OperationID, again.OperationID)
+	items, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-2", "item-1", "item-3")
+}
@@ -0,0 +1,99 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// PositionWriter writes positions without a version check. Store adapters for
+// bulk jobs implement it; see ListScanner.
+type PositionWriter interface {
+	WritePositions(ctx context.Context, listID string, changes ChangeSet) error
+}
+
+// BatchOptions controls how bulk position updates are written, so that a
+// rebalance touching many rows does not saturate the database.
+type BatchOptions struct {
+	// Size is the maximum number of changes per write; the default is 500.
+	Size int
+	// Delay is the pause between two writes.
+	Delay time.Duration
+	// Wait, if set, is called before every write and may block to apply
+	// backpressure, for example until a rate limiter admits the write or
+	// replication has caught up. An error stops the job.
+	Wait func(ctx context.Context) error
+	// Progress, if set, is called after every write.
+	Progress func(BatchProgress)
+}
+
+// BatchProgress reports how far a bulk update has come.
+type BatchProgress struct {
+	// Batches is the number of writes so far.
+	Batches int
+	// Written is the number of changes written so far.
+	Written int
+	// Total is the number of changes to write, or 0 if it is not known up
+	// front.
+	Total int
+}
+
+// WriteBatched writes changes to a list in batches as configured by opts. If
+// it fails, the batches reported through Progress have been written.
+func WriteBatched(ctx context.Context, w PositionWriter, listID string, changes ChangeSet, opts BatchOptions) error {
+	bw := newBatchWriter(w, listID, opts)
+	bw.progress.Total = len(changes)
+	return bw.write(ctx, changes)
+}
+
+// batchWriter writes changes in batches and keeps the progress across calls.
+type batchWriter struct {
+	w        PositionWriter
+	listID   string
+	opts     BatchOptions
+	progress BatchProgress
+}
+
+func newBatchWriter(w PositionWriter, listID string, opts BatchOptions) *batchWriter {
+	if opts.Size <= 0 {
+		opts.Size = 500
+	}
+	return &batchWriter{w: w, listID: listID, opts: opts}
+}
+
+func (bw *batchWriter) write(ctx context.Context, changes ChangeSet) error {
+	for len(changes) > 0 {
+		if err := bw.pause(ctx); err != nil {
+			return err
+		}
+		n := min(bw.opts.Size, len(changes))
+		if err := bw.w.WritePositions(ctx, bw.listID, changes[:n]); err != nil {
+			return fmt.Errorf("write batch %d of %s: %w", bw.progress.Batches+1, bw.listID, err)
+		}
+		changes = changes[n:]
+		bw.progress.Batches++
+		bw.progress.Written += n
+		if bw.opts.Progress != nil {
+			bw.opts.Progress(bw.progress)
+		}
+	}
+	return nil
+}
+
+// pause waits before a write: for Delay if this is not the first write, then
+// for the Wait hook.
+func (bw *batchWriter) pause(ctx context.Context) error {
+	if bw.progress.Batches > 0 && bw.opts.Delay > 0 {
+		timer := time.NewTimer(bw.opts.Delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		}
+	}
+	if bw.opts.Wait != nil {
+		return bw.opts.Wait(ctx)
+	}
+	return ctx.Err()
+}
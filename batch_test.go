@@ -0,0 +1,80 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingWriter records the size of every batch written.
+type recordingWriter struct {
+	batches []int
+	fail    int
+}
+
+func (w *recordingWriter) WritePositions(ctx context.Context, listID string, changes order.ChangeSet) error {
+	if len(w.batches)+1 == w.fail {
+		return errors.New("disk full")
+	}
+	w.batches = append(w.batches, len(changes))
+	return nil
+}
+
+func changeSet(n int) order.ChangeSet {
+	changes := make(order.ChangeSet, n)
+	for i := range changes {
+		changes[i] = order.PositionChange{ItemID: fmt.Sprintf("item-%d", i+1), OldPosition: n - i, NewPosition: i + 1}
+	}
+	return changes
+}
+
+func TestWriteBatched(t *testing.T) {
+	w := &recordingWriter{}
+	var progress []order.BatchProgress
+	waits := 0
+	err := order.WriteBatched(context.Background(), w, "list", changeSet(7), order.BatchOptions{
+		Size:     3,
+		Delay:    time.Millisecond,
+		Wait:     func(context.Context) error { waits++; return nil },
+		Progress: func(p order.BatchProgress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []int{3, 3, 1}, w.batches)
+	assert.Equal(t, 3, waits)
+	assert.Equal(t, []order.BatchProgress{
+		{Batches: 1, Written: 3, Total: 7},
+		{Batches: 2, Written: 6, Total: 7},
+		{Batches: 3, Written: 7, Total: 7},
+	}, progress)
+}
+
+func TestWriteBatchedStops(t *testing.T) {
+	w := &recordingWriter{fail: 2}
+	err := order.WriteBatched(context.Background(), w, "list", changeSet(5), order.BatchOptions{Size: 2})
+	assert.ErrorContains(t, err, "batch 2")
+	assert.Equal(t, []int{2}, w.batches)
+
+	w = &recordingWriter{}
+	limited := errors.New("rate limited")
+	err = order.WriteBatched(context.Background(), w, "list", changeSet(5), order.BatchOptions{
+		Wait: func(context.Context) error { return limited },
+	})
+	assert.ErrorIs(t, err, limited)
+	assert.Empty(t, w.batches)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	err = order.WriteBatched(ctx, w, "list", changeSet(4), order.BatchOptions{
+		Size:     1,
+		Delay:    time.Hour,
+		Progress: func(order.BatchProgress) { cancel() },
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Equal(t, []int{1}, w.batches)
+}
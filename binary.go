@@ -0,0 +1,184 @@
+package order
+
+import (
+	"encoding/binary"
+	"fmt"
+	"time"
+)
+
+// The binary encodings start with a format byte. IDs are front-coded: each ID
+// stores the length of the prefix it shares with the previous ID followed by
+// the rest, which keeps lists of IDs like "item-1041" small.
+const (
+	snapshotFormat  byte = 1
+	changeSetFormat byte = 2
+)
+
+// MarshalBinary encodes the snapshot compactly, for lists too large to sync as JSON.
+func (s OrderSnapshot) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 16+len(s.IDs)*8)
+	buf = append(buf, snapshotFormat)
+	var at int64
+	if !s.TakenAt.IsZero() {
+		at = s.TakenAt.UnixNano()
+	}
+	buf = binary.AppendVarint(buf, at)
+	buf = binary.AppendUvarint(buf, uint64(len(s.IDs)))
+	prev := ""
+	for _, id := range s.IDs {
+		buf = appendID(buf, prev, id)
+		prev = id
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a snapshot encoded with MarshalBinary. It fails with
+// ErrInvalidEncoding if data is malformed.
+func (s *OrderSnapshot) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.byte() != snapshotFormat {
+		return fmt.Errorf("OrderSnapshot: unknown format: %w", ErrInvalidEncoding)
+	}
+	at := d.varint()
+	n := d.count()
+	ids := make([]string, 0, n)
+	prev := ""
+	for i := 0; i < n && d.err == nil; i++ {
+		prev = d.id(prev)
+		ids = append(ids, prev)
+	}
+	if err := d.finish("OrderSnapshot"); err != nil {
+		return err
+	}
+	*s = OrderSnapshot{IDs: ids}
+	if at != 0 {
+		s.TakenAt = time.Unix(0, at)
+	}
+	return nil
+}
+
+// MarshalBinary encodes the change set compactly. New positions are stored as
+// the distance from the old ones, which is small for most moves.
+func (cs ChangeSet) MarshalBinary() ([]byte, error) {
+	buf := make([]byte, 0, 8+len(cs)*12)
+	buf = append(buf, changeSetFormat)
+	buf = binary.AppendUvarint(buf, uint64(len(cs)))
+	prev := ""
+	for _, change := range cs {
+		buf = appendID(buf, prev, change.ItemID)
+		buf = binary.AppendVarint(buf, int64(change.OldPosition))
+		buf = binary.AppendVarint(buf, int64(change.NewPosition-change.OldPosition))
+		prev = change.ItemID
+	}
+	return buf, nil
+}
+
+// UnmarshalBinary decodes a change set encoded with MarshalBinary. It fails with
+// ErrInvalidEncoding if data is malformed.
+func (cs *ChangeSet) UnmarshalBinary(data []byte) error {
+	d := decoder{data: data}
+	if d.byte() != changeSetFormat {
+		return fmt.Errorf("ChangeSet: unknown format: %w", ErrInvalidEncoding)
+	}
+	n := d.count()
+	var changes ChangeSet
+	if n > 0 {
+		changes = make(ChangeSet, 0, n)
+	}
+	prev := ""
+	for i := 0; i < n && d.err == nil; i++ {
+		prev = d.id(prev)
+		old := int(d.varint())
+		changes = append(changes, PositionChange{ItemID: prev, OldPosition: old, NewPosition: old + int(d.varint())})
+	}
+	if err := d.finish("ChangeSet"); err != nil {
+		return err
+	}
+	*cs = changes
+	return nil
+}
+
+func appendID(buf []byte, prev, id string) []byte {
+	shared := 0
+	for shared < len(prev) && shared < len(id) && prev[shared] == id[shared] {
+		shared++
+	}
+	buf = binary.AppendUvarint(buf, uint64(shared))
+	buf = binary.AppendUvarint(buf, uint64(len(id)-shared))
+	return append(buf, id[shared:]...)
+}
+
+// decoder reads the binary encodings, remembering the first error.
+type decoder struct {
+	data []byte
+	err  error
+}
+
+func (d *decoder) fail() {
+	if d.err == nil {
+		d.err = ErrInvalidEncoding
+	}
+	d.data = nil
+}
+
+func (d *decoder) byte() byte {
+	if len(d.data) == 0 {
+		d.fail()
+		return 0
+	}
+	b := d.data[0]
+	d.data = d.data[1:]
+	return b
+}
+
+func (d *decoder) uvarint() uint64 {
+	v, n := binary.Uvarint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+func (d *decoder) varint() int64 {
+	v, n := binary.Varint(d.data)
+	if n <= 0 {
+		d.fail()
+		return 0
+	}
+	d.data = d.data[n:]
+	return v
+}
+
+// count reads a length, rejecting counts that cannot fit in the remaining data.
+func (d *decoder) count() int {
+	n := d.uvarint()
+	if n > uint64(len(d.data)) {
+		d.fail()
+		return 0
+	}
+	return int(n)
+}
+
+func (d *decoder) id(prev string) string {
+	shared := d.uvarint()
+	rest := d.uvarint()
+	if d.err != nil || shared > uint64(len(prev)) || rest > uint64(len(d.data)) {
+		d.fail()
+		return ""
+	}
+	id := prev[:shared] + string(d.data[:rest])
+	d.data = d.data[rest:]
+	return id
+}
+
+func (d *decoder) finish(what string) error {
+	if d.err == nil && len(d.data) > 0 {
+		d.err = ErrInvalidEncoding
+	}
+	if d.err != nil {
+		return fmt.Errorf("%s: %w", what, d.err)
+	}
+	return nil
+}
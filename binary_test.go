@@ -0,0 +1,64 @@
+package order_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotBinaryRoundTrip(t *testing.T) {
+	ids := make([]string, 1000)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("item-%d", i+1)
+	}
+	snap := order.OrderSnapshot{IDs: ids, TakenAt: time.Unix(1700000000, 123)}
+
+	data, err := snap.MarshalBinary()
+	require.NoError(t, err)
+	jsonData, err := json.Marshal(snap)
+	require.NoError(t, err)
+	assert.Less(t, len(data), len(jsonData)/3)
+
+	var decoded order.OrderSnapshot
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, snap.IDs, decoded.IDs)
+	assert.True(t, snap.TakenAt.Equal(decoded.TakenAt))
+
+	empty, err := order.OrderSnapshot{}.MarshalBinary()
+	require.NoError(t, err)
+	require.NoError(t, decoded.UnmarshalBinary(empty))
+	assert.Empty(t, decoded.IDs)
+	assert.True(t, decoded.TakenAt.IsZero())
+}
+
+func TestChangeSetBinaryRoundTrip(t *testing.T) {
+	changes := order.ChangeSet{
+		{ItemID: "item-10", OldPosition: 10, NewPosition: 1},
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+		{ItemID: "other", OldPosition: 2, NewPosition: 3},
+	}
+	data, err := changes.MarshalBinary()
+	require.NoError(t, err)
+
+	var decoded order.ChangeSet
+	require.NoError(t, decoded.UnmarshalBinary(data))
+	assert.Equal(t, changes, decoded)
+}
+
+func TestBinaryRejectsMalformedData(t *testing.T) {
+	data, err := order.ChangeSet{{ItemID: "a", OldPosition: 1, NewPosition: 2}}.MarshalBinary()
+	require.NoError(t, err)
+
+	var changes order.ChangeSet
+	assert.ErrorIs(t, changes.UnmarshalBinary(data[:len(data)-1]), order.ErrInvalidEncoding)
+	assert.ErrorIs(t, changes.UnmarshalBinary(append(data, 0)), order.ErrInvalidEncoding)
+	var snap order.OrderSnapshot
+	assert.ErrorIs(t, snap.UnmarshalBinary(data), order.ErrInvalidEncoding, "wrong format byte")
+	assert.ErrorIs(t, snap.UnmarshalBinary(nil), order.ErrInvalidEncoding)
+}
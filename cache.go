@@ -0,0 +1,171 @@
+package order
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Cache holds loaded lists for a CachingStore. Implementations must be safe
+// for concurrent use; a shared cache such as Redis can implement it to share
+// lists between processes.
+type Cache[T Orderable] interface {
+	// Get returns the cached list, if present and not expired.
+	Get(ctx context.Context, listID string) (CachedList[T], bool)
+	// Set caches a list for ttl. A ttl of 0 means no expiry.
+	Set(ctx context.Context, listID string, list CachedList[T], ttl time.Duration)
+	// Delete removes a list from the cache.
+	Delete(ctx context.Context, listID string)
+}
+
+// CachedList is a list as returned by Store.Load.
+type CachedList[T Orderable] struct {
+	Items   []T
+	Version int64
+}
+
+// CachingStore is a read-through cache in front of a Store. Load serves lists
+// from the cache and loads them from the underlying Store on a miss. Every
+// SavePositions call, successful or not, invalidates the cached list, so the
+// next Load sees the new version. Cached items are copies (see Cloner), so
+// callers that move the items they loaded do not change the cache.
+type CachingStore[T Orderable] struct {
+	store Store[T]
+	cache Cache[T]
+	ttl   time.Duration
+
+	// loads holds the lists being loaded from store, so that a Load that
+	// raced with a save does not cache the list it loaded before. Entries are
+	// removed when the last Load of a list returns.
+	mu    sync.Mutex
+	loads map[string]*cacheLoad
+}
+
+// cacheLoad tracks the running Loads of a list.
+type cacheLoad struct {
+	running int
+	// generation counts the invalidations of the list while Loads ran.
+	generation uint64
+}
+
+var _ Store[Orderable] = (*CachingStore[Orderable])(nil)
+
+// NewCachingStore wraps store with cache, keeping lists for ttl. A ttl of 0
+// keeps them until they are invalidated.
+func NewCachingStore[T Orderable](store Store[T], cache Cache[T], ttl time.Duration) *CachingStore[T] {
+	return &CachingStore[T]{store: store, cache: cache, ttl: ttl, loads: make(map[string]*cacheLoad)}
+}
+
+// Load implements Store. The returned items are copies of the cached ones.
+func (s *CachingStore[T]) Load(ctx context.Context, listID string) ([]T, int64, error) {
+	if list, ok := s.cache.Get(ctx, listID); ok {
+		return copyItems(list.Items, 0), list.Version, nil
+	}
+	s.mu.Lock()
+	load, ok := s.loads[listID]
+	if !ok {
+		load = &cacheLoad{}
+		s.loads[listID] = load
+	}
+	load.running++
+	generation := load.generation
+	s.mu.Unlock()
+
+	items, version, err := s.store.Load(ctx, listID)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if load.running--; load.running == 0 {
+		delete(s.loads, listID)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	if load.generation == generation {
+		s.cache.Set(ctx, listID, CachedList[T]{Items: copyItems(items, 0), Version: version}, s.ttl)
+	}
+	return items, version, nil
+}
+
+// SavePositions implements Store.
+func (s *CachingStore[T]) SavePositions(ctx context.Context, listID string, expectedVersion int64, changes ChangeSet) (int64, error) {
+	defer s.Invalidate(ctx, listID)
+	return s.store.SavePositions(ctx, listID, expectedVersion, changes)
+}
+
+// Unwrap implements Wrapper.
+func (s *CachingStore[T]) Unwrap() Store[T] {
+	return s.store
+}
+
+// Invalidate removes a list from the cache, for example after it was changed
+// outside this store. A Load of the list that is still running when
+// Invalidate is called does not cache its result. Other processes sharing
+// the Cache are not covered by this and should rely on the ttl.
+func (s *CachingStore[T]) Invalidate(ctx context.Context, listID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if load, ok := s.loads[listID]; ok {
+		load.generation++
+	}
+	s.cache.Delete(ctx, listID)
+}
+
+// MemoryCache is an in-process Cache.
+type MemoryCache[T Orderable] struct {
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry[T]
+}
+
+var _ Cache[Orderable] = (*MemoryCache[Orderable])(nil)
+
+type memoryCacheEntry[T Orderable] struct {
+	list    CachedList[T]
+	expires time.Time
+}
+
+// NewMemoryCache creates an empty MemoryCache.
+func NewMemoryCache[T Orderable]() *MemoryCache[T] {
+	return &MemoryCache[T]{entries: make(map[string]memoryCacheEntry[T])}
+}
+
+// Get implements Cache. Expired entries are removed when they are read.
+func (c *MemoryCache[T]) Get(ctx context.Context, listID string) (CachedList[T], bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[listID]
+	if !ok {
+		return CachedList[T]{}, false
+	}
+	if !entry.expires.IsZero() && !time.Now().Before(entry.expires) {
+		delete(c.entries, listID)
+		return CachedList[T]{}, false
+	}
+	return entry.list, true
+}
+
+// Set implements Cache.
+func (c *MemoryCache[T]) Set(ctx context.Context, listID string, list CachedList[T], ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry := memoryCacheEntry[T]{list: list}
+	if ttl > 0 {
+		entry.expires = time.Now().Add(ttl)
+	}
+	c.entries[listID] = entry
+}
+
+// Delete implements Cache.
+func (c *MemoryCache[T]) Delete(ctx context.Context, listID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.entries, listID)
+}
+
+// Len returns the number of cached lists, including expired ones that have
+// not been read since they expired.
+func (c *MemoryCache[T]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.entries)
+}
@@ -0,0 +1,155 @@
+package order_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingStore counts the Load calls that reach the underlying store.
+type countingStore struct {
+	*ordertest.Store[*ordertest.Item]
+	loads int
+}
+
+func (s *countingStore) Load(ctx context.Context, listID string) ([]*ordertest.Item, int64, error) {
+	s.loads++
+	return s.Store.Load(ctx, listID)
+}
+
+func TestCachingStore(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingStore{Store: ordertest.NewStore[*ordertest.Item]()}
+	backend.Put("list", ordertest.Items(3))
+	cache := order.NewMemoryCache[*ordertest.Item]()
+	store := order.NewCachingStore[*ordertest.Item](backend, cache, 0)
+
+	for range 3 {
+		items, version, err := store.Load(ctx, "list")
+		require.NoError(t, err)
+		assert.Len(t, items, 3)
+		assert.Equal(t, int64(1), version)
+	}
+	assert.Equal(t, 1, backend.loads)
+
+	version, err := store.SavePositions(ctx, "list", 1, order.ChangeSet{{ItemID: "item-1", OldPosition: 1, NewPosition: 4}})
+	require.NoError(t, err)
+	assert.Equal(t, 0, cache.Len())
+
+	items, loaded, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, version, loaded)
+	ordertest.AssertOrder(t, items, "item-2", "item-3", "item-1")
+	assert.Equal(t, 2, backend.loads)
+
+	store.Invalidate(ctx, "list")
+	_, _, err = store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, 3, backend.loads)
+
+	_, _, err = store.Load(ctx, "missing")
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+	assert.Equal(t, 1, cache.Len())
+}
+
+func TestCachingStoreInvalidatesOnConflict(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingStore{Store: ordertest.NewStore[*ordertest.Item]()}
+	backend.Put("list", ordertest.Items(2))
+	store := order.NewCachingStore[*ordertest.Item](backend, order.NewMemoryCache[*ordertest.Item](), 0)
+
+	_, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	_, err = store.SavePositions(ctx, "list", 7, nil)
+	assert.ErrorIs(t, err, order.ErrVersionConflict)
+
+	_, _, err = store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, 2, backend.loads)
+
+	// Once no Load races with an invalidation, lists are cached again.
+	_, _, err = store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, 2, backend.loads)
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	ctx := context.Background()
+	cache := order.NewMemoryCache[*ordertest.Item]()
+	cache.Set(ctx, "list", order.CachedList[*ordertest.Item]{Version: 1}, 100*time.Millisecond)
+	_, ok := cache.Get(ctx, "list")
+	assert.True(t, ok)
+
+	time.Sleep(150 * time.Millisecond)
+	_, ok = cache.Get(ctx, "list")
+	assert.False(t, ok)
+	assert.Equal(t, 0, cache.Len())
+}
+
+func TestCachingStoreCopiesItems(t *testing.T) {
+	ctx := context.Background()
+	backend := ordertest.NewStore[*ordertest.Item]()
+	backend.Put("list", ordertest.Items(3))
+	store := order.NewCachingStore[*ordertest.Item](backend, order.NewMemoryCache[*ordertest.Item](), 0)
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	items[0].SetPosition(42)
+	cached, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, 1, cached[0].GetPosition())
+	cached[1].SetPosition(42)
+	cached, _, err = store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, 2, cached[1].GetPosition())
+}
+
+// blockingStore holds Load until release is closed.
+type blockingStore struct {
+	*countingStore
+	loading chan struct{}
+	release chan struct{}
+}
+
+func (s *blockingStore) Load(ctx context.Context, listID string) ([]*ordertest.Item, int64, error) {
+	items, version, err := s.countingStore.Load(ctx, listID)
+	close(s.loading)
+	<-s.release
+	return items, version, err
+}
+
+func TestCachingStoreDoesNotCacheRacingLoad(t *testing.T) {
+	ctx := context.Background()
+	backend := &countingStore{Store: ordertest.NewStore[*ordertest.Item]()}
+	backend.Put("list", ordertest.Items(3))
+	blocking := &blockingStore{countingStore: backend, loading: make(chan struct{}), release: make(chan struct{})}
+	store := order.NewCachingStore[*ordertest.Item](blocking, order.NewMemoryCache[*ordertest.Item](), 0)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _, err := store.Load(ctx, "list")
+		assert.NoError(t, err)
+	}()
+	<-blocking.loading
+	store.Invalidate(ctx, "list")
+	close(blocking.release)
+	<-done
+
+	// The list loaded before the invalidation was not cached.
+	blocking.loading = make(chan struct{})
+	_, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, 2, backend.loads)
+
+	// Once no Load races with an invalidation, lists are cached again.
+	_, _, err = store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, 2, backend.loads)
+}
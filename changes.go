@@ -0,0 +1,34 @@
+package order
+
+// Result describes the outcome of a move.
+type Result[T any] struct {
+	// Changed reports whether any position was updated. When false, callers can
+	// skip persistence, event broadcasting and cache invalidation.
+	Changed bool
+	// OldPosition and NewPosition are the positions of the moved item before and
+	// after the move.
+	OldPosition int
+	NewPosition int
+	// Affected holds every item whose position was updated, in slice order.
+	Affected []T
+}
+
+// PositionChange records the position of an item before and after an operation.
+type PositionChange struct {
+	ItemID      string
+	OldPosition int
+	NewPosition int
+}
+
+// ChangeSet lists the items whose positions were changed by an operation, in
+// slice order. It is the delta a caller needs to persist.
+type ChangeSet []PositionChange
+
+// IDs returns the IDs of the changed items.
+func (cs ChangeSet) IDs() []string {
+	ids := make([]string, len(cs))
+	for i, change := range cs {
+		ids[i] = change.ItemID
+	}
+	return ids
+}
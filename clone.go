@@ -0,0 +1,56 @@
+package order
+
+import "reflect"
+
+// Cloner can be implemented by items that know how to copy themselves. The copy
+// must not share mutable state, including the position, with the original.
+type Cloner[T any] interface {
+	Clone() T
+}
+
+// Clone returns an independent copy of items in the same order, calling Clone
+// on every item. Positions are copied as they are.
+func Clone[T interface {
+	Orderable
+	Cloner[T]
+}](items []T) []T {
+	return CloneFunc(items, T.Clone)
+}
+
+// CloneFunc is like Clone but copies every item with clone, for item types that
+// do not implement Cloner.
+func CloneFunc[T Orderable](items []T, clone func(T) T) []T {
+	if items == nil {
+		return nil
+	}
+	clones := make([]T, len(items))
+	for i, item := range items {
+		clones[i] = clone(item)
+	}
+	return clones
+}
+
+// CopyItem returns a copy of item whose position can be changed without
+// touching item, for stores and caches that hand out items. It calls Clone if
+// item implements Cloner, copies the pointed-to value shallowly if item is a
+// pointer, and returns item itself otherwise.
+func CopyItem[T any](item T) T {
+	return copyItem(item)
+}
+
+// copyItem returns a copy of item whose position can be changed without
+// touching item: the result of Clone if item implements Cloner, a shallow copy
+// of the pointed-to value if it is a pointer, and item itself otherwise, since
+// other values are copied by assignment.
+func copyItem[T any](item T) T {
+	if c, ok := any(item).(Cloner[T]); ok {
+		return c.Clone()
+	}
+	v := reflect.ValueOf(item)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return item
+	}
+	copied := reflect.New(v.Elem().Type())
+	copied.Elem().Set(v.Elem())
+	return copied.Interface().(T)
+}
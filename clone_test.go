@@ -0,0 +1,52 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// ClonableItem extends TestItem with the Cloner interface.
+type ClonableItem struct {
+	TestItem
+	Tags []string
+}
+
+func (ci *ClonableItem) Clone() *ClonableItem {
+	clone := *ci
+	clone.Tags = append([]string(nil), ci.Tags...)
+	return &clone
+}
+
+func TestClone(t *testing.T) {
+	items := make([]*ClonableItem, 3)
+	for i, item := range createTestItems(3) {
+		items[i] = &ClonableItem{TestItem: *item, Tags: []string{"tag"}}
+	}
+
+	clones := order.Clone(items)
+	assert.Equal(t, items, clones)
+	for i := range items {
+		assert.NotSame(t, items[i], clones[i])
+	}
+
+	clones[0].Tags[0] = "changed"
+	_, err := order.NewOrderManager[*ClonableItem]().Bottom(clones, clones[0].GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, "tag", items[0].Tags[0])
+	assert.Equal(t, 1, items[0].GetPosition())
+	assert.Equal(t, items[0].GetID(), clones[2].GetID())
+}
+
+func TestCloneFunc(t *testing.T) {
+	items := createTestItems(2)
+	clones := order.CloneFunc(items, func(item *TestItem) *TestItem {
+		clone := *item
+		return &clone
+	})
+	assert.Equal(t, items, clones)
+	assert.NotSame(t, items[0], clones[0])
+	assert.Nil(t, order.CloneFunc[*TestItem](nil, nil))
+}
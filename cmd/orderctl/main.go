@@ -0,0 +1,274 @@
+// Command orderctl reorders the records of a JSON array or CSV file.
+//
+// Usage:
+//
+//	orderctl move --id X (--up | --down | --top | --bottom | --to N | --above Y | --below Y) [flags] file
+//
+// The records are taken in file order, the move is applied, the position
+// field of every record is renumbered starting from 1 and the result is
+// written to stdout (or to the file given with -o). Pass "-" as the file to
+// read from stdin.
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/yacobolo/order"
+)
+
+const usage = `usage: orderctl move --id X (--up | --down | --top | --bottom | --to N | --above Y | --below Y) [flags] file`
+
+// record is a single JSON object or CSV row made orderable.
+type record struct {
+	id       string
+	position int
+	fields   map[string]any // set for JSON input
+	row      []string       // set for CSV input
+}
+
+func (r *record) GetID() string {
+	return r.id
+}
+
+func (r *record) GetPosition() int {
+	return r.position
+}
+
+func (r *record) SetPosition(position int) {
+	r.position = position
+}
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "orderctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string, stdin io.Reader, stdout io.Writer) error {
+	if len(args) == 0 || args[0] != "move" {
+		return errors.New(usage)
+	}
+
+	fs := flag.NewFlagSet("move", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var (
+		id       = fs.String("id", "", "ID of the record to move")
+		above    = fs.String("above", "", "move the record directly above the record with this ID")
+		below    = fs.String("below", "", "move the record directly below the record with this ID")
+		to       = fs.Int("to", 0, "move the record to this 1-based position")
+		up       = fs.Bool("up", false, "move the record up by one position")
+		down     = fs.Bool("down", false, "move the record down by one position")
+		top      = fs.Bool("top", false, "move the record to the first position")
+		bottom   = fs.Bool("bottom", false, "move the record to the last position")
+		idField  = fs.String("id-field", "id", "name of the ID field or column")
+		posField = fs.String("position-field", "position", "name of the position field or column")
+		format   = fs.String("format", "", "input format: json or csv (default: from file extension)")
+		output   = fs.String("o", "-", "output file, - for stdout")
+	)
+	if err := fs.Parse(args[1:]); err != nil {
+		return fmt.Errorf("%w\n%s", err, usage)
+	}
+	if *id == "" {
+		return fmt.Errorf("--id is required\n%s", usage)
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("exactly one input file is required\n%s", usage)
+	}
+
+	// --to is passed only if it was given, so that --to 0 is reported as an
+	// invalid position rather than as a missing move.
+	var position *int
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "to" {
+			position = to
+		}
+	})
+	move, err := selectMove(*id, *above, *below, position, *up, *down, *top, *bottom)
+	if err != nil {
+		return err
+	}
+
+	path := fs.Arg(0)
+	data, err := readInput(path, stdin)
+	if err != nil {
+		return err
+	}
+	if *format == "" {
+		*format = "json"
+		if strings.EqualFold(filepath.Ext(path), ".csv") {
+			*format = "csv"
+		}
+	}
+
+	var (
+		records []*record
+		encode  func(io.Writer, []*record) error
+	)
+	switch *format {
+	case "json":
+		records, err = decodeJSON(data, *idField)
+		encode = func(w io.Writer, records []*record) error { return encodeJSON(w, records, *posField) }
+	case "csv":
+		var header []string
+		header, records, err = decodeCSV(data, *idField)
+		encode = func(w io.Writer, records []*record) error { return encodeCSV(w, header, records, *posField) }
+	default:
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	if err != nil {
+		return err
+	}
+
+	om := order.NewOrderManager[*record]()
+	om.NormalizePositions(records)
+	if _, err := move(om, records); err != nil {
+		return err
+	}
+
+	if *output == "-" {
+		return encode(stdout, records)
+	}
+	var buf bytes.Buffer
+	if err := encode(&buf, records); err != nil {
+		return err
+	}
+	return os.WriteFile(*output, buf.Bytes(), 0o644)
+}
+
+type (
+	manager = order.OrderManager[*record]
+	result  = order.Result[*record]
+)
+
+// moveFunc applies a single move to the records.
+type moveFunc func(*manager, []*record) (result, error)
+
+// selectMove returns the move described by the flags, making sure exactly one
+// was requested. to is nil if --to was not given.
+func selectMove(id, above, below string, to *int, up, down, top, bottom bool) (moveFunc, error) {
+	var moves []moveFunc
+	if above != "" {
+		moves = append(moves, func(om *manager, rs []*record) (result, error) { return om.Above(rs, id, above) })
+	}
+	if below != "" {
+		moves = append(moves, func(om *manager, rs []*record) (result, error) { return om.Below(rs, id, below) })
+	}
+	if to != nil {
+		moves = append(moves, func(om *manager, rs []*record) (result, error) { return om.To(rs, id, *to) })
+	}
+	if up {
+		moves = append(moves, func(om *manager, rs []*record) (result, error) { return om.Up(rs, id) })
+	}
+	if down {
+		moves = append(moves, func(om *manager, rs []*record) (result, error) { return om.Down(rs, id) })
+	}
+	if top {
+		moves = append(moves, func(om *manager, rs []*record) (result, error) { return om.Top(rs, id) })
+	}
+	if bottom {
+		moves = append(moves, func(om *manager, rs []*record) (result, error) { return om.Bottom(rs, id) })
+	}
+	if len(moves) != 1 {
+		return nil, fmt.Errorf("exactly one of --up, --down, --top, --bottom, --to, --above or --below is required\n%s", usage)
+	}
+	return moves[0], nil
+}
+
+func readInput(path string, stdin io.Reader) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func decodeJSON(data []byte, idField string) ([]*record, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var objects []map[string]any
+	if err := dec.Decode(&objects); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+	records := make([]*record, len(objects))
+	for i, obj := range objects {
+		raw, ok := obj[idField]
+		if !ok || raw == nil {
+			return nil, fmt.Errorf("record %d has no %q field", i, idField)
+		}
+		records[i] = &record{id: fmt.Sprint(raw), fields: obj}
+	}
+	return records, nil
+}
+
+func encodeJSON(w io.Writer, records []*record, posField string) error {
+	objects := make([]map[string]any, len(records))
+	for i, r := range records {
+		r.fields[posField] = r.position
+		objects[i] = r.fields
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(objects)
+}
+
+func decodeCSV(data []byte, idColumn string) ([]string, []*record, error) {
+	rows, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("decode CSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil, errors.New("decode CSV: missing header row")
+	}
+	header := rows[0]
+	idIndex := columnIndex(header, idColumn)
+	if idIndex < 0 {
+		return nil, nil, fmt.Errorf("CSV has no %q column", idColumn)
+	}
+	records := make([]*record, len(rows)-1)
+	for i, row := range rows[1:] {
+		records[i] = &record{id: row[idIndex], row: row}
+	}
+	return header, records, nil
+}
+
+func encodeCSV(w io.Writer, header []string, records []*record, posColumn string) error {
+	posIndex := columnIndex(header, posColumn)
+	if posIndex < 0 {
+		header = append(header, posColumn)
+		posIndex = len(header) - 1
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	for _, r := range records {
+		for len(r.row) < len(header) {
+			r.row = append(r.row, "")
+		}
+		r.row[posIndex] = strconv.Itoa(r.position)
+		if err := cw.Write(r.row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+func columnIndex(header []string, name string) int {
+	for i, column := range header {
+		if column == name {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,93 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeTempFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o644))
+	return path
+}
+
+func TestMoveJSON(t *testing.T) {
+	path := writeTempFile(t, "list.json", `[
+		{"id": "a", "position": 1, "title": "A"},
+		{"id": "b", "position": 2, "title": "B"},
+		{"id": "c", "position": 3, "title": "C"}
+	]`)
+
+	var out bytes.Buffer
+	err := run([]string{"move", "--id", "c", "--above", "a", path}, nil, &out)
+	require.NoError(t, err)
+
+	var got []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Len(t, got, 3)
+	for i, want := range []string{"c", "a", "b"} {
+		assert.Equal(t, want, got[i]["id"])
+		assert.Equal(t, float64(i+1), got[i]["position"])
+	}
+	assert.Equal(t, "C", got[0]["title"])
+}
+
+func TestMoveCSV(t *testing.T) {
+	path := writeTempFile(t, "list.csv", "id,title\na,A\nb,B\nc,C\n")
+
+	var out bytes.Buffer
+	err := run([]string{"move", "--id", "a", "--bottom", path}, nil, &out)
+	require.NoError(t, err)
+
+	assert.Equal(t, "id,title,position\nb,B,1\nc,C,2\na,A,3\n", out.String())
+}
+
+func TestMoveStdinWithCustomFields(t *testing.T) {
+	in := strings.NewReader(`[{"key": 10, "rank": 1}, {"key": 20, "rank": 2}]`)
+
+	var out bytes.Buffer
+	err := run([]string{"move", "--id", "20", "--up", "--id-field", "key", "--position-field", "rank", "-"}, in, &out)
+	require.NoError(t, err)
+
+	var got []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &got))
+	assert.Equal(t, float64(20), got[0]["key"])
+	assert.Equal(t, float64(1), got[0]["rank"])
+}
+
+func TestMoveRequiresExactlyOneMove(t *testing.T) {
+	path := writeTempFile(t, "list.json", `[{"id": "a"}]`)
+
+	err := run([]string{"move", "--id", "a", path}, nil, &bytes.Buffer{})
+	assert.Error(t, err)
+
+	err = run([]string{"move", "--id", "a", "--top", "--bottom", path}, nil, &bytes.Buffer{})
+	assert.Error(t, err)
+}
+
+func TestMoveToZero(t *testing.T) {
+	path := writeTempFile(t, "list.json", `[{"id": "a"}, {"id": "b"}]`)
+
+	err := run([]string{"move", "--id", "a", "--to", "0", path}, nil, &bytes.Buffer{})
+	assert.ErrorIs(t, err, order.ErrInvalidPosition)
+
+	err = run([]string{"move", "--id", "a", "--to", "0", "--top", path}, nil, &bytes.Buffer{})
+	assert.ErrorContains(t, err, "exactly one of")
+}
+
+func TestMoveUnknownID(t *testing.T) {
+	path := writeTempFile(t, "list.json", `[{"id": "a"}, {"id": "b"}]`)
+
+	err := run([]string{"move", "--id", "x", "--top", path}, nil, &bytes.Buffer{})
+	assert.Error(t, err)
+}
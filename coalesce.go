@@ -0,0 +1,126 @@
+package order
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Coalescer merges rapid successive moves of the same item into a single move
+// before they reach the Store. A drag-and-drop client typically emits many
+// intermediate moves while an item is being dragged; only the last one matters.
+//
+// A move waits for window before it is applied. If another move of the same
+// item on the same list arrives within that time, it replaces the waiting move
+// and the window starts again. A move of any other item on the list applies
+// the waiting move first, so moves are still applied in submission order.
+// Only absolute moves (To, Top, Bottom, Above and Below) are coalesced; Up and
+// Down depend on where the item currently is and are never merged.
+//
+// Every Future of a merged move receives the result of the move that was
+// finally applied.
+type Coalescer[T Orderable] struct {
+	queue  *Queue[T]
+	window time.Duration
+
+	mu      sync.Mutex
+	pending map[string]*pendingMove[T]
+}
+
+type pendingMove[T Orderable] struct {
+	ctx     context.Context
+	move    Move[string]
+	futures []*Future[T]
+	timer   *time.Timer
+}
+
+// NewCoalescer creates a Coalescer that applies moves with manager after they
+// have been idle for window.
+func NewCoalescer[T Orderable](manager *PersistentManager[T], window time.Duration) *Coalescer[T] {
+	return &Coalescer[T]{queue: NewQueue(manager), window: window, pending: make(map[string]*pendingMove[T])}
+}
+
+// Submit queues m for the list and returns immediately. See Queue.Submit.
+func (c *Coalescer[T]) Submit(ctx context.Context, listID string, m Move[string]) *Future[T] {
+	future := &Future[T]{done: make(chan struct{})}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	p := c.pending[listID]
+	if p != nil && p.move.ItemID == m.ItemID && coalescable(m.Kind) {
+		p.timer.Stop()
+		p.ctx, p.move = ctx, m
+		p.futures = append(p.futures, future)
+		c.schedule(listID, p)
+		return future
+	}
+	if p != nil {
+		c.flushLocked(listID, p)
+	}
+	p = &pendingMove[T]{ctx: ctx, move: m, futures: []*Future[T]{future}}
+	if !coalescable(m.Kind) {
+		c.submitLocked(listID, p)
+		return future
+	}
+	c.pending[listID] = p
+	c.schedule(listID, p)
+	return future
+}
+
+// Apply submits m and waits for its result.
+func (c *Coalescer[T]) Apply(ctx context.Context, listID string, m Move[string]) (PersistedResult[T], error) {
+	return c.Submit(ctx, listID, m).Wait(ctx)
+}
+
+// schedule starts the window of p. The timer replaces any earlier one, so a
+// timer that fires after p was replaced or flushed finds a different pending
+// move and does nothing.
+func (c *Coalescer[T]) schedule(listID string, p *pendingMove[T]) {
+	var timer *time.Timer
+	timer = time.AfterFunc(c.window, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.pending[listID] == p && p.timer == timer {
+			c.flushLocked(listID, p)
+		}
+	})
+	p.timer = timer
+}
+
+func (c *Coalescer[T]) flushLocked(listID string, p *pendingMove[T]) {
+	p.timer.Stop()
+	delete(c.pending, listID)
+	c.submitLocked(listID, p)
+}
+
+// submitLocked hands p to the queue. It is called with c.mu held, which keeps
+// the queue order equal to the submission order.
+func (c *Coalescer[T]) submitLocked(listID string, p *pendingMove[T]) {
+	queued := c.queue.Submit(p.ctx, listID, p.move)
+	go func() {
+		<-queued.Done()
+		for _, future := range p.futures {
+			future.resolve(queued.result, queued.err)
+		}
+	}()
+}
+
+// Flush applies every waiting move without waiting for its window to end.
+func (c *Coalescer[T]) Flush() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for listID, p := range c.pending {
+		c.flushLocked(listID, p)
+	}
+}
+
+// Close applies every waiting move, stops accepting moves and waits until all
+// of them have been applied, or until ctx is done.
+func (c *Coalescer[T]) Close(ctx context.Context) error {
+	c.Flush()
+	return c.queue.Close(ctx)
+}
+
+func coalescable(kind MoveKind) bool {
+	return kind != MoveUp && kind != MoveDown
+}
@@ -0,0 +1,83 @@
+package order_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newCoalescer(t *testing.T, n int, window time.Duration) (*order.Coalescer[*ordertest.Item], *ordertest.Store[*ordertest.Item]) {
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(n))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	c := order.NewCoalescer(pm, window)
+	t.Cleanup(func() { c.Close(context.Background()) })
+	return c, store
+}
+
+func TestCoalescerMergesMovesOfOneItem(t *testing.T) {
+	ctx := context.Background()
+	c, store := newCoalescer(t, 4, time.Hour)
+
+	futures := []*order.Future[*ordertest.Item]{
+		c.Submit(ctx, "list", order.Move[string]{Kind: order.MoveTo, ItemID: "item-1", Position: 2}),
+		c.Submit(ctx, "list", order.Move[string]{Kind: order.MoveTo, ItemID: "item-1", Position: 3}),
+		c.Submit(ctx, "list", order.Move[string]{Kind: order.MoveBelow, ItemID: "item-1", TargetID: "item-4"}),
+	}
+	c.Flush()
+	for _, future := range futures {
+		result, err := future.Wait(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, 4, result.NewPosition)
+	}
+	assert.Equal(t, 1, store.Saves())
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-2", "item-3", "item-4", "item-1")
+}
+
+func TestCoalescerKeepsSubmissionOrder(t *testing.T) {
+	ctx := context.Background()
+	c, store := newCoalescer(t, 3, time.Hour)
+
+	c.Submit(ctx, "list", order.Move[string]{Kind: order.MoveBottom, ItemID: "item-1"})
+	c.Submit(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	up := c.Submit(ctx, "list", order.Move[string]{Kind: order.MoveUp, ItemID: "item-2"})
+	_, err := up.Wait(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 3, store.Saves())
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-2", "item-3", "item-1")
+}
+
+func TestCoalescerDoesNotMergeRelativeMoves(t *testing.T) {
+	ctx := context.Background()
+	c, store := newCoalescer(t, 3, time.Hour)
+
+	c.Submit(ctx, "list", order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"})
+	_, err := c.Apply(ctx, "list", order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"})
+	require.NoError(t, err)
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-2", "item-3", "item-1")
+}
+
+func TestCoalescerAppliesAfterWindow(t *testing.T) {
+	ctx := context.Background()
+	c, store := newCoalescer(t, 3, 10*time.Millisecond)
+
+	result, err := c.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Equal(t, 1, store.Saves())
+}
@@ -0,0 +1,182 @@
+package order
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// OrderedCollection holds a slice of items whose order is managed by an OrderManager.
+// The verbs are available as methods, so callers need not pass the slice to
+// every call, and the collection keeps an index by ID and a version.
+type OrderedCollection[T Orderable] struct {
+	items    []T
+	manager  *OrderManager[T]
+	archived []archivedItem[T]
+	excluded []excludedItem[T]
+	version  int64
+	// index maps IDs to slice indices; nil until Get needs it after a change.
+	index map[string]int
+	hooks []func(Result[T])
+}
+
+// NewOrderedCollection creates a collection from items in their current slice order
+// and normalizes their positions.
+func NewOrderedCollection[T Orderable](items []T) *OrderedCollection[T] {
+	c := &OrderedCollection[T]{items: items, manager: NewOrderManager[T]()}
+	c.manager.NormalizePositions(c.items)
+	return c
+}
+
+// Items returns the items of the collection in order.
+func (c *OrderedCollection[T]) Items() []T {
+	return c.items
+}
+
+// Len returns the number of items in the collection.
+func (c *OrderedCollection[T]) Len() int {
+	return len(c.items)
+}
+
+// Version counts the changes made to the collection since it was created.
+func (c *OrderedCollection[T]) Version() int64 {
+	return c.version
+}
+
+// Get returns the item with itemID, looked up in the index of the collection.
+func (c *OrderedCollection[T]) Get(itemID string) (T, bool) {
+	if c.index == nil {
+		c.index = c.manager.IndexMap(c.items)
+	}
+	i, ok := c.index[itemID]
+	if !ok {
+		var zero T
+		return zero, false
+	}
+	return c.items[i], true
+}
+
+// OnChange registers hook to be called after every move that changed the
+// collection, with its Result.
+func (c *OrderedCollection[T]) OnChange(hook func(Result[T])) {
+	c.hooks = append(c.hooks, hook)
+}
+
+// Up moves an item up by one position, see KeyedManager.Up.
+func (c *OrderedCollection[T]) Up(itemID string) (Result[T], error) {
+	return c.Apply(Move[string]{Kind: MoveUp, ItemID: itemID})
+}
+
+// Down moves an item down by one position, see KeyedManager.Down.
+func (c *OrderedCollection[T]) Down(itemID string) (Result[T], error) {
+	return c.Apply(Move[string]{Kind: MoveDown, ItemID: itemID})
+}
+
+// To moves an item to a 1-based position, see KeyedManager.To.
+func (c *OrderedCollection[T]) To(itemID string, position int) (Result[T], error) {
+	return c.Apply(Move[string]{Kind: MoveTo, ItemID: itemID, Position: position})
+}
+
+// Top moves an item to the first position.
+func (c *OrderedCollection[T]) Top(itemID string) (Result[T], error) {
+	return c.Apply(Move[string]{Kind: MoveTop, ItemID: itemID})
+}
+
+// Bottom moves an item to the last position.
+func (c *OrderedCollection[T]) Bottom(itemID string) (Result[T], error) {
+	return c.Apply(Move[string]{Kind: MoveBottom, ItemID: itemID})
+}
+
+// Above moves an item directly above the target item.
+func (c *OrderedCollection[T]) Above(itemID, targetID string) (Result[T], error) {
+	return c.Apply(Move[string]{Kind: MoveAbove, ItemID: itemID, TargetID: targetID})
+}
+
+// Below moves an item directly below the target item.
+func (c *OrderedCollection[T]) Below(itemID, targetID string) (Result[T], error) {
+	return c.Apply(Move[string]{Kind: MoveBelow, ItemID: itemID, TargetID: targetID})
+}
+
+// Apply performs the move m on the collection.
+func (c *OrderedCollection[T]) Apply(m Move[string]) (Result[T], error) {
+	result, err := c.manager.Apply(c.items, m)
+	if err == nil {
+		c.moved(result)
+	}
+	return result, err
+}
+
+// MoveMany moves several items as a block, see KeyedManager.MoveMany.
+func (c *OrderedCollection[T]) MoveMany(itemIDs []string, position int) (Result[T], error) {
+	result, err := c.manager.MoveMany(c.items, itemIDs, position)
+	if err == nil {
+		c.moved(result)
+	}
+	return result, err
+}
+
+// moved records a move and runs the hooks if it changed the collection.
+func (c *OrderedCollection[T]) moved(result Result[T]) {
+	if !result.Changed {
+		return
+	}
+	c.changed()
+	for _, hook := range c.hooks {
+		hook(result)
+	}
+}
+
+// changed bumps the version and drops the index after a change.
+func (c *OrderedCollection[T]) changed() {
+	c.version++
+	c.index = nil
+}
+
+// MarshalJSON serializes the items as a JSON array in order.
+func (c *OrderedCollection[T]) MarshalJSON() ([]byte, error) {
+	if c.items == nil {
+		return []byte("[]"), nil
+	}
+	return json.Marshal(c.items)
+}
+
+// UnmarshalJSON decodes a JSON array of items, orders them by position and
+// normalizes their positions. Positions must be positive and unique; gaps are closed.
+// The decoded items replace the collection, and its archived and excluded items
+// are dropped, since they belong to the items it held before.
+func (c *OrderedCollection[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	if err := json.Unmarshal(data, &items); err != nil {
+		return err
+	}
+
+	seen := make(map[int]struct{}, len(items))
+	for _, item := range items {
+		position := item.GetPosition()
+		if position < 1 {
+			return fmt.Errorf("UnmarshalJSON: item %s has position %d: %w", item.GetID(), position, ErrInvalidPosition)
+		}
+		if _, ok := seen[position]; ok {
+			return fmt.Errorf("UnmarshalJSON: position %d is used more than once: %w", position, ErrInvalidPosition)
+		}
+		seen[position] = struct{}{}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].GetPosition() < items[j].GetPosition()
+	})
+
+	if c.manager == nil {
+		c.manager = NewOrderManager[T]()
+	}
+	c.items = items
+	c.archived, c.excluded = nil, nil
+	c.manager.NormalizePositions(c.items)
+	c.changed()
+	return nil
+}
+
+// String renders the collection with Dump.
+func (c *OrderedCollection[T]) String() string {
+	return Dump(c.items)
+}
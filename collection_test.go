@@ -0,0 +1,144 @@
+package order_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedCollectionJSONRoundTrip(t *testing.T) {
+	items := createTestItems(3)
+	col := order.NewOrderedCollection(items)
+
+	data, err := json.Marshal(col)
+	require.NoError(t, err)
+
+	var decoded order.OrderedCollection[*TestItem]
+	require.NoError(t, json.Unmarshal(data, &decoded))
+
+	assert.Equal(t, col.Len(), decoded.Len())
+	for i, item := range decoded.Items() {
+		assert.Equal(t, items[i].GetID(), item.GetID())
+		assert.Equal(t, i+1, item.GetPosition())
+	}
+}
+
+func TestOrderedCollectionUnmarshalSortsAndNormalizes(t *testing.T) {
+	items := createTestItems(3)
+	data := []byte(`[
+		{"ID": "` + items[0].GetID() + `", "Position": 30},
+		{"ID": "` + items[1].GetID() + `", "Position": 10},
+		{"ID": "` + items[2].GetID() + `", "Position": 20}
+	]`)
+
+	var col order.OrderedCollection[*TestItem]
+	require.NoError(t, json.Unmarshal(data, &col))
+
+	got := col.Items()
+	assert.Equal(t, items[1].GetID(), got[0].GetID())
+	assert.Equal(t, items[2].GetID(), got[1].GetID())
+	assert.Equal(t, items[0].GetID(), got[2].GetID())
+	for i, item := range got {
+		assert.Equal(t, i+1, item.GetPosition())
+	}
+}
+
+func TestOrderedCollectionUnmarshalDropsArchivedAndExcluded(t *testing.T) {
+	items := createTestItems(3)
+	col := order.NewOrderedCollection(items)
+	require.NoError(t, col.Archive(items[0].GetID()))
+	require.NoError(t, col.Exclude(items[1].GetID()))
+
+	data := []byte(`[{"ID": "` + createTestItems(1)[0].GetID() + `", "Position": 1}]`)
+	require.NoError(t, json.Unmarshal(data, col))
+	assert.Empty(t, col.Archived())
+	assert.Empty(t, col.Excluded())
+	assert.ErrorIs(t, col.Restore(items[0].GetID(), order.RestorePosition), order.ErrItemNotFound)
+	assert.Equal(t, 1, col.Len())
+}
+
+func TestOrderedCollectionUnmarshalRejectsInvalidPositions(t *testing.T) {
+	items := createTestItems(2)
+	cases := map[string]string{
+		"duplicate": `[{"ID": "` + items[0].GetID() + `", "Position": 1}, {"ID": "` + items[1].GetID() + `", "Position": 1}]`,
+		"zero":      `[{"ID": "` + items[0].GetID() + `", "Position": 0}]`,
+	}
+	for name, data := range cases {
+		t.Run(name, func(t *testing.T) {
+			var col order.OrderedCollection[*TestItem]
+			err := json.Unmarshal([]byte(data), &col)
+			assert.True(t, errors.Is(err, order.ErrInvalidPosition))
+		})
+	}
+}
+
+func TestOrderedCollectionMarshalEmpty(t *testing.T) {
+	data, err := json.Marshal(order.NewOrderedCollection[*TestItem](nil))
+	require.NoError(t, err)
+	assert.JSONEq(t, `[]`, string(data))
+}
+
+func TestOrderedCollectionVerbs(t *testing.T) {
+	items := createTestItems(4)
+	a, b, c, d := items[0], items[1], items[2], items[3]
+	col := order.NewOrderedCollection(items)
+	var changes []order.Result[*TestItem]
+	col.OnChange(func(result order.Result[*TestItem]) { changes = append(changes, result) })
+
+	steps := []func() (order.Result[*TestItem], error){
+		func() (order.Result[*TestItem], error) { return col.Bottom(a.GetID()) },
+		func() (order.Result[*TestItem], error) { return col.Up(d.GetID()) },
+		func() (order.Result[*TestItem], error) { return col.Above(c.GetID(), b.GetID()) },
+		func() (order.Result[*TestItem], error) { return col.Down(c.GetID()) },
+		func() (order.Result[*TestItem], error) { return col.To(a.GetID(), 2) },
+		func() (order.Result[*TestItem], error) { return col.Below(d.GetID(), a.GetID()) },
+	}
+	for _, step := range steps {
+		_, err := step()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []*TestItem{b, a, d, c}, col.Items())
+	assert.Equal(t, int64(6), col.Version())
+	assert.Len(t, changes, 6)
+
+	_, err := col.Top(b.GetID())
+	require.NoError(t, err)
+	assert.Equal(t, int64(6), col.Version(), "no-ops do not count")
+	assert.Len(t, changes, 6)
+
+	_, err = col.MoveMany([]string{d.GetID(), c.GetID()}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, []*TestItem{d, c, b, a}, col.Items())
+
+	_, err = col.Top("missing")
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	assert.Equal(t, int64(7), col.Version())
+}
+
+func TestOrderedCollectionGet(t *testing.T) {
+	items := createTestItems(3)
+	a, b, c := items[0], items[1], items[2]
+	col := order.NewOrderedCollection(items)
+
+	got, ok := col.Get(b.GetID())
+	require.True(t, ok)
+	assert.Same(t, b, got)
+	_, ok = col.Get("missing")
+	assert.False(t, ok)
+
+	// The index follows moves and removals.
+	_, err := col.Top(c.GetID())
+	require.NoError(t, err)
+	require.NoError(t, col.Archive(a.GetID()))
+	got, ok = col.Get(b.GetID())
+	require.True(t, ok)
+	assert.Same(t, b, got)
+	_, ok = col.Get(a.GetID())
+	assert.False(t, ok)
+	assert.Equal(t, int64(2), col.Version())
+}
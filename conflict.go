@@ -0,0 +1,147 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// Conflict describes a move that could not be applied because the list
+// changed underneath it: it was saved by someone else since it was loaded
+// (ErrVersionConflict), or the item or target was removed (ErrItemNotFound)
+// or locked (ErrItemLocked) in the meantime.
+type Conflict struct {
+	ListID string
+	Move   Move[string]
+	Err    error
+	// Attempt counts the resolutions so far, starting at 1.
+	Attempt int
+}
+
+// ConflictResolver decides what happens to a conflicting move. Use one of
+// KeepServer, KeepClient, MergeByRule or AskUser, or implement your own. An
+// error returned by Resolve is returned to whoever submitted the move.
+type ConflictResolver interface {
+	Resolve(ctx context.Context, c Conflict) (Resolution, error)
+}
+
+// ConflictResolverFunc adapts a function to the ConflictResolver interface.
+type ConflictResolverFunc func(ctx context.Context, c Conflict) (Resolution, error)
+
+// Resolve calls f(ctx, c).
+func (f ConflictResolverFunc) Resolve(ctx context.Context, c Conflict) (Resolution, error) {
+	return f(ctx, c)
+}
+
+// ResolutionKind is the decision of a ConflictResolver.
+type ResolutionKind int
+
+const (
+	// RejectMove fails the move with the conflict error.
+	RejectMove ResolutionKind = iota
+	// DropMove discards the move and keeps the list as it is, without error.
+	DropMove
+	// RetryMove applies Resolution.Move to the list as it is now.
+	RetryMove
+)
+
+// Resolution is the outcome of resolving a Conflict.
+type Resolution struct {
+	Kind ResolutionKind
+	// Move is the move to apply for RetryMove: the original move or a
+	// replacement for it.
+	Move Move[string]
+}
+
+// maxConflictAttempts bounds how often a move is retried after conflicts,
+// so that a resolver that keeps retrying against a busy list gives up.
+const maxConflictAttempts = 3
+
+// KeepServer resolves conflicts in favor of the stored order: conflicting
+// moves are dropped without error.
+func KeepServer() ConflictResolver {
+	return ConflictResolverFunc(func(context.Context, Conflict) (Resolution, error) {
+		return Resolution{Kind: DropMove}, nil
+	})
+}
+
+// KeepClient resolves version conflicts in favor of the move, by applying it
+// again to the list as it is now. Moves whose item or target is gone cannot be
+// applied again and are rejected.
+func KeepClient() ConflictResolver {
+	return ConflictResolverFunc(func(_ context.Context, c Conflict) (Resolution, error) {
+		if errors.Is(c.Err, ErrVersionConflict) {
+			return Resolution{Kind: RetryMove, Move: c.Move}, nil
+		}
+		return Resolution{Kind: RejectMove}, nil
+	})
+}
+
+// MergeByRule resolves conflicts with rule, which returns the move to apply
+// instead of the conflicting one, or false to reject it:
+//
+//	// Moves relative to a removed card go to the top.
+//	order.MergeByRule(func(c order.Conflict) (order.Move[string], bool) {
+//		return order.Move[string]{Kind: order.MoveTop, ItemID: c.Move.ItemID}, true
+//	})
+func MergeByRule(rule func(c Conflict) (Move[string], bool)) ConflictResolver {
+	return ConflictResolverFunc(func(_ context.Context, c Conflict) (Resolution, error) {
+		if m, ok := rule(c); ok {
+			return Resolution{Kind: RetryMove, Move: m}, nil
+		}
+		return Resolution{Kind: RejectMove}, nil
+	})
+}
+
+// AskUser fails conflicting moves with a *ConflictError, so that the caller
+// can show the conflict to the user and let them decide.
+func AskUser() ConflictResolver {
+	return ConflictResolverFunc(func(_ context.Context, c Conflict) (Resolution, error) {
+		return Resolution{}, &ConflictError{Conflict: c}
+	})
+}
+
+// ConflictError reports a conflict that needs a decision by the user. It
+// matches the error of the conflict with errors.Is.
+type ConflictError struct {
+	Conflict Conflict
+}
+
+func (e *ConflictError) Error() string {
+	return fmt.Sprintf("conflict on list %s: %v needs a decision", e.Conflict.ListID, e.Conflict.Err)
+}
+
+func (e *ConflictError) Unwrap() error {
+	return e.Conflict.Err
+}
+
+func isConflict(err error) bool {
+	return errors.Is(err, ErrVersionConflict) || errors.Is(err, ErrItemNotFound) || errors.Is(err, ErrItemLocked)
+}
+
+// resolveConflicts hands the conflicting move m, which failed with err, to
+// resolver and applies its replacements with apply until one succeeds or the
+// resolver gives up. dropped reports that the resolver dropped the move.
+func resolveConflicts[R any](ctx context.Context, resolver ConflictResolver, listID string, m Move[string], err error, apply func(Move[string]) (R, error)) (result R, dropped bool, _ error) {
+	for attempt := 1; isConflict(err); attempt++ {
+		if attempt > maxConflictAttempts {
+			return result, false, err
+		}
+		resolution, rerr := resolver.Resolve(ctx, Conflict{ListID: listID, Move: m, Err: err, Attempt: attempt})
+		if rerr != nil {
+			return result, false, rerr
+		}
+		switch resolution.Kind {
+		case DropMove:
+			return result, true, nil
+		case RetryMove:
+			m = resolution.Move
+			if result, err = apply(m); err == nil {
+				return result, false, nil
+			}
+		default:
+			return result, false, err
+		}
+	}
+	return result, false, err
+}
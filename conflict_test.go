@@ -0,0 +1,108 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// racingStore saves a move of another writer right after the next Load, so
+// that the save of the loaded list fails with ErrVersionConflict.
+type racingStore struct {
+	*ordertest.Store[*ordertest.Item]
+	races int
+}
+
+func (s *racingStore) Load(ctx context.Context, listID string) ([]*ordertest.Item, int64, error) {
+	items, version, err := s.Store.Load(ctx, listID)
+	if err == nil && s.races > 0 {
+		s.races--
+		other, _, _ := s.Store.Load(ctx, listID)
+		om := order.NewOrderManager[*ordertest.Item]()
+		result, _ := om.Bottom(other, other[0].GetID())
+		changes := make(order.ChangeSet, len(result.Affected))
+		for i, item := range result.Affected {
+			changes[i] = order.PositionChange{ItemID: item.GetID(), NewPosition: item.GetPosition()}
+		}
+		_, _ = s.Store.SavePositions(ctx, listID, version, changes)
+	}
+	return items, version, err
+}
+
+func newRacingManager(races int, opts ...order.PersistentOption) (*order.PersistentManager[*ordertest.Item], *racingStore) {
+	store := &racingStore{Store: ordertest.NewStore[*ordertest.Item](), races: races}
+	store.Put("list", ordertest.Items(3))
+	return order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), opts...), store
+}
+
+func TestConflictResolvers(t *testing.T) {
+	ctx := context.Background()
+	top := order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"}
+
+	pm, _ := newRacingManager(1)
+	_, err := pm.Apply(ctx, "list", top)
+	assert.ErrorIs(t, err, order.ErrVersionConflict, "no resolver")
+
+	pm, store := newRacingManager(1, order.WithConflictResolver(order.KeepServer()))
+	result, err := pm.Apply(ctx, "list", top)
+	require.NoError(t, err)
+	assert.False(t, result.Changed)
+	assert.Equal(t, store.Version("list"), result.Version)
+	items, _, _ := pm.Load(ctx, "list")
+	ordertest.AssertOrder(t, items, "item-2", "item-3", "item-1")
+
+	pm, _ = newRacingManager(1, order.WithConflictResolver(order.KeepClient()))
+	result, err = pm.Apply(ctx, "list", top)
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+	items, _, _ = pm.Load(ctx, "list")
+	ordertest.AssertOrder(t, items, "item-3", "item-2", "item-1")
+
+	pm, _ = newRacingManager(10, order.WithConflictResolver(order.KeepClient()))
+	_, err = pm.Apply(ctx, "list", top)
+	assert.ErrorIs(t, err, order.ErrVersionConflict, "gives up on a busy list")
+
+	pm, _ = newRacingManager(1, order.WithConflictResolver(order.AskUser()))
+	_, err = pm.Apply(ctx, "list", top)
+	var conflict *order.ConflictError
+	require.ErrorAs(t, err, &conflict)
+	assert.ErrorIs(t, err, order.ErrVersionConflict)
+	assert.Equal(t, "list", conflict.Conflict.ListID)
+	assert.Equal(t, top, conflict.Conflict.Move)
+	assert.Equal(t, 1, conflict.Conflict.Attempt)
+}
+
+func TestMergeByRule(t *testing.T) {
+	ctx := context.Background()
+	toBottom := order.MergeByRule(func(c order.Conflict) (order.Move[string], bool) {
+		var notFound *order.NotFoundError
+		if errors.As(c.Err, &notFound) && notFound.ItemID == c.Move.TargetID {
+			return order.Move[string]{Kind: order.MoveBottom, ItemID: c.Move.ItemID}, true
+		}
+		return c.Move, false
+	})
+	var events []order.OrderChangedEvent
+	pm, _ := newRacingManager(0, order.WithConflictResolver(toBottom), order.WithObserver(func(_ context.Context, event order.OrderChangedEvent) {
+		events = append(events, event)
+	}))
+
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveAbove, ItemID: "item-1", TargetID: "gone"})
+	require.NoError(t, err)
+	items, _, _ := pm.Load(ctx, "list")
+	ordertest.AssertOrder(t, items, "item-2", "item-3", "item-1")
+	// The event reports the move that replaced the conflicting one.
+	require.Len(t, events, 1)
+	assert.Equal(t, order.Move[string]{Kind: order.MoveBottom, ItemID: "item-1"}, events[0].Move)
+
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "gone"})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTo, ItemID: "item-1", Position: 9})
+	assert.ErrorIs(t, err, order.ErrInvalidPosition, "not a conflict")
+}
@@ -0,0 +1,189 @@
+package order
+
+import (
+	"container/heap"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Constraint requires the item First to come somewhere before the item Then.
+// "C after D" is Constraint{First: D, Then: C}.
+type Constraint[ID comparable] struct {
+	First ID
+	Then  ID
+}
+
+// CycleError reports constraints that cannot all hold because they require
+// items to come before themselves. It matches ErrInvalidOrder with errors.Is.
+type CycleError struct {
+	Op string
+	// Cycles holds the IDs of each group of items whose constraints form a
+	// cycle, in their current order.
+	Cycles [][]string
+}
+
+func (e *CycleError) Error() string {
+	groups := make([]string, len(e.Cycles))
+	for i, cycle := range e.Cycles {
+		groups[i] = strings.Join(cycle, ", ")
+	}
+	return fmt.Sprintf("%s: %v: constraints form cycles among [%s]", e.Op, ErrInvalidOrder, strings.Join(groups, "], ["))
+}
+
+func (e *CycleError) Unwrap() error {
+	return ErrInvalidOrder
+}
+
+// ApplyConstraints reorders items so that every constraint holds, moving as
+// little as it can: items are taken in their current order, except that an
+// item waits until every item constrained to come before it has been placed.
+// An ordering that already satisfies the constraints is left as it is, and
+// items without constraints keep their order relative to each other. This
+// keeps displacement low, but is not guaranteed to find the order with the
+// fewest moved items.
+//
+// Constraints naming unknown items fail with a *NotFoundError, constraints
+// that contradict each other with a *CycleError listing every cycle, and
+// orders that would move a locked item with a *LockedError. The items are left
+// unchanged on error.
+func (os *KeyedManager[T, ID]) ApplyConstraints(items []T, constraints []Constraint[ID]) (result Result[T], err error) {
+	var none ID
+	defer os.instrument("ApplyConstraints", items, none)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("ApplyConstraints: %w", err)
+	}
+	items, done := os.withoutDeleted(items, none)
+	defer done(&result, &err)
+
+	index := os.IndexMap(items)
+	next := make([][]int, len(items))
+	waiting := make([]int, len(items))
+	for _, c := range constraints {
+		first, ok := index[c.First]
+		if !ok {
+			return Result[T]{}, &NotFoundError{Op: "ApplyConstraints", ItemID: formatID(c.First)}
+		}
+		then, ok := index[c.Then]
+		if !ok {
+			return Result[T]{}, &NotFoundError{Op: "ApplyConstraints", ItemID: formatID(c.Then)}
+		}
+		next[first] = append(next[first], then)
+		waiting[then]++
+	}
+
+	// Kahn's algorithm, always taking the ready item that comes first now.
+	ready := &indexHeap{}
+	for i := range items {
+		if waiting[i] == 0 {
+			heap.Push(ready, i)
+		}
+	}
+	order := make([]int, 0, len(items))
+	for ready.Len() > 0 {
+		i := heap.Pop(ready).(int)
+		order = append(order, i)
+		for _, j := range next[i] {
+			if waiting[j]--; waiting[j] == 0 {
+				heap.Push(ready, j)
+			}
+		}
+	}
+	if len(order) < len(items) {
+		return Result[T]{}, &CycleError{Op: "ApplyConstraints", Cycles: os.cycles(items, next, waiting)}
+	}
+
+	for to, from := range order {
+		if to != from && isLocked(items[from]) {
+			return Result[T]{}, &LockedError{Op: "ApplyConstraints", ItemID: formatID(os.getID(items[from]))}
+		}
+	}
+	ordered := make([]T, len(items))
+	for to, from := range order {
+		ordered[to] = items[from]
+	}
+	copy(items, ordered)
+	affected := os.normalize(items)
+	return Result[T]{Changed: len(affected) > 0, Affected: affected}, nil
+}
+
+// cycles returns the IDs of every strongly connected component of more than
+// one item, or of one item constrained to come before itself, among the items
+// that Kahn's algorithm could not place, found with Tarjan's algorithm.
+func (os *KeyedManager[T, ID]) cycles(items []T, next [][]int, waiting []int) [][]string {
+	const unvisited = -1
+	num := make([]int, len(items))
+	low := make([]int, len(items))
+	onStack := make([]bool, len(items))
+	for i := range num {
+		num[i] = unvisited
+	}
+	var stack []int
+	var groups [][]int
+	counter := 0
+
+	var visit func(i int)
+	visit = func(i int) {
+		num[i], low[i] = counter, counter
+		counter++
+		stack = append(stack, i)
+		onStack[i] = true
+		for _, j := range next[i] {
+			if waiting[j] == 0 {
+				continue
+			}
+			if num[j] == unvisited {
+				visit(j)
+				low[i] = min(low[i], low[j])
+			} else if onStack[j] {
+				low[i] = min(low[i], num[j])
+			}
+		}
+		if low[i] != num[i] {
+			return
+		}
+		var members []int
+		for {
+			j := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[j] = false
+			members = append(members, j)
+			if j == i {
+				break
+			}
+		}
+		if len(members) == 1 && !slices.Contains(next[i], i) {
+			return
+		}
+		slices.Sort(members)
+		groups = append(groups, members)
+	}
+	for i := range items {
+		if waiting[i] > 0 && num[i] == unvisited {
+			visit(i)
+		}
+	}
+	slices.SortFunc(groups, func(a, b []int) int { return a[0] - b[0] })
+	cycles := make([][]string, len(groups))
+	for g, members := range groups {
+		cycles[g] = make([]string, len(members))
+		for k, m := range members {
+			cycles[g][k] = formatID(os.getID(items[m]))
+		}
+	}
+	return cycles
+}
+
+// indexHeap is a min-heap of slice indices.
+type indexHeap []int
+
+func (h indexHeap) Len() int           { return len(h) }
+func (h indexHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h indexHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *indexHeap) Push(x any)        { *h = append(*h, x.(int)) }
+func (h *indexHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,55 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyConstraints(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c", "d", "e")
+
+	result, err := om.ApplyConstraints(items, []order.Constraint[string]{
+		{First: "d", Then: "b"},
+		{First: "e", Then: "d"},
+	})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "a", "c", "e", "d", "b")
+	ordertest.AssertNormalized(t, items)
+	assert.True(t, result.Changed)
+	assert.Len(t, result.Affected, 3)
+
+	// Constraints that already hold change nothing.
+	result, err = om.ApplyConstraints(items, []order.Constraint[string]{{First: "a", Then: "b"}, {First: "c", Then: "b"}})
+	require.NoError(t, err)
+	assert.False(t, result.Changed)
+	ordertest.AssertOrder(t, items, "a", "c", "e", "d", "b")
+}
+
+func TestApplyConstraintsCycles(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c", "d", "e", "f")
+
+	_, err := om.ApplyConstraints(items, []order.Constraint[string]{
+		{First: "e", Then: "f"},
+		{First: "f", Then: "e"},
+		{First: "a", Then: "c"},
+		{First: "c", Then: "b"},
+		{First: "b", Then: "a"},
+		{First: "b", Then: "d"}, // d waits for the cycle but is not part of it
+		{First: "d", Then: "d"},
+	})
+	var cycleErr *order.CycleError
+	require.ErrorAs(t, err, &cycleErr)
+	assert.ErrorIs(t, err, order.ErrInvalidOrder)
+	assert.Equal(t, [][]string{{"a", "b", "c"}, {"d"}, {"e", "f"}}, cycleErr.Cycles)
+	ordertest.AssertOrder(t, items, "a", "b", "c", "d", "e", "f")
+
+	_, err = om.ApplyConstraints(items, []order.Constraint[string]{{First: "a", Then: "x"}})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
@@ -0,0 +1,127 @@
+package order
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions names the ID and position columns of a CSV ordering. Empty names
+// default to "id" and "position".
+type CSVOptions struct {
+	IDColumn       string
+	PositionColumn string
+}
+
+func (o CSVOptions) columns() (string, string) {
+	id, position := o.IDColumn, o.PositionColumn
+	if id == "" {
+		id = "id"
+	}
+	if position == "" {
+		position = "position"
+	}
+	return id, position
+}
+
+// CSVRecord is one row of a CSV ordering. Fields holds every column of the row
+// as read, so extra columns are written back unchanged.
+type CSVRecord struct {
+	ID       string
+	Position int
+	Fields   []string
+}
+
+func (r *CSVRecord) GetID() string            { return r.ID }
+func (r *CSVRecord) GetPosition() int         { return r.Position }
+func (r *CSVRecord) SetPosition(position int) { r.Position = position }
+
+// CSVTable is an ordering read from or written to CSV.
+type CSVTable struct {
+	// Header holds the column names, including the ID and position columns.
+	Header []string
+	// Records holds the rows in order.
+	Records []*CSVRecord
+}
+
+// ReadCSV reads an ordering with a header row. Rows are sorted by the position
+// column and renumbered from 1; without a position column the file order is
+// used. Positions must be positive integers and unique, failing with
+// ErrInvalidPosition otherwise, and IDs must be unique, failing with ErrDuplicateID.
+func ReadCSV(r io.Reader, opts CSVOptions) (*CSVTable, error) {
+	idColumn, positionColumn := opts.columns()
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("ReadCSV: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("ReadCSV: missing header row")
+	}
+	header := rows[0]
+	idIndex := slices.Index(header, idColumn)
+	if idIndex < 0 {
+		return nil, fmt.Errorf("ReadCSV: no %q column", idColumn)
+	}
+	positionIndex := slices.Index(header, positionColumn)
+
+	table := &CSVTable{Header: header, Records: make([]*CSVRecord, 0, len(rows)-1)}
+	ids := make(map[string]int, len(rows)-1)
+	positions := make(map[int]int, len(rows)-1)
+	for i, row := range rows[1:] {
+		line := i + 2
+		record := &CSVRecord{ID: row[idIndex], Position: i + 1, Fields: row}
+		if first, ok := ids[record.ID]; ok {
+			return nil, fmt.Errorf("ReadCSV: line %d: id %s already used on line %d: %w", line, record.ID, first, ErrDuplicateID)
+		}
+		ids[record.ID] = line
+		if positionIndex >= 0 {
+			position, err := strconv.Atoi(strings.TrimSpace(row[positionIndex]))
+			if err != nil || position < 1 {
+				return nil, fmt.Errorf("ReadCSV: line %d: position %q: %w", line, row[positionIndex], ErrInvalidPosition)
+			}
+			if first, ok := positions[position]; ok {
+				return nil, fmt.Errorf("ReadCSV: line %d: position %d already used on line %d: %w", line, position, first, ErrInvalidPosition)
+			}
+			positions[position] = line
+			record.Position = position
+		}
+		table.Records = append(table.Records, record)
+	}
+
+	SortByPosition(table.Records, nil)
+	renumber(table.Records)
+	return table, nil
+}
+
+// WriteCSV writes the table with its header, in record order, filling the
+// position column from each record's position. The column is added at the end
+// if the header does not have it.
+func (t *CSVTable) WriteCSV(w io.Writer, opts CSVOptions) error {
+	_, positionColumn := opts.columns()
+	header := t.Header
+	positionIndex := slices.Index(header, positionColumn)
+	if positionIndex < 0 {
+		header = append(slices.Clip(header), positionColumn)
+		positionIndex = len(header) - 1
+	}
+
+	cw := csv.NewWriter(w)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	row := make([]string, len(header))
+	for _, record := range t.Records {
+		clear(row)
+		copy(row, record.Fields)
+		row[positionIndex] = strconv.Itoa(record.Position)
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,60 @@
+package order_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	input := "id,title,position\nb,Beta,20\na,Alpha,10\nc,\"Gamma, the third\",30\n"
+
+	table, err := order.ReadCSV(strings.NewReader(input), order.CSVOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "title", "position"}, table.Header)
+	ids := make([]string, len(table.Records))
+	for i, record := range table.Records {
+		ids[i] = record.ID
+	}
+	assert.Equal(t, []string{"a", "b", "c"}, ids)
+	assert.True(t, order.IsNormalized(table.Records))
+
+	_, err = order.NewOrderManager[*order.CSVRecord]().Top(table.Records, "c")
+	require.NoError(t, err)
+	var buf bytes.Buffer
+	require.NoError(t, table.WriteCSV(&buf, order.CSVOptions{}))
+	assert.Equal(t, "id,title,position\nc,\"Gamma, the third\",1\na,Alpha,2\nb,Beta,3\n", buf.String())
+}
+
+func TestReadCSVWithoutPositionColumn(t *testing.T) {
+	input := "sku,name\nx,First\ny,Second\n"
+	opts := order.CSVOptions{IDColumn: "sku", PositionColumn: "rank"}
+
+	table, err := order.ReadCSV(strings.NewReader(input), opts)
+	require.NoError(t, err)
+	assert.Equal(t, "y", table.Records[1].ID)
+	assert.Equal(t, 2, table.Records[1].Position)
+
+	var buf bytes.Buffer
+	require.NoError(t, table.WriteCSV(&buf, opts))
+	assert.Equal(t, "sku,name,rank\nx,First,1\ny,Second,2\n", buf.String())
+}
+
+func TestReadCSVRejectsInvalidInput(t *testing.T) {
+	for input, want := range map[string]error{
+		"id,position\na,1\nb,1\n": order.ErrInvalidPosition,
+		"id,position\na,0\n":      order.ErrInvalidPosition,
+		"id,position\na,x\n":      order.ErrInvalidPosition,
+		"id,position\na,1\na,2\n": order.ErrDuplicateID,
+	} {
+		_, err := order.ReadCSV(strings.NewReader(input), order.CSVOptions{})
+		assert.ErrorIs(t, err, want, input)
+	}
+	_, err := order.ReadCSV(strings.NewReader("name\nx\n"), order.CSVOptions{})
+	assert.Error(t, err)
+}
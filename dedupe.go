@@ -0,0 +1,66 @@
+package order
+
+import "time"
+
+// Timestamped can be implemented by items to let KeepNewest pick the most
+// recently updated of several items sharing an ID.
+type Timestamped interface {
+	GetUpdatedAt() time.Time
+}
+
+// DuplicatePolicy selects which of several items sharing an ID Deduplicate keeps.
+type DuplicatePolicy int
+
+const (
+	// KeepFirst keeps the first occurrence in slice order.
+	KeepFirst DuplicatePolicy = iota
+	// KeepLowestPosition keeps the occurrence with the lowest position, the
+	// first one in slice order on ties.
+	KeepLowestPosition
+	// KeepNewest keeps the occurrence with the latest GetUpdatedAt if the items
+	// implement Timestamped, otherwise the last occurrence in slice order.
+	KeepNewest
+)
+
+// Deduplicate removes items whose ID occurs more than once, keeping one winner
+// per ID according to policy. Kept items stay in slice order, each at the index
+// of the winning occurrence; removed items are returned in slice order.
+// Positions are not touched, so callers usually normalize the result afterwards.
+func Deduplicate[T Orderable](items []T, policy DuplicatePolicy) (kept []T, removed []T) {
+	winners := make(map[string]int, len(items))
+	for index, item := range items {
+		id := item.GetID()
+		current, ok := winners[id]
+		if !ok || replaces(item, items[current], policy) {
+			winners[id] = index
+		}
+	}
+
+	kept = make([]T, 0, len(winners))
+	for index, item := range items {
+		if winners[item.GetID()] == index {
+			kept = append(kept, item)
+		} else {
+			removed = append(removed, item)
+		}
+	}
+	return kept, removed
+}
+
+// replaces reports whether candidate, which comes later in the slice, wins over
+// current under policy.
+func replaces[T Orderable](candidate, current T, policy DuplicatePolicy) bool {
+	switch policy {
+	case KeepLowestPosition:
+		return candidate.GetPosition() < current.GetPosition()
+	case KeepNewest:
+		a, aok := any(candidate).(Timestamped)
+		b, bok := any(current).(Timestamped)
+		if aok && bok {
+			return !a.GetUpdatedAt().Before(b.GetUpdatedAt())
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,71 @@
+package order_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// TimestampedItem is a TestItem that records when it was last updated.
+type TimestampedItem struct {
+	TestItem
+	UpdatedAt time.Time
+}
+
+func (ti *TimestampedItem) GetUpdatedAt() time.Time {
+	return ti.UpdatedAt
+}
+
+func TestDeduplicateKeepFirst(t *testing.T) {
+	items := createTestItems(4)
+	items[2].ID = items[0].ID
+
+	kept, removed := order.Deduplicate(items, order.KeepFirst)
+
+	assert.Equal(t, []*TestItem{items[0], items[1], items[3]}, kept)
+	assert.Equal(t, []*TestItem{items[2]}, removed)
+}
+
+func TestDeduplicateKeepLowestPosition(t *testing.T) {
+	items := createTestItems(4)
+	items[3].ID = items[1].ID
+	items[3].SetPosition(0)
+
+	kept, removed := order.Deduplicate(items, order.KeepLowestPosition)
+
+	assert.Equal(t, []*TestItem{items[0], items[2], items[3]}, kept)
+	assert.Equal(t, []*TestItem{items[1]}, removed)
+}
+
+func TestDeduplicateKeepNewest(t *testing.T) {
+	id := uuid.New()
+	now := time.Now()
+	items := []*TimestampedItem{
+		{TestItem: TestItem{ID: id, Position: 1}, UpdatedAt: now},
+		{TestItem: TestItem{ID: uuid.New(), Position: 2}, UpdatedAt: now},
+		{TestItem: TestItem{ID: id, Position: 3}, UpdatedAt: now.Add(-time.Hour)},
+	}
+
+	kept, removed := order.Deduplicate(items, order.KeepNewest)
+	assert.Equal(t, []*TimestampedItem{items[0], items[1]}, kept)
+	assert.Equal(t, []*TimestampedItem{items[2]}, removed)
+
+	// Without timestamps the last occurrence is considered the newest.
+	plain := createTestItems(3)
+	plain[2].ID = plain[0].ID
+	keptPlain, _ := order.Deduplicate(plain, order.KeepNewest)
+	assert.Equal(t, []*TestItem{plain[1], plain[2]}, keptPlain)
+}
+
+func TestDeduplicateNoDuplicates(t *testing.T) {
+	items := createTestItems(3)
+
+	kept, removed := order.Deduplicate(items, order.KeepFirst)
+
+	assert.Equal(t, items, kept)
+	assert.Empty(t, removed)
+}
@@ -0,0 +1,106 @@
+package order
+
+// Deletable can be implemented by items that are soft-deleted rather than
+// removed. Moves skip deleted items: they can be neither moved nor used as a
+// target, and they do not count as neighbors, positions or list length. Where
+// they end up is set with WithDeletedPolicy.
+type Deletable interface {
+	IsDeleted() bool
+}
+
+// DeletedPolicy decides what happens to soft-deleted items during a move.
+type DeletedPolicy int
+
+const (
+	// KeepDeletedPositions leaves deleted items in their slots, so they keep
+	// their positions, and moves the live items around them.
+	KeepDeletedPositions DeletedPolicy = iota
+	// CompactDeleted moves deleted items behind all live items, so the live
+	// items are numbered 1..n without holes.
+	CompactDeleted
+)
+
+// mayBeDeletable reports whether values of T can implement Deletable. It is
+// false only for concrete types without an IsDeleted method, which lets moves
+// skip the scan for deleted items.
+func mayBeDeletable[T any]() bool {
+	var zero T
+	if _, ok := any(zero).(Deletable); ok {
+		return true
+	}
+	// A nil interface means T is an interface type whose values may still be Deletable.
+	return any(zero) == nil
+}
+
+func isDeleted[T any](item T) bool {
+	d, ok := any(item).(Deletable)
+	return ok && d.IsDeleted()
+}
+
+// withoutDeleted returns the live items for a move to work on, and the function
+// that lays the result back out over items once the move is done:
+//
+//	items, done := os.withoutDeleted(items, itemID)
+//	defer done(&result, &err)
+//
+// Without deleted items it returns items itself and a no-op.
+func (os *KeyedManager[T, ID]) withoutDeleted(items []T, itemID ID) ([]T, func(*Result[T], *error)) {
+	noop := func(*Result[T], *error) {}
+	if !os.deletable {
+		return items, noop
+	}
+	var slots []int
+	for i, item := range items {
+		if !isDeleted(item) {
+			slots = append(slots, i)
+		}
+	}
+	if len(slots) == len(items) {
+		return items, noop
+	}
+
+	live := make([]T, len(slots))
+	oldPositions := make(map[ID]int, len(items))
+	for i, slot := range slots {
+		live[i] = items[slot]
+	}
+	for _, item := range items {
+		oldPositions[os.getID(item)] = os.getPos(item)
+	}
+
+	return live, func(result *Result[T], err *error) {
+		if *err != nil {
+			return
+		}
+		if os.opts.deletedPolicy == CompactDeleted {
+			deleted := make([]T, 0, len(items)-len(live))
+			for _, item := range items {
+				if isDeleted(item) {
+					deleted = append(deleted, item)
+				}
+			}
+			copy(items, live)
+			copy(items[len(live):], deleted)
+		} else {
+			for i, slot := range slots {
+				items[slot] = live[i]
+			}
+		}
+
+		// The move numbered the live items from the base; renumber the whole slice and
+		// report what changed compared to before the move.
+		var affected []T
+		for i := range items {
+			os.setPos(&items[i], os.opts.position(i))
+			id := os.getID(items[i])
+			if oldPositions[id] != os.opts.position(i) {
+				affected = append(affected, items[i])
+			}
+			if id == itemID {
+				result.OldPosition, result.NewPosition = oldPositions[id], os.opts.position(i)
+			}
+		}
+		result.Changed = len(affected) > 0
+		result.Affected = affected
+	}
+}
@@ -0,0 +1,81 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// DeletableItem extends TestItem with a soft-delete flag.
+type DeletableItem struct {
+	TestItem
+	Deleted bool
+}
+
+func (di *DeletableItem) IsDeleted() bool {
+	return di.Deleted
+}
+
+func createDeletableItems(n int, deleted ...int) []*DeletableItem {
+	items := make([]*DeletableItem, n)
+	for i, item := range createTestItems(n) {
+		items[i] = &DeletableItem{TestItem: *item}
+	}
+	for _, i := range deleted {
+		items[i].Deleted = true
+	}
+	return items
+}
+
+func TestDeletedItemsAreSkipped(t *testing.T) {
+	os := order.NewOrderManager[*DeletableItem]()
+	items := createDeletableItems(4, 1)
+	all := append([]*DeletableItem(nil), items...)
+
+	// all[1] is deleted, so all[2] is directly below all[0].
+	result, err := os.Up(items, all[2].GetID())
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.OldPosition)
+	assert.Equal(t, 1, result.NewPosition)
+	assert.Equal(t, []*DeletableItem{all[2], all[1], all[0], all[3]}, items)
+	assert.Equal(t, 2, all[1].GetPosition(), "deleted item keeps its position")
+	assert.ElementsMatch(t, []*DeletableItem{all[2], all[0]}, result.Affected)
+
+	_, err = os.Above(items, all[3].GetID(), all[1].GetID())
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	_, err = os.Top(items, all[1].GetID())
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+
+	// Bottom counts live items only.
+	result, err = os.Bottom(items, all[2].GetID())
+	require.NoError(t, err)
+	assert.Equal(t, 4, result.NewPosition)
+	assert.Equal(t, []*DeletableItem{all[0], all[1], all[3], all[2]}, items)
+	assert.True(t, order.IsNormalized(items))
+}
+
+func TestDeletedItemsCompacted(t *testing.T) {
+	os := order.NewOrderManager[*DeletableItem](order.WithDeletedPolicy(order.CompactDeleted))
+	items := createDeletableItems(4, 0)
+	all := append([]*DeletableItem(nil), items...)
+
+	result, err := os.Below(items, all[1].GetID(), all[2].GetID())
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.NewPosition)
+	assert.Equal(t, []*DeletableItem{all[2], all[1], all[3], all[0]}, items)
+	assert.True(t, order.IsNormalized(items))
+	assert.Len(t, result.Affected, 3)
+}
+
+func TestMoveWhereSkipsDeleted(t *testing.T) {
+	os := order.NewOrderManager[*DeletableItem]()
+	items := createDeletableItems(3, 1)
+	all := append([]*DeletableItem(nil), items...)
+
+	_, err := os.MoveWhere(items, func(item *DeletableItem) bool { return item != all[0] }, order.Move[string]{Kind: order.MoveTop})
+	require.NoError(t, err)
+	assert.Equal(t, []*DeletableItem{all[2], all[1], all[0]}, items)
+}
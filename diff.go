@@ -0,0 +1,27 @@
+package order
+
+// Diff returns the changes that turn the order recorded in before into the
+// order in after, as a ChangeSet in the order of after followed by removed
+// items. Positions are 1-based slice positions: an item added since before
+// has an OldPosition of 0, and an item removed since has a NewPosition of 0.
+// Items whose position is the same in both are left out.
+func Diff(before, after OrderSnapshot) ChangeSet {
+	old := make(map[string]int, len(before.IDs))
+	for i, id := range before.IDs {
+		old[id] = i + 1
+	}
+	var changes ChangeSet
+	current := make(map[string]bool, len(after.IDs))
+	for i, id := range after.IDs {
+		current[id] = true
+		if old[id] != i+1 {
+			changes = append(changes, PositionChange{ItemID: id, OldPosition: old[id], NewPosition: i + 1})
+		}
+	}
+	for i, id := range before.IDs {
+		if !current[id] {
+			changes = append(changes, PositionChange{ItemID: id, OldPosition: i + 1})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,22 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiff(t *testing.T) {
+	before := order.OrderSnapshot{IDs: []string{"a", "b", "c", "d"}}
+	after := order.OrderSnapshot{IDs: []string{"b", "a", "e", "d"}}
+
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "b", OldPosition: 2, NewPosition: 1},
+		{ItemID: "a", OldPosition: 1, NewPosition: 2},
+		{ItemID: "e", OldPosition: 0, NewPosition: 3},
+		{ItemID: "c", OldPosition: 3, NewPosition: 0},
+	}, order.Diff(before, after))
+	assert.Empty(t, order.Diff(before, before))
+}
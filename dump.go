@@ -0,0 +1,45 @@
+package order
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+)
+
+// Labeler can be implemented by items to add a human-readable label to Dump output.
+type Labeler interface {
+	Label() string
+}
+
+// Dump renders items as a table of index, position and ID, plus a label column
+// if any item implements Labeler. It is meant for debugging and logs.
+func Dump[T Orderable](items []T) string {
+	labeled := false
+	for _, item := range items {
+		if _, ok := any(item).(Labeler); ok {
+			labeled = true
+			break
+		}
+	}
+
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	if labeled {
+		fmt.Fprintln(w, "INDEX\tPOSITION\tID\tLABEL")
+	} else {
+		fmt.Fprintln(w, "INDEX\tPOSITION\tID")
+	}
+	for index, item := range items {
+		if !labeled {
+			fmt.Fprintf(w, "%d\t%d\t%s\n", index, item.GetPosition(), item.GetID())
+			continue
+		}
+		label := ""
+		if l, ok := any(item).(Labeler); ok {
+			label = l.Label()
+		}
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", index, item.GetPosition(), item.GetID(), label)
+	}
+	w.Flush()
+	return b.String()
+}
@@ -0,0 +1,52 @@
+package order_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// LabeledItem is a TestItem with a human-readable name.
+type LabeledItem struct {
+	TestItem
+	Name string
+}
+
+func (li *LabeledItem) Label() string {
+	return li.Name
+}
+
+func TestDump(t *testing.T) {
+	items := createTestItems(2)
+	items[1].SetPosition(7)
+
+	lines := strings.Split(strings.TrimSpace(order.Dump(items)), "\n")
+
+	assert.Len(t, lines, 3)
+	assert.Equal(t, []string{"INDEX", "POSITION", "ID"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"1", "7", items[1].GetID()}, strings.Fields(lines[2]))
+}
+
+func TestDumpWithLabels(t *testing.T) {
+	items := []*LabeledItem{
+		{TestItem: TestItem{ID: uuid.New(), Position: 1}, Name: "First"},
+		{TestItem: TestItem{ID: uuid.New(), Position: 2}, Name: "Second"},
+	}
+
+	lines := strings.Split(strings.TrimSpace(order.Dump(items)), "\n")
+
+	assert.Equal(t, []string{"INDEX", "POSITION", "ID", "LABEL"}, strings.Fields(lines[0]))
+	assert.Equal(t, []string{"0", "1", items[0].GetID(), "First"}, strings.Fields(lines[1]))
+}
+
+func TestOrderedCollectionString(t *testing.T) {
+	items := createTestItems(3)
+	col := order.NewOrderedCollection(items)
+
+	assert.Equal(t, order.Dump(items), fmt.Sprint(col))
+}
@@ -0,0 +1,147 @@
+package order
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrItemNotFound         = errors.New("item not found")
+	ErrInvalidPosition      = errors.New("invalid position")
+	ErrInvalidOrder         = errors.New("invalid order")
+	ErrDuplicateID          = errors.New("duplicate item id")
+	ErrItemLocked           = errors.New("item locked")
+	ErrAlreadyAtTop         = errors.New("item already at top")
+	ErrAlreadyAtBottom      = errors.New("item already at bottom")
+	ErrSameItem             = errors.New("item and target are the same")
+	ErrListNotFound         = errors.New("list not found")
+	ErrVersionConflict      = errors.New("version conflict")
+	ErrInvalidTags          = errors.New("invalid order struct tags")
+	ErrUnknownLane          = errors.New("unknown lane")
+	ErrNothingToUndo        = errors.New("nothing to undo")
+	ErrInvalidEncoding      = errors.New("invalid binary encoding")
+	ErrQueueClosed          = errors.New("queue closed")
+	ErrTransactionDone      = errors.New("transaction already committed or rolled back")
+	ErrSavepointNotFound    = errors.New("savepoint not found")
+	ErrUnhealthy            = errors.New("store unhealthy")
+	ErrIdempotencyKeyReused = errors.New("idempotency key reused for a different move")
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrNotPermitted         = errors.New("not permitted to move item")
+	ErrNoAuditStore         = errors.New("no audit store")
+)
+
+// NotFoundError reports an ID that is not present in the slice.
+// It matches ErrItemNotFound with errors.Is.
+type NotFoundError struct {
+	Op     string
+	ItemID string
+}
+
+func (e *NotFoundError) Error() string {
+	return fmt.Sprintf("%s: %v: %s", e.Op, ErrItemNotFound, e.ItemID)
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return ErrItemNotFound
+}
+
+// PositionError reports a requested position outside the valid range [Min, Max].
+// It matches ErrInvalidPosition with errors.Is.
+type PositionError struct {
+	Op        string
+	Requested int
+	Min       int
+	Max       int
+}
+
+func (e *PositionError) Error() string {
+	return fmt.Sprintf("%s: %v: %d not in [%d, %d]", e.Op, ErrInvalidPosition, e.Requested, e.Min, e.Max)
+}
+
+func (e *PositionError) Unwrap() error {
+	return ErrInvalidPosition
+}
+
+// LockedError reports an attempt to move an item that is locked.
+// It matches ErrItemLocked with errors.Is.
+type LockedError struct {
+	Op     string
+	ItemID string
+}
+
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s: %v: %s", e.Op, ErrItemLocked, e.ItemID)
+}
+
+func (e *LockedError) Unwrap() error {
+	return ErrItemLocked
+}
+
+// DuplicateIDError reports an ID that occurs more than once in a slice.
+// It matches ErrDuplicateID with errors.Is.
+type DuplicateIDError struct {
+	Op          string
+	ItemID      string
+	FirstIndex  int
+	SecondIndex int
+}
+
+func (e *DuplicateIDError) Error() string {
+	return fmt.Sprintf("%s: %v: %s at indices %d and %d", e.Op, ErrDuplicateID, e.ItemID, e.FirstIndex, e.SecondIndex)
+}
+
+func (e *DuplicateIDError) Unwrap() error {
+	return ErrDuplicateID
+}
+
+// PermissionError reports an item that the user may not reorder.
+// It matches ErrNotPermitted with errors.Is.
+type PermissionError struct {
+	Op     string
+	ItemID string
+}
+
+func (e *PermissionError) Error() string {
+	return fmt.Sprintf("%s: %v: %s", e.Op, ErrNotPermitted, e.ItemID)
+}
+
+func (e *PermissionError) Unwrap() error {
+	return ErrNotPermitted
+}
+
+// PreconditionError reports a Precondition of a move that did not hold.
+// Field is "PrevID", "NextID" or "Position", and Expected and Actual are the
+// expected and actual values, formatted as strings. It matches
+// ErrPreconditionFailed with errors.Is.
+type PreconditionError struct {
+	Op       string
+	ItemID   string
+	Field    string
+	Expected string
+	Actual   string
+}
+
+func (e *PreconditionError) Error() string {
+	return fmt.Sprintf("%s: %v: %s of %s is %q, expected %q", e.Op, ErrPreconditionFailed, e.Field, e.ItemID, e.Actual, e.Expected)
+}
+
+func (e *PreconditionError) Unwrap() error {
+	return ErrPreconditionFailed
+}
+
+// AuditError reports a move that was saved but whose event could not be
+// recorded in the AuditStore. The move is not undone, so callers that retry
+// failed moves should tell it apart with errors.As instead of retrying.
+type AuditError struct {
+	ListID      string
+	OperationID string
+	Err         error
+}
+
+func (e *AuditError) Error() string {
+	return fmt.Sprintf("audit %s: operation %s: %v", e.ListID, e.OperationID, e.Err)
+}
+
+func (e *AuditError) Unwrap() error {
+	return e.Err
+}
@@ -0,0 +1,77 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+// LockableItem is a TestItem that can be locked in place.
+type LockableItem struct {
+	TestItem
+	Locked bool
+}
+
+func (li *LockableItem) IsLocked() bool {
+	return li.Locked
+}
+
+func TestPositionError(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(3)
+
+	_, err := os.To(items, items[0].GetID(), 7)
+
+	var posErr *order.PositionError
+	assert.True(t, errors.As(err, &posErr))
+	assert.Equal(t, 7, posErr.Requested)
+	assert.Equal(t, 1, posErr.Min)
+	assert.Equal(t, 3, posErr.Max)
+	assert.True(t, errors.Is(err, order.ErrInvalidPosition))
+}
+
+func TestNotFoundError(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(3)
+	missingID := uuid.New().String()
+
+	_, err := os.Above(items, items[0].GetID(), missingID)
+
+	var notFound *order.NotFoundError
+	assert.True(t, errors.As(err, &notFound))
+	assert.Equal(t, missingID, notFound.ItemID)
+	assert.True(t, errors.Is(err, order.ErrItemNotFound))
+}
+
+func TestLockedError(t *testing.T) {
+	os := order.NewOrderManager[*LockableItem]()
+	items := []*LockableItem{
+		{TestItem: TestItem{ID: uuid.New(), Position: 1}},
+		{TestItem: TestItem{ID: uuid.New(), Position: 2}, Locked: true},
+		{TestItem: TestItem{ID: uuid.New(), Position: 3}},
+	}
+	lockedID := items[1].GetID()
+
+	for name, move := range map[string]func() error{
+		"Up":   func() error { _, err := os.Up(items, lockedID); return err },
+		"Down": func() error { _, err := os.Down(items, lockedID); return err },
+		"Top":  func() error { _, err := os.Top(items, lockedID); return err },
+	} {
+		err := move()
+		var locked *order.LockedError
+		assert.True(t, errors.As(err, &locked), name)
+		assert.Equal(t, lockedID, locked.ItemID, name)
+		assert.True(t, errors.Is(err, order.ErrItemLocked), name)
+	}
+	locked := items[1]
+	assert.Equal(t, 2, locked.GetPosition())
+
+	// Unlocked items may still displace the locked one.
+	_, err := os.Top(items, items[2].GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, 3, locked.GetPosition())
+}
@@ -0,0 +1,126 @@
+package order
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OrderChangedEvent reports a change to the order of a list made through a
+// PersistentManager, so that other services can mirror the list without
+// polling it. Events of concurrent moves may be observed out of order;
+// Version orders them, except in write-behind mode, where it is the version
+// of the last save.
+type OrderChangedEvent struct {
+	ListID      string `json:"list_id"`
+	OperationID string `json:"operation_id"`
+	// Move is the move that changed the list. Its Kind is 0 for a complete
+	// order applied with ApplyOrder.
+	Move    Move[string] `json:"move"`
+	Changes ChangeSet    `json:"changes"`
+	Version int64        `json:"version"`
+	Time    time.Time    `json:"time"`
+	// Actor made the move, see WithActor.
+	Actor string `json:"actor,omitempty"`
+	// TenantID is the tenant of the list for the managers of a
+	// TenantManager.
+	TenantID string `json:"tenant_id,omitempty"`
+}
+
+// WithObserver calls observe with an OrderChangedEvent after every applied
+// move that changed a list. Observers are called one at a time on the
+// goroutine that made the move, so they should return quickly.
+func WithObserver(observe func(context.Context, OrderChangedEvent)) PersistentOption {
+	return func(o *persistentOptions) {
+		o.observers = append(o.observers, observe)
+	}
+}
+
+// watchers delivers events to the channels returned by Watch.
+type watchers struct {
+	mu   sync.Mutex
+	subs map[*watcher]struct{}
+}
+
+type watcher struct {
+	listID string
+	ch     chan OrderChangedEvent
+}
+
+// Watch returns a channel of the OrderChangedEvents of listID, or of all
+// lists if listID is empty, from now on. The channel has room for buffer
+// events; a watcher that falls further behind is dropped and its channel
+// closed, so that it cannot hold up moves, and should load the list again
+// before watching anew. The channel is also closed when ctx is done.
+func (pm *PersistentManager[T]) Watch(ctx context.Context, listID string, buffer int) <-chan OrderChangedEvent {
+	w := &watcher{listID: listID, ch: make(chan OrderChangedEvent, buffer)}
+	pm.watchers.mu.Lock()
+	if pm.watchers.subs == nil {
+		pm.watchers.subs = make(map[*watcher]struct{})
+	}
+	pm.watchers.subs[w] = struct{}{}
+	pm.watchers.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		pm.watchers.drop(w)
+	}()
+	return w.ch
+}
+
+// drop closes the channel of w unless it was dropped already.
+func (ws *watchers) drop(w *watcher) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	if _, ok := ws.subs[w]; ok {
+		delete(ws.subs, w)
+		close(w.ch)
+	}
+}
+
+func (ws *watchers) send(event OrderChangedEvent) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	for w := range ws.subs {
+		if w.listID != "" && w.listID != event.ListID {
+			continue
+		}
+		select {
+		case w.ch <- event:
+		default:
+			delete(ws.subs, w)
+			close(w.ch)
+		}
+	}
+}
+
+// applied stamps the result of m, just applied to listID, with an operation
+// ID, records the change in the audit store and reports it to observers and
+// watchers. The error is an *AuditError if the audit store failed.
+func (pm *PersistentManager[T]) applied(ctx context.Context, listID string, m Move[string], result *PersistedResult[T]) error {
+	result.OperationID = pm.opts.operationIDs()
+	if !result.Changed {
+		return nil
+	}
+	event := OrderChangedEvent{
+		ListID:      listID,
+		OperationID: result.OperationID,
+		Move:        m,
+		Changes:     result.Changes,
+		Version:     result.Version,
+		Time:        time.Now(),
+		Actor:       ActorFrom(ctx),
+		TenantID:    pm.tenantID,
+	}
+	var err error
+	if pm.opts.audit != nil {
+		if auditErr := pm.opts.audit.Append(ctx, event); auditErr != nil {
+			err = &AuditError{ListID: listID, OperationID: result.OperationID, Err: auditErr}
+		}
+	}
+	for _, observe := range pm.opts.observers {
+		observe(ctx, event)
+	}
+	pm.watchers.send(event)
+	return err
+}
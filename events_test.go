@@ -0,0 +1,79 @@
+package order_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/mockstore"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithObserver(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	var events []order.OrderChangedEvent
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](),
+		order.WithObserver(func(_ context.Context, event order.OrderChangedEvent) {
+			events = append(events, event)
+		}))
+
+	m := order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"}
+	result, err := pm.Apply(ctx, "list", m)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	event := events[0]
+	assert.Equal(t, "list", event.ListID)
+	assert.Equal(t, result.OperationID, event.OperationID)
+	assert.Equal(t, m, event.Move)
+	assert.Equal(t, result.Changes, event.Changes)
+	assert.Equal(t, result.Version, event.Version)
+	assert.False(t, event.Time.IsZero())
+
+	// Moves that change nothing are not reported.
+	_, err = pm.Apply(ctx, "list", m)
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+
+	_, err = pm.ApplyOrder(ctx, "list", []string{"item-1", "item-2", "item-3"}, order.ReconcilePolicy[*ordertest.Item]{})
+	require.NoError(t, err)
+	require.Len(t, events, 2)
+	assert.Zero(t, events[1].Move.Kind)
+	assert.NotEmpty(t, events[1].Changes)
+}
+
+func TestWatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("a", ordertest.Items(3))
+	store.Put("b", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	list := pm.Watch(ctx, "a", 4)
+	all := pm.Watch(ctx, "", 4)
+	slow := pm.Watch(ctx, "", 1)
+
+	_, err := pm.Apply(ctx, "b", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	_, err = pm.Apply(ctx, "a", order.Move[string]{Kind: order.MoveTop, ItemID: "item-2"})
+	require.NoError(t, err)
+
+	assert.Equal(t, "a", (<-list).ListID)
+	assert.Equal(t, "b", (<-all).ListID)
+	assert.Equal(t, "a", (<-all).ListID)
+
+	// The slow watcher missed the second event and was dropped.
+	assert.Equal(t, "b", (<-slow).ListID)
+	_, open := <-slow
+	assert.False(t, open)
+
+	cancel()
+	_, open = <-list
+	assert.False(t, open)
+	_, open = <-all
+	assert.False(t, open)
+}
@@ -0,0 +1,53 @@
+package order
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"hash"
+)
+
+// hashVersion identifies the digest format. It is mixed into every hash so
+// that a change of format never produces digests that collide with old ones.
+const hashVersion = "order/hash/v1"
+
+// Hash returns a stable digest of the ID sequence of items, in slice order. It
+// changes whenever items are added, removed or reordered, and only then, so it
+// can serve as an HTTP ETag or let two parties check that they agree on an
+// order without exchanging it. Positions are not hashed.
+func Hash[T Orderable](items []T) string {
+	h := newIDHash()
+	for _, item := range items {
+		writeID(h, item.GetID())
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// HashIDs is Hash for a list of IDs, such as OrderSnapshot.IDs.
+func HashIDs(ids []string) string {
+	h := newIDHash()
+	for _, id := range ids {
+		writeID(h, id)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func newIDHash() hash.Hash {
+	h := sha256.New()
+	h.Write([]byte(hashVersion))
+	return h
+}
+
+// writeID writes a length-prefixed ID, so that ("ab", "c") and ("a", "bc") hash
+// differently.
+func writeID(h hash.Hash, id string) {
+	var length [binary.MaxVarintLen64]byte
+	h.Write(length[:binary.PutUvarint(length[:], uint64(len(id)))])
+	h.Write([]byte(id))
+}
+
+// Hash returns the digest of the snapshot's IDs, equal to Hash of the items it
+// was taken from.
+func (s OrderSnapshot) Hash() string {
+	return HashIDs(s.IDs)
+}
@@ -0,0 +1,26 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	items := ordertest.ItemsWithIDs("a", "b", "c")
+	hash := order.Hash(items)
+	assert.Len(t, hash, 64)
+	assert.Equal(t, hash, order.HashIDs([]string{"a", "b", "c"}))
+	assert.Equal(t, hash, order.Snapshot(items).Hash())
+
+	// Positions do not matter, only the ID sequence does.
+	items[0].Position = 7
+	assert.Equal(t, hash, order.Hash(items))
+
+	assert.NotEqual(t, hash, order.HashIDs([]string{"b", "a", "c"}))
+	assert.NotEqual(t, order.HashIDs([]string{"ab", "c"}), order.HashIDs([]string{"a", "bc"}))
+	assert.NotEqual(t, order.HashIDs(nil), order.HashIDs([]string{""}))
+}
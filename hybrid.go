@@ -0,0 +1,240 @@
+package order
+
+// DefaultHybridThreshold is the size at which a HybridList created with a
+// threshold of 0 switches to its tree backend.
+const DefaultHybridThreshold = 2048
+
+// HybridList is an ordered list that picks its data structure by size. Small
+// lists are kept in a plain slice and moved with an OrderManager, which is the
+// fastest option for them. Once the list grows past a threshold it switches to
+// a balanced tree, where inserts, removals and moves take O(log n) instead of
+// O(n); it switches back when it shrinks below half the threshold.
+//
+// In slice mode the positions of the items are renumbered on every change. In
+// tree mode that would cost O(n) per move, so item positions are only written
+// by Items; Position always reports the current position.
+//
+// Positions passed to At, Insert and To count slots from the index base, like
+// those of OrderManager.To.
+type HybridList[T Orderable] struct {
+	threshold int
+	manager   *OrderManager[T]
+
+	// Exactly one backend is in use: items while tree is nil.
+	items []T
+	tree  *treeList[T]
+	nodes map[string]*treeNode[T]
+}
+
+// NewHybridList creates a list from items in their current slice order and
+// normalizes their positions. A threshold of 0 means DefaultHybridThreshold.
+// The list takes ownership of items. The options configure the moves and the
+// numbering of positions.
+func NewHybridList[T Orderable](items []T, threshold int, opts ...Option) *HybridList[T] {
+	if threshold <= 0 {
+		threshold = DefaultHybridThreshold
+	}
+	l := &HybridList[T]{threshold: threshold, manager: NewOrderManager[T](opts...), items: items}
+	l.manager.NormalizePositions(l.items)
+	l.rebalance()
+	return l
+}
+
+// Len returns the number of items.
+func (l *HybridList[T]) Len() int {
+	if l.tree != nil {
+		return l.tree.len()
+	}
+	return len(l.items)
+}
+
+// UsesTree reports whether the list currently uses its tree backend.
+func (l *HybridList[T]) UsesTree() bool {
+	return l.tree != nil
+}
+
+// At returns the item at the given position.
+func (l *HybridList[T]) At(position int) (T, error) {
+	base := l.manager.opts.base()
+	if position < base || position > base+l.Len()-1 {
+		var zero T
+		return zero, &PositionError{Op: "At", Requested: position, Min: base, Max: base + l.Len() - 1}
+	}
+	if l.tree != nil {
+		return l.tree.at(position - base).item, nil
+	}
+	return l.items[position-base], nil
+}
+
+// Position returns the current position of an item.
+func (l *HybridList[T]) Position(id string) (int, error) {
+	index, err := l.index("Position", id)
+	if err != nil {
+		return 0, err
+	}
+	return l.manager.opts.position(index), nil
+}
+
+// Items returns the items in order and writes their positions. In tree mode
+// this builds a new slice.
+func (l *HybridList[T]) Items() []T {
+	if l.tree == nil {
+		return l.items
+	}
+	items := l.tree.items()
+	for i, item := range items {
+		if position := l.manager.opts.position(i); item.GetPosition() != position {
+			item.SetPosition(position)
+		}
+	}
+	return items
+}
+
+// Insert adds item at the given position, shifting later items down. A
+// position one past the last item appends. An ID that is already in the list
+// fails with a *DuplicateIDError.
+func (l *HybridList[T]) Insert(item T, position int) error {
+	base := l.manager.opts.base()
+	if position < base || position > base+l.Len() {
+		return &PositionError{Op: "Insert", Requested: position, Min: base, Max: base + l.Len()}
+	}
+	dest := position - base
+	if index, err := l.index("Insert", item.GetID()); err == nil {
+		return &DuplicateIDError{Op: "Insert", ItemID: item.GetID(), FirstIndex: index, SecondIndex: dest}
+	}
+	if l.tree != nil {
+		n := &treeNode[T]{item: item, priority: newPriority()}
+		l.tree.insert(n, dest)
+		l.nodes[item.GetID()] = n
+	} else {
+		l.items = append(l.items, item)
+		copy(l.items[dest+1:], l.items[dest:])
+		l.items[dest] = item
+		l.manager.normalize(l.items)
+	}
+	l.rebalance()
+	return nil
+}
+
+// Remove takes an item out of the list.
+func (l *HybridList[T]) Remove(id string) error {
+	index, err := l.index("Remove", id)
+	if err != nil {
+		return err
+	}
+	if l.tree != nil {
+		l.tree.remove(index)
+		delete(l.nodes, id)
+	} else {
+		l.items = append(l.items[:index], l.items[index+1:]...)
+		l.manager.normalize(l.items)
+	}
+	l.rebalance()
+	return nil
+}
+
+// To moves an item to the given position.
+func (l *HybridList[T]) To(id string, position int) error {
+	if l.tree == nil {
+		_, err := l.manager.To(l.items, id, position)
+		return err
+	}
+	base := l.manager.opts.base()
+	if position < base || position > base+l.Len()-1 {
+		return &PositionError{Op: "To", Requested: position, Min: base, Max: base + l.Len() - 1}
+	}
+	index, err := l.index("To", id)
+	if err != nil {
+		return err
+	}
+	if isLocked(l.nodes[id].item) {
+		return &LockedError{Op: "To", ItemID: id}
+	}
+	l.tree.insert(l.tree.remove(index), position-base)
+	return nil
+}
+
+// Top moves an item to the first position.
+func (l *HybridList[T]) Top(id string) error {
+	return l.To(id, l.manager.opts.base())
+}
+
+// Bottom moves an item to the last position.
+func (l *HybridList[T]) Bottom(id string) error {
+	return l.To(id, l.manager.opts.base()+l.Len()-1)
+}
+
+// Above moves an item to be directly above the target item, like
+// OrderManager.Above.
+func (l *HybridList[T]) Above(id, targetID string) error {
+	return l.relative("Above", id, targetID, 0)
+}
+
+// Below moves an item to be directly below the target item, like
+// OrderManager.Below.
+func (l *HybridList[T]) Below(id, targetID string) error {
+	return l.relative("Below", id, targetID, 1)
+}
+
+func (l *HybridList[T]) relative(op, id, targetID string, offset int) error {
+	if l.tree == nil {
+		var err error
+		if offset == 0 {
+			_, err = l.manager.Above(l.items, id, targetID)
+		} else {
+			_, err = l.manager.Below(l.items, id, targetID)
+		}
+		return err
+	}
+	index, err := l.index(op, id)
+	if err != nil {
+		return err
+	}
+	target, err := l.index(op, targetID)
+	if err != nil || id == targetID {
+		return err
+	}
+	if isLocked(l.nodes[id].item) {
+		return &LockedError{Op: op, ItemID: id}
+	}
+	insert := target + offset
+	if index < insert {
+		// The target shifts up once the item is removed
+		insert--
+	}
+	l.tree.insert(l.tree.remove(index), insert)
+	return nil
+}
+
+// index returns the 0-based index of an item.
+func (l *HybridList[T]) index(op, id string) (int, error) {
+	if l.tree != nil {
+		if n, ok := l.nodes[id]; ok {
+			return l.tree.index(n), nil
+		}
+	} else {
+		for i, item := range l.items {
+			if item.GetID() == id {
+				return i, nil
+			}
+		}
+	}
+	return -1, &NotFoundError{Op: op, ItemID: id}
+}
+
+// rebalance switches the backend when the list has crossed a threshold.
+func (l *HybridList[T]) rebalance() {
+	switch {
+	case l.tree == nil && len(l.items) > l.threshold:
+		var nodes []*treeNode[T]
+		l.tree, nodes = newTreeList(l.items)
+		l.nodes = make(map[string]*treeNode[T], len(nodes))
+		for _, n := range nodes {
+			l.nodes[n.item.GetID()] = n
+		}
+		l.items = nil
+	case l.tree != nil && l.tree.len() < l.threshold/2:
+		l.items = l.Items()
+		l.tree, l.nodes = nil, nil
+	}
+}
@@ -0,0 +1,146 @@
+package order_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHybridListSwitchesBackend(t *testing.T) {
+	l := order.NewHybridList(ordertest.Items(4), 4)
+	assert.False(t, l.UsesTree())
+
+	require.NoError(t, l.Insert(&ordertest.Item{ID: "new"}, 1))
+	assert.True(t, l.UsesTree())
+	position, err := l.Position("item-4")
+	require.NoError(t, err)
+	assert.Equal(t, 5, position)
+
+	require.NoError(t, l.Remove("new"))
+	require.NoError(t, l.Remove("item-1"))
+	assert.True(t, l.UsesTree())
+	require.NoError(t, l.Remove("item-2"))
+	assert.True(t, l.UsesTree())
+	require.NoError(t, l.Remove("item-3"))
+	assert.False(t, l.UsesTree())
+	items := l.Items()
+	ordertest.AssertOrder(t, items, "item-4")
+	ordertest.AssertNormalized(t, items)
+}
+
+func TestHybridListWithIndexBase(t *testing.T) {
+	for _, threshold := range []int{100, 1} {
+		l := order.NewHybridList(ordertest.Items(3), threshold, order.WithIndexBase(0), order.WithPositionStep(10))
+		require.NoError(t, l.Insert(&ordertest.Item{ID: "new"}, 0))
+		first, err := l.At(0)
+		require.NoError(t, err)
+		assert.Equal(t, "new", first.GetID())
+		require.NoError(t, l.Bottom("new"))
+		position, err := l.Position("new")
+		require.NoError(t, err)
+		assert.Equal(t, 30, position)
+		require.NoError(t, l.Top("new"))
+
+		items := l.Items()
+		ordertest.AssertOrder(t, items, "new", "item-1", "item-2", "item-3")
+		assert.Equal(t, 20, items[2].GetPosition())
+	}
+}
+
+func TestHybridListErrors(t *testing.T) {
+	for _, threshold := range []int{100, 1} {
+		l := order.NewHybridList(ordertest.Items(3), threshold)
+		assert.ErrorIs(t, l.Insert(&ordertest.Item{ID: "item-1"}, 1), order.ErrDuplicateID)
+		assert.ErrorIs(t, l.Insert(&ordertest.Item{ID: "x"}, 5), order.ErrInvalidPosition)
+		assert.ErrorIs(t, l.Remove("x"), order.ErrItemNotFound)
+		assert.ErrorIs(t, l.To("item-1", 4), order.ErrInvalidPosition)
+		assert.ErrorIs(t, l.Above("item-1", "x"), order.ErrItemNotFound)
+		_, err := l.At(0)
+		assert.ErrorIs(t, err, order.ErrInvalidPosition)
+	}
+}
+
+// TestHybridListMatchesModel checks both backends against the reference model
+// with random moves, inserts and removals.
+func TestHybridListMatchesModel(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	for run := 0; run < 20; run++ {
+		items := ordertest.Items(1 + r.Intn(20))
+		model := ordertest.NewModel(ordertest.IDs(items)...)
+		l := order.NewHybridList(items, 1+r.Intn(16))
+		next := len(items)
+
+		for step := 0; step < 100; step++ {
+			ids := model.IDs()
+			n := len(ids)
+			id := fmt.Sprintf("item-%d", 1+r.Intn(next+1))
+			target := fmt.Sprintf("item-%d", 1+r.Intn(next+1))
+			position := 1 + r.Intn(n+1)
+
+			var gotErr, wantErr error
+			switch op := r.Intn(7); op {
+			case 0:
+				gotErr, wantErr = l.To(id, position), model.To(id, position)
+			case 1:
+				gotErr, wantErr = l.Above(id, target), model.Above(id, target)
+			case 2:
+				gotErr, wantErr = l.Below(id, target), model.Below(id, target)
+			case 3:
+				gotErr, wantErr = l.Top(id), model.Top(id)
+			case 4:
+				gotErr, wantErr = l.Bottom(id), model.Bottom(id)
+			case 5:
+				next++
+				newID := fmt.Sprintf("item-%d", next)
+				require.NoError(t, l.Insert(&ordertest.Item{ID: newID}, position))
+				model = ordertest.NewModel(append(ids[:position-1:position-1], append([]string{newID}, ids[position-1:]...)...)...)
+			case 6:
+				if n > 1 {
+					gotErr = l.Remove(id)
+					rest := without(ids, id)
+					if len(rest) == n {
+						wantErr = order.ErrItemNotFound
+					}
+					model = ordertest.NewModel(rest...)
+				}
+			}
+			require.Equal(t, wantErr == nil, gotErr == nil, "step %d: got %v, want %v", step, gotErr, wantErr)
+			if wantErr != nil {
+				require.True(t, errors.Is(gotErr, order.ErrItemNotFound) == errors.Is(wantErr, order.ErrItemNotFound), "step %d: got %v, want %v", step, gotErr, wantErr)
+			}
+			require.Equal(t, model.IDs(), ordertest.IDs(l.Items()), "step %d", step)
+			require.Equal(t, len(model.IDs()), l.Len())
+		}
+		ordertest.AssertNormalized(t, l.Items())
+	}
+}
+
+func without(ids []string, id string) []string {
+	var rest []string
+	for _, other := range ids {
+		if other != id {
+			rest = append(rest, other)
+		}
+	}
+	return rest
+}
+
+func BenchmarkHybridListTo(b *testing.B) {
+	for _, n := range []int{1_000, 100_000} {
+		l := order.NewHybridList(ordertest.Items(n), 0)
+		b.Run(fmt.Sprintf("n=%d", n), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if err := l.To("item-1", 1+i%n); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,197 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// IdempotencyStore remembers the results of moves applied with
+// PersistentManager.ApplyIdempotent by their idempotency key, so that a
+// retried request returns the first result instead of moving the item again.
+// Keys are scoped to a list. A Store that implements IdempotencyStore is used
+// by default; otherwise see WithIdempotencyStore.
+//
+// The key is recorded after the positions are saved, in a separate call, so a
+// crash between the two leaves the move saved but not its key, and a retry
+// applies the move again. Moves are therefore applied at least once, not
+// exactly once.
+//
+// Implementations must be safe for concurrent use.
+type IdempotencyStore interface {
+	// Lookup returns the record of key in listID, if there is one.
+	Lookup(ctx context.Context, listID, key string) (IdempotencyRecord, bool, error)
+	// Record stores the record of key in listID.
+	Record(ctx context.Context, listID, key string, record IdempotencyRecord) error
+}
+
+// IdempotencyRecord is the result of a move as remembered by an
+// IdempotencyStore. It does not hold the affected items, which may have
+// changed since.
+type IdempotencyRecord struct {
+	Move        Move[string] `json:"move"`
+	OperationID string       `json:"operation_id"`
+	Changed     bool         `json:"changed"`
+	OldPosition int          `json:"old_position"`
+	NewPosition int          `json:"new_position"`
+	Changes     ChangeSet    `json:"changes,omitempty"`
+	Version     int64        `json:"version"`
+}
+
+// DefaultIdempotencyTTL is how long the default IdempotencyStore of a
+// PersistentManager remembers a key.
+const DefaultIdempotencyTTL = 24 * time.Hour
+
+// ApplyIdempotent is Apply for requests that may be retried, such as HTTP
+// requests from clients on flaky networks. The first call with a key applies
+// m and records its result; later calls with the same key and move return
+// that result with Replayed set and do not touch the list. Reusing a key for
+// a different move fails with ErrIdempotencyKeyReused. Calls with the same
+// key wait for each other, failed moves are not recorded, and an empty key
+// applies m like Apply.
+//
+// If the move was applied but its result could not be recorded, the result
+// is returned together with the error. An *AuditError from Apply does not
+// stop the key from being recorded.
+func (pm *PersistentManager[T]) ApplyIdempotent(ctx context.Context, listID, key string, m Move[string]) (PersistedResult[T], error) {
+	if key == "" {
+		return pm.Apply(ctx, listID, m)
+	}
+	scoped := listID
+	if pm.check != nil {
+		scoped = TenantListID(pm.tenantID, listID)
+	}
+	unlock, err := pm.lockKey(ctx, scoped, key)
+	if err != nil {
+		return PersistedResult[T]{}, err
+	}
+	defer unlock()
+
+	record, ok, err := pm.opts.idempotency.Lookup(ctx, scoped, key)
+	if err != nil {
+		return PersistedResult[T]{}, fmt.Errorf("ApplyIdempotent %s: %w", listID, err)
+	}
+	if ok {
+		if !record.Move.Equal(m) {
+			return PersistedResult[T]{}, fmt.Errorf("ApplyIdempotent %s: key %q: %w", listID, key, ErrIdempotencyKeyReused)
+		}
+		return PersistedResult[T]{
+			Result:      Result[T]{Changed: record.Changed, OldPosition: record.OldPosition, NewPosition: record.NewPosition},
+			Changes:     record.Changes,
+			Version:     record.Version,
+			OperationID: record.OperationID,
+			Replayed:    true,
+		}, nil
+	}
+
+	result, err := pm.Apply(ctx, listID, m)
+	var auditErr *AuditError
+	if err != nil && !errors.As(err, &auditErr) {
+		return result, err
+	}
+	record = IdempotencyRecord{
+		Move:        m,
+		OperationID: result.OperationID,
+		Changed:     result.Changed,
+		OldPosition: result.OldPosition,
+		NewPosition: result.NewPosition,
+		Changes:     result.Changes,
+		Version:     result.Version,
+	}
+	if err := pm.opts.idempotency.Record(ctx, scoped, key, record); err != nil {
+		return result, fmt.Errorf("ApplyIdempotent %s: recording key %q: %w", listID, key, err)
+	}
+	// The move was saved, so the key is recorded even if its audit failed.
+	return result, err
+}
+
+// lockKey waits until no other call holds key in listID and takes it. The
+// returned function releases it.
+func (pm *PersistentManager[T]) lockKey(ctx context.Context, listID, key string) (func(), error) {
+	k := listID + "\x00" + key
+	for {
+		pm.keysMu.Lock()
+		held, busy := pm.keys[k]
+		if !busy {
+			done := make(chan struct{})
+			pm.keys[k] = done
+			pm.keysMu.Unlock()
+			return func() {
+				pm.keysMu.Lock()
+				delete(pm.keys, k)
+				pm.keysMu.Unlock()
+				close(done)
+			}, nil
+		}
+		pm.keysMu.Unlock()
+		select {
+		case <-held:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// MemoryIdempotencyStore is an in-process IdempotencyStore that forgets keys
+// after a TTL. It is the default of a PersistentManager whose Store does not
+// remember keys itself, which is enough for a single server; servers behind
+// a load balancer need a shared store.
+type MemoryIdempotencyStore struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	records   map[string]memoryIdempotencyEntry
+	lastSweep time.Time
+}
+
+var _ IdempotencyStore = (*MemoryIdempotencyStore)(nil)
+
+type memoryIdempotencyEntry struct {
+	record  IdempotencyRecord
+	expires time.Time
+}
+
+// NewMemoryIdempotencyStore creates an empty MemoryIdempotencyStore that
+// remembers keys for ttl. A ttl of 0 remembers them forever.
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	return &MemoryIdempotencyStore{ttl: ttl, records: make(map[string]memoryIdempotencyEntry)}
+}
+
+// Lookup implements IdempotencyStore.
+func (s *MemoryIdempotencyStore) Lookup(ctx context.Context, listID, key string) (IdempotencyRecord, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.records[listID+"\x00"+key]
+	if !ok || s.expired(entry, time.Now()) {
+		return IdempotencyRecord{}, false, nil
+	}
+	return entry.record, true, nil
+}
+
+// Record implements IdempotencyStore. Expired keys are dropped at most once
+// per TTL, when a key is recorded.
+func (s *MemoryIdempotencyStore) Record(ctx context.Context, listID, key string, record IdempotencyRecord) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	if s.ttl > 0 && now.Sub(s.lastSweep) >= s.ttl {
+		for k, entry := range s.records {
+			if s.expired(entry, now) {
+				delete(s.records, k)
+			}
+		}
+		s.lastSweep = now
+	}
+	entry := memoryIdempotencyEntry{record: record}
+	if s.ttl > 0 {
+		entry.expires = now.Add(s.ttl)
+	}
+	s.records[listID+"\x00"+key] = entry
+	return nil
+}
+
+func (s *MemoryIdempotencyStore) expired(entry memoryIdempotencyEntry, now time.Time) bool {
+	return !entry.expires.IsZero() && !now.Before(entry.expires)
+}
@@ -0,0 +1,140 @@
+package order_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestApplyIdempotent(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	store.Put("other", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	down := order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"}
+
+	first, err := pm.ApplyIdempotent(ctx, "list", "key", down)
+	require.NoError(t, err)
+	assert.False(t, first.Replayed)
+
+	retried, err := pm.ApplyIdempotent(ctx, "list", "key", down)
+	require.NoError(t, err)
+	assert.True(t, retried.Replayed)
+	assert.Equal(t, first.OperationID, retried.OperationID)
+	assert.Equal(t, first.Changes, retried.Changes)
+	assert.Equal(t, first.Version, retried.Version)
+	assert.Equal(t, 2, retried.NewPosition)
+	assert.Equal(t, 1, store.Saves())
+
+	_, err = pm.ApplyIdempotent(ctx, "list", "key", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	assert.ErrorIs(t, err, order.ErrIdempotencyKeyReused)
+
+	// Keys are scoped to a list, and an empty key does not deduplicate.
+	_, err = pm.ApplyIdempotent(ctx, "other", "key", down)
+	require.NoError(t, err)
+	_, err = pm.ApplyIdempotent(ctx, "list", "", down)
+	require.NoError(t, err)
+	assert.Equal(t, 3, store.Saves())
+
+	items, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-2", "item-3", "item-1")
+}
+
+func TestApplyIdempotentComparesExpectedPositionByValue(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	down := func(position int) order.Move[string] {
+		return order.Move[string]{Kind: order.MoveDown, ItemID: "item-1", Expect: order.Precondition[string]{Position: &position}}
+	}
+
+	_, err := pm.ApplyIdempotent(ctx, "list", "key", down(1))
+	require.NoError(t, err)
+	retried, err := pm.ApplyIdempotent(ctx, "list", "key", down(1))
+	require.NoError(t, err)
+	assert.True(t, retried.Replayed)
+	_, err = pm.ApplyIdempotent(ctx, "list", "key", down(2))
+	assert.ErrorIs(t, err, order.ErrIdempotencyKeyReused)
+}
+
+func TestApplyIdempotentDoesNotRecordFailures(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	m := order.Move[string]{Kind: order.MoveTop, ItemID: "item-2"}
+
+	_, err := pm.ApplyIdempotent(ctx, "list", "key", m)
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+
+	store.Put("list", ordertest.Items(2))
+	result, err := pm.ApplyIdempotent(ctx, "list", "key", m)
+	require.NoError(t, err)
+	assert.False(t, result.Replayed)
+	assert.True(t, result.Changed)
+}
+
+func TestApplyIdempotentConcurrentRetries(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(5))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	var wg sync.WaitGroup
+	for range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := pm.ApplyIdempotent(ctx, "list", "key", order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 1, store.Saves())
+}
+
+// idempotentStore is a Store that also remembers idempotency keys.
+type idempotentStore struct {
+	*ordertest.Store[*ordertest.Item]
+	*order.MemoryIdempotencyStore
+}
+
+func TestApplyIdempotentUsesStore(t *testing.T) {
+	ctx := context.Background()
+	store := idempotentStore{ordertest.NewStore[*ordertest.Item](), order.NewMemoryIdempotencyStore(0)}
+	store.Put("list", ordertest.Items(2))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	result, err := pm.ApplyIdempotent(ctx, "list", "key", order.Move[string]{Kind: order.MoveTop, ItemID: "item-2"})
+	require.NoError(t, err)
+	record, ok, err := store.Lookup(ctx, "list", "key")
+	require.NoError(t, err)
+	require.True(t, ok)
+	assert.Equal(t, result.OperationID, record.OperationID)
+	assert.Equal(t, result.Changes, record.Changes)
+}
+
+func TestMemoryIdempotencyStoreExpires(t *testing.T) {
+	ctx := context.Background()
+	s := order.NewMemoryIdempotencyStore(time.Millisecond)
+	require.NoError(t, s.Record(ctx, "list", "key", order.IdempotencyRecord{OperationID: "op"}))
+	record, ok, err := s.Lookup(ctx, "list", "key")
+	require.NoError(t, err)
+	if ok {
+		assert.Equal(t, "op", record.OperationID)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	_, ok, err = s.Lookup(ctx, "list", "key")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
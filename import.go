@@ -0,0 +1,143 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ItemInserter adds items, with their positions set, to a list without a
+// version check and bumps the version of the list, creating the list if it
+// does not exist. Stores that support ImportOrdered implement it.
+type ItemInserter[T Orderable] interface {
+	InsertItems(ctx context.Context, listID string, items []T) error
+}
+
+// ImportStore is a Store that can shift positions and add items in bulk, as
+// needed by ImportOrdered.
+type ImportStore[T Orderable] interface {
+	Store[T]
+	PositionWriter
+	ItemInserter[T]
+}
+
+// ImportPlacement decides where ImportOrdered puts the imported items. Create
+// one with ImportAtTop, ImportAtBottom or ImportAfter; the zero value imports
+// at the bottom.
+type ImportPlacement struct {
+	kind     importKind
+	anchorID string
+}
+
+type importKind int
+
+const (
+	importAtBottom importKind = iota
+	importAtTop
+	importAfter
+)
+
+// ImportAtTop puts the imported items above the existing ones.
+func ImportAtTop() ImportPlacement {
+	return ImportPlacement{kind: importAtTop}
+}
+
+// ImportAtBottom puts the imported items below the existing ones.
+func ImportAtBottom() ImportPlacement {
+	return ImportPlacement{kind: importAtBottom}
+}
+
+// ImportAfter puts the imported items directly below the item with anchorID.
+func ImportAfter(anchorID string) ImportPlacement {
+	return ImportPlacement{kind: importAfter, anchorID: anchorID}
+}
+
+// ImportOrdered adds newItems to a list in one go, keeping their order in the
+// input, at the place set by at. It loads the list once, gives the items the
+// positions following the place and sets them on the items, renumbers the
+// existing items around them, then adds the new items, writing both in batches
+// as set by opts. A list that does not exist yet is created. The returned
+// ChangeSet lists every changed position in the new order, with an
+// OldPosition of 0 for the imported items, like in Diff.
+//
+// IDs that are already in the list or occur twice in newItems fail with a
+// *DuplicateIDError, and an unknown anchor with a *NotFoundError, before
+// anything is written. Like NormalizeList, ImportOrdered bypasses version
+// checks, so run it while the list is not being edited. If it fails part
+// way, the batches reported through opts.Progress have been written.
+func ImportOrdered[T Orderable](ctx context.Context, store ImportStore[T], listID string, newItems []T, at ImportPlacement, opts BatchOptions) (ChangeSet, error) {
+	items, _, err := store.Load(ctx, listID)
+	if err != nil && !errors.Is(err, ErrListNotFound) {
+		return nil, fmt.Errorf("ImportOrdered %s: %w", listID, err)
+	}
+
+	seen := make(map[string]int, len(items)+len(newItems))
+	for i, item := range items {
+		seen[item.GetID()] = i
+	}
+	for i, item := range newItems {
+		if first, ok := seen[item.GetID()]; ok {
+			return nil, &DuplicateIDError{Op: "ImportOrdered", ItemID: item.GetID(), FirstIndex: first, SecondIndex: len(items) + i}
+		}
+		seen[item.GetID()] = len(items) + i
+	}
+
+	index := len(items)
+	switch at.kind {
+	case importAtTop:
+		index = 0
+	case importAfter:
+		i, ok := seen[at.anchorID]
+		if !ok || i >= len(items) {
+			return nil, &NotFoundError{Op: "ImportOrdered", ItemID: at.anchorID}
+		}
+		index = i + 1
+	}
+
+	var above, below ChangeSet
+	for i, item := range items {
+		position := i + 1
+		if i >= index {
+			position += len(newItems)
+		}
+		if old := item.GetPosition(); old != position {
+			change := PositionChange{ItemID: item.GetID(), OldPosition: old, NewPosition: position}
+			if i < index {
+				above = append(above, change)
+			} else {
+				below = append(below, change)
+			}
+		}
+	}
+	imported := make(ChangeSet, len(newItems))
+	for i, item := range newItems {
+		item.SetPosition(index + i + 1)
+		imported[i] = PositionChange{ItemID: item.GetID(), NewPosition: index + i + 1}
+	}
+
+	bw := newBatchWriter(store, listID, opts)
+	bw.progress.Total = len(above) + len(below) + len(newItems)
+	if err := bw.write(ctx, append(above, below...)); err != nil {
+		return nil, fmt.Errorf("ImportOrdered %s: %w", listID, err)
+	}
+	for batch := newItems; len(batch) > 0; {
+		if err := bw.pause(ctx); err != nil {
+			return nil, fmt.Errorf("ImportOrdered %s: %w", listID, err)
+		}
+		n := min(bw.opts.Size, len(batch))
+		if err := store.InsertItems(ctx, listID, batch[:n]); err != nil {
+			return nil, fmt.Errorf("ImportOrdered %s: insert batch %d: %w", listID, bw.progress.Batches+1, err)
+		}
+		batch = batch[n:]
+		bw.progress.Batches++
+		bw.progress.Written += n
+		if bw.opts.Progress != nil {
+			bw.opts.Progress(bw.progress)
+		}
+	}
+
+	changes := make(ChangeSet, 0, bw.progress.Total)
+	changes = append(changes, above...)
+	changes = append(changes, imported...)
+	return append(changes, below...), nil
+}
@@ -0,0 +1,80 @@
+package order_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportOrdered(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.ItemsWithIDs("a", "b", "c"))
+
+	var progress []order.BatchProgress
+	changes, err := order.ImportOrdered(ctx, store, "list", ordertest.ItemsWithIDs("x", "y", "z"), order.ImportAfter("a"), order.BatchOptions{
+		Size:     2,
+		Progress: func(p order.BatchProgress) { progress = append(progress, p) },
+	})
+	require.NoError(t, err)
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "x", NewPosition: 2},
+		{ItemID: "y", NewPosition: 3},
+		{ItemID: "z", NewPosition: 4},
+		{ItemID: "b", OldPosition: 2, NewPosition: 5},
+		{ItemID: "c", OldPosition: 3, NewPosition: 6},
+	}, changes)
+	assert.Equal(t, []order.BatchProgress{
+		{Batches: 1, Written: 2, Total: 5},
+		{Batches: 2, Written: 4, Total: 5},
+		{Batches: 3, Written: 5, Total: 5},
+	}, progress)
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "a", "x", "y", "z", "b", "c")
+	ordertest.AssertNormalized(t, items)
+}
+
+func TestImportOrderedPlacements(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+
+	_, err := order.ImportOrdered(ctx, store, "new", ordertest.ItemsWithIDs("a", "b"), order.ImportAtBottom(), order.BatchOptions{})
+	require.NoError(t, err)
+	_, err = order.ImportOrdered(ctx, store, "new", ordertest.ItemsWithIDs("c"), order.ImportPlacement{}, order.BatchOptions{})
+	require.NoError(t, err)
+	changes, err := order.ImportOrdered(ctx, store, "new", ordertest.ItemsWithIDs("top"), order.ImportAtTop(), order.BatchOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"top", "a", "b", "c"}, changes.IDs())
+
+	items, _, err := store.Load(ctx, "new")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "top", "a", "b", "c")
+	ordertest.AssertNormalized(t, items)
+}
+
+func TestImportOrderedRejects(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.ItemsWithIDs("a", "b"))
+	version := store.Version("list")
+
+	_, err := order.ImportOrdered(ctx, store, "list", ordertest.ItemsWithIDs("x", "b"), order.ImportAtTop(), order.BatchOptions{})
+	var dup *order.DuplicateIDError
+	require.ErrorAs(t, err, &dup)
+	assert.Equal(t, "b", dup.ItemID)
+
+	_, err = order.ImportOrdered(ctx, store, "list", ordertest.ItemsWithIDs("x", "x"), order.ImportAtTop(), order.BatchOptions{})
+	assert.ErrorIs(t, err, order.ErrDuplicateID)
+
+	_, err = order.ImportOrdered(ctx, store, "list", ordertest.ItemsWithIDs("x"), order.ImportAfter("x"), order.BatchOptions{})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+
+	assert.Equal(t, version, store.Version("list"))
+}
@@ -0,0 +1,22 @@
+package order
+
+import (
+	"slices"
+	"sort"
+)
+
+// InsertSorted adds item to items at the position less dictates and renumbers
+// the list, so that "insert alphabetically, but allow manual moves afterwards"
+// is one call. The position is found by binary search, which assumes items is
+// sorted by less; on a list that was reordered by hand since, the item lands
+// somewhere consistent with its neighbors at the point the search ends. The
+// item goes after items that compare equal to it.
+//
+// It returns the new list and the changed positions, with an OldPosition of 0
+// for item, like in Diff. The slice passed in must not be used afterwards.
+func InsertSorted[T Orderable](items []T, item T, less func(a, b T) bool) ([]T, ChangeSet) {
+	i := sort.Search(len(items), func(i int) bool { return less(item, items[i]) })
+	items = slices.Insert(items, i, item)
+	item.SetPosition(0)
+	return items, renumber(items)
+}
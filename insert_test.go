@@ -0,0 +1,43 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestInsertSorted(t *testing.T) {
+	byID := func(a, b *ordertest.Item) bool { return a.ID < b.ID }
+	items := ordertest.ItemsWithIDs("b", "d", "f")
+
+	items, changes := order.InsertSorted(items, &ordertest.Item{ID: "c", Position: 9}, byID)
+	ordertest.AssertOrder(t, items, "b", "c", "d", "f")
+	ordertest.AssertNormalized(t, items)
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "c", OldPosition: 0, NewPosition: 2},
+		{ItemID: "d", OldPosition: 2, NewPosition: 3},
+		{ItemID: "f", OldPosition: 3, NewPosition: 4},
+	}, changes)
+
+	items, changes = order.InsertSorted(items, &ordertest.Item{ID: "g"}, byID)
+	ordertest.AssertOrder(t, items, "b", "c", "d", "f", "g")
+	assert.Equal(t, order.ChangeSet{{ItemID: "g", OldPosition: 0, NewPosition: 5}}, changes)
+
+	items, _ = order.InsertSorted(items, &ordertest.Item{ID: "a"}, byID)
+	ordertest.AssertOrder(t, items, "a", "b", "c", "d", "f", "g")
+
+	empty, changes := order.InsertSorted(nil, &ordertest.Item{ID: "x"}, byID)
+	ordertest.AssertOrder(t, empty, "x")
+	assert.Len(t, changes, 1)
+}
+
+func TestInsertSortedAfterEqual(t *testing.T) {
+	items := []*ordertest.Item{{ID: "1", Name: "apple", Position: 1}, {ID: "2", Name: "pear", Position: 2}}
+	byName := func(a, b *ordertest.Item) bool { return a.Name < b.Name }
+
+	items, _ = order.InsertSorted(items, &ordertest.Item{ID: "3", Name: "apple"}, byName)
+	ordertest.AssertOrder(t, items, "1", "3", "2")
+}
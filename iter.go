@@ -0,0 +1,62 @@
+package order
+
+import "iter"
+
+// All returns an iterator over the items of the collection in order.
+func (c *OrderedCollection[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range c.items {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Backward returns an iterator over the items of the collection from last to first.
+func (c *OrderedCollection[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := len(c.items) - 1; i >= 0; i-- {
+			if !yield(c.items[i]) {
+				return
+			}
+		}
+	}
+}
+
+// FromPosition returns an iterator over the items at position p and after.
+// A position below 1 starts at the first item.
+func (c *OrderedCollection[T]) FromPosition(p int) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, item := range c.items[min(max(p-1, 0), len(c.items)):] {
+			if !yield(item) {
+				return
+			}
+		}
+	}
+}
+
+// Between returns an iterator over the items from idA to idB, both included.
+// If idB comes before idA, the items are yielded in descending order. Nothing is
+// yielded if either ID is not in the collection.
+func (c *OrderedCollection[T]) Between(idA, idB string) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		from, err := c.manager.GetItemIndexByID(c.items, idA)
+		if err != nil {
+			return
+		}
+		to, err := c.manager.GetItemIndexByID(c.items, idB)
+		if err != nil {
+			return
+		}
+		step := 1
+		if to < from {
+			step = -1
+		}
+		for i := from; ; i += step {
+			if !yield(c.items[i]) || i == to {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package order_test
+
+import (
+	"iter"
+	"slices"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func collectIDs(seq iter.Seq[*TestItem]) []string {
+	var ids []string
+	for item := range seq {
+		ids = append(ids, item.GetID())
+	}
+	return ids
+}
+
+func TestCollectionAllAndBackward(t *testing.T) {
+	items := createTestItems(3)
+	c := order.NewOrderedCollection(items)
+	ids := []string{items[0].GetID(), items[1].GetID(), items[2].GetID()}
+
+	assert.Equal(t, ids, collectIDs(c.All()))
+	slices.Reverse(ids)
+	assert.Equal(t, ids, collectIDs(c.Backward()))
+
+	for item := range c.All() {
+		assert.Equal(t, items[0], item)
+		break
+	}
+}
+
+func TestCollectionFromPosition(t *testing.T) {
+	items := createTestItems(4)
+	c := order.NewOrderedCollection(items)
+
+	assert.Equal(t, []string{items[2].GetID(), items[3].GetID()}, collectIDs(c.FromPosition(3)))
+	assert.Len(t, collectIDs(c.FromPosition(0)), 4)
+	assert.Empty(t, collectIDs(c.FromPosition(9)))
+}
+
+func TestCollectionBetween(t *testing.T) {
+	items := createTestItems(5)
+	c := order.NewOrderedCollection(items)
+
+	assert.Equal(t, []string{items[1].GetID(), items[2].GetID(), items[3].GetID()},
+		collectIDs(c.Between(items[1].GetID(), items[3].GetID())))
+	assert.Equal(t, []string{items[3].GetID(), items[2].GetID()},
+		collectIDs(c.Between(items[3].GetID(), items[2].GetID())))
+	assert.Equal(t, []string{items[4].GetID()}, collectIDs(c.Between(items[4].GetID(), items[4].GetID())))
+	assert.Empty(t, collectIDs(c.Between(items[0].GetID(), "missing")))
+}
@@ -0,0 +1,211 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Laned is implemented by items that belong to a lane, such as a priority. Lanes
+// have a fixed order; manual ordering applies within each lane, where positions
+// start from 1.
+type Laned[L comparable] interface {
+	Orderable
+	GetLane() L
+	SetLane(lane L)
+}
+
+// LaneManager orders items grouped into lanes. It expects the slice to hold the
+// lanes one after the other in lane order, as arranged by Sort.
+type LaneManager[T Laned[L], L comparable] struct {
+	lanes   []L
+	rank    map[L]int
+	manager *OrderManager[T]
+}
+
+// NewLaneManager creates a LaneManager for the given lanes, listed in order. The
+// options configure the moves within a lane.
+func NewLaneManager[T Laned[L], L comparable](lanes []L, opts ...Option) *LaneManager[T, L] {
+	rank := make(map[L]int, len(lanes))
+	for i, lane := range lanes {
+		rank[lane] = i
+	}
+	return &LaneManager[T, L]{lanes: lanes, rank: rank, manager: NewOrderManager[T](opts...)}
+}
+
+// Sort groups items by lane in lane order, keeps their order by position within
+// each lane and renumbers every lane from the index base. It fails with ErrUnknownLane if an
+// item's lane was not passed to NewLaneManager; items is left unchanged then.
+func (lm *LaneManager[T, L]) Sort(items []T) error {
+	for _, item := range items {
+		if _, ok := lm.rank[item.GetLane()]; !ok {
+			return fmt.Errorf("Sort: item %s has lane %v: %w", item.GetID(), item.GetLane(), ErrUnknownLane)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		ri, rj := lm.rank[items[i].GetLane()], lm.rank[items[j].GetLane()]
+		if ri != rj {
+			return ri < rj
+		}
+		return items[i].GetPosition() < items[j].GetPosition()
+	})
+	for _, lane := range lm.lanes {
+		lm.manager.normalize(lm.Lane(items, lane))
+	}
+	return nil
+}
+
+// Lane returns the items of lane as a subslice of items.
+func (lm *LaneManager[T, L]) Lane(items []T, lane L) []T {
+	start, end := lm.laneRange(items, lane)
+	return items[start:end]
+}
+
+// laneRange returns the bounds of lane within items.
+func (lm *LaneManager[T, L]) laneRange(items []T, lane L) (int, int) {
+	rank := lm.rank[lane]
+	start := 0
+	for start < len(items) && lm.rank[items[start].GetLane()] < rank {
+		start++
+	}
+	end := start
+	for end < len(items) && items[end].GetLane() == lane {
+		end++
+	}
+	return start, end
+}
+
+// laneOf returns the lane of the item with the given ID as a subslice of items.
+func (lm *LaneManager[T, L]) laneOf(items []T, itemID string) ([]T, error) {
+	index, err := lm.manager.GetItemIndexByID(items, itemID)
+	if err != nil {
+		return nil, err
+	}
+	return lm.Lane(items, items[index].GetLane()), nil
+}
+
+// Up moves an item up by one position within its lane.
+func (lm *LaneManager[T, L]) Up(items []T, itemID string) (Result[T], error) {
+	lane, err := lm.laneOf(items, itemID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return lm.manager.Up(lane, itemID)
+}
+
+// Down moves an item down by one position within its lane.
+func (lm *LaneManager[T, L]) Down(items []T, itemID string) (Result[T], error) {
+	lane, err := lm.laneOf(items, itemID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return lm.manager.Down(lane, itemID)
+}
+
+// To moves an item to a position within its lane.
+func (lm *LaneManager[T, L]) To(items []T, itemID string, newPosition int) (Result[T], error) {
+	lane, err := lm.laneOf(items, itemID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return lm.manager.To(lane, itemID, newPosition)
+}
+
+// Top moves an item to the first position of its lane.
+func (lm *LaneManager[T, L]) Top(items []T, itemID string) (Result[T], error) {
+	lane, err := lm.laneOf(items, itemID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return lm.manager.Top(lane, itemID)
+}
+
+// Bottom moves an item to the last position of its lane.
+func (lm *LaneManager[T, L]) Bottom(items []T, itemID string) (Result[T], error) {
+	lane, err := lm.laneOf(items, itemID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	return lm.manager.Bottom(lane, itemID)
+}
+
+// Above moves an item directly above the target item, moving it into the
+// target's lane if needed.
+func (lm *LaneManager[T, L]) Above(items []T, itemID, targetID string) (Result[T], error) {
+	return lm.relative("Above", items, itemID, targetID, 0)
+}
+
+// Below moves an item directly below the target item, moving it into the
+// target's lane if needed.
+func (lm *LaneManager[T, L]) Below(items []T, itemID, targetID string) (Result[T], error) {
+	return lm.relative("Below", items, itemID, targetID, 1)
+}
+
+func (lm *LaneManager[T, L]) relative(op string, items []T, itemID, targetID string, offset int) (Result[T], error) {
+	index, err := lm.manager.GetItemIndexByID(items, itemID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	targetIndex, err := lm.manager.GetItemIndexByID(items, targetID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	lane := items[targetIndex].GetLane()
+	if items[index].GetLane() == lane {
+		if op == "Above" {
+			return lm.manager.Above(lm.Lane(items, lane), itemID, targetID)
+		}
+		return lm.manager.Below(lm.Lane(items, lane), itemID, targetID)
+	}
+	start, _ := lm.laneRange(items, lane)
+	return lm.MoveToLane(items, itemID, lane, lm.manager.opts.base()+targetIndex-start+offset)
+}
+
+// MoveToLane moves an item into lane at the given position, counted from the
+// index base like the positions of To, and renumbers the lanes it left and
+// entered. Within the item's own lane it behaves like To.
+// Affected includes the moved item, whose lane changed even if its position did
+// not.
+func (lm *LaneManager[T, L]) MoveToLane(items []T, itemID string, lane L, position int) (result Result[T], err error) {
+	defer lm.manager.instrument("MoveToLane", items, itemID)(&result, &err)
+	if _, ok := lm.rank[lane]; !ok {
+		return Result[T]{}, fmt.Errorf("MoveToLane: lane %v: %w", lane, ErrUnknownLane)
+	}
+	index, err := lm.manager.GetItemIndexByID(items, itemID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	item := items[index]
+	oldLane := item.GetLane()
+	if oldLane == lane {
+		return lm.manager.to(lm.Lane(items, lane), itemID, position)
+	}
+	start, end := lm.laneRange(items, lane)
+	base := lm.manager.opts.base()
+	if position < base || position > base+end-start {
+		return Result[T]{}, &PositionError{Op: "MoveToLane", Requested: position, Min: base, Max: base + end - start}
+	}
+	if isLocked(item) {
+		return Result[T]{}, &LockedError{Op: "MoveToLane", ItemID: itemID}
+	}
+
+	dest := start + position - base
+	if index < dest {
+		// The lane shifts up once the item is removed
+		dest--
+		copy(items[index:dest], items[index+1:dest+1])
+	} else {
+		copy(items[dest+1:index+1], items[dest:index])
+	}
+	items[dest] = item
+
+	oldPosition := item.GetPosition()
+	item.SetLane(lane)
+	// Reset the position so the moved item is always reported as affected.
+	item.SetPosition(lm.manager.opts.position(-1))
+	first, second := lm.Lane(items, oldLane), lm.Lane(items, lane)
+	if lm.rank[lane] < lm.rank[oldLane] {
+		first, second = second, first
+	}
+	affected := append(lm.manager.normalize(first), lm.manager.normalize(second)...)
+	return Result[T]{Changed: true, OldPosition: oldPosition, NewPosition: item.GetPosition(), Affected: affected}, nil
+}
@@ -0,0 +1,120 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// LanedItem extends TestItem with a priority lane.
+type LanedItem struct {
+	TestItem
+	Lane string
+}
+
+func (li *LanedItem) GetLane() string     { return li.Lane }
+func (li *LanedItem) SetLane(lane string) { li.Lane = lane }
+
+func createLanedItems(lanes ...string) []*LanedItem {
+	items := make([]*LanedItem, len(lanes))
+	for i, item := range createTestItems(len(lanes)) {
+		items[i] = &LanedItem{TestItem: *item, Lane: lanes[i]}
+	}
+	return items
+}
+
+func laneSummary(items []*LanedItem) []string {
+	summary := make([]string, len(items))
+	for i, item := range items {
+		summary[i] = item.Lane + "/" + string(rune('0'+item.Position))
+	}
+	return summary
+}
+
+func TestLaneManagerSort(t *testing.T) {
+	lm := order.NewLaneManager[*LanedItem]([]string{"P0", "P1", "P2"})
+	items := createLanedItems("P2", "P0", "P1", "P0")
+
+	require.NoError(t, lm.Sort(items))
+	assert.Equal(t, []string{"P0/1", "P0/2", "P1/1", "P2/1"}, laneSummary(items))
+	assert.Len(t, lm.Lane(items, "P0"), 2)
+	assert.Empty(t, lm.Lane(items, "P3"))
+
+	items[0].Lane = "P9"
+	assert.ErrorIs(t, lm.Sort(items), order.ErrUnknownLane)
+}
+
+func TestLaneManagerMovesWithinLane(t *testing.T) {
+	lm := order.NewLaneManager[*LanedItem]([]string{"P0", "P1"})
+	items := createLanedItems("P0", "P0", "P1", "P1")
+	require.NoError(t, lm.Sort(items))
+	last := items[3]
+
+	result, err := lm.Top(items, last.GetID())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewPosition)
+	assert.Same(t, last, items[2])
+
+	_, err = lm.Up(items, last.GetID())
+	require.NoError(t, err)
+	assert.Same(t, last, items[2], "Up must not leave the lane")
+	assert.Equal(t, []string{"P0/1", "P0/2", "P1/1", "P1/2"}, laneSummary(items))
+}
+
+func TestLaneManagerMoveToLane(t *testing.T) {
+	lm := order.NewLaneManager[*LanedItem]([]string{"P0", "P1", "P2"})
+	items := createLanedItems("P0", "P1", "P1", "P2")
+	require.NoError(t, lm.Sort(items))
+	moved := items[3]
+
+	result, err := lm.MoveToLane(items, moved.GetID(), "P0", 1)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.OldPosition)
+	assert.Equal(t, 1, result.NewPosition)
+	assert.Contains(t, result.Affected, moved)
+	assert.Equal(t, "P0", moved.Lane)
+	assert.Same(t, moved, items[0])
+	assert.Equal(t, []string{"P0/1", "P0/2", "P1/1", "P1/2"}, laneSummary(items))
+
+	_, err = lm.Below(items, moved.GetID(), items[2].GetID())
+	require.NoError(t, err)
+	assert.Same(t, moved, items[2])
+	assert.Equal(t, []string{"P0/1", "P1/1", "P1/2", "P1/3"}, laneSummary(items))
+
+	_, err = lm.MoveToLane(items, moved.GetID(), "P2", 3)
+	var posErr *order.PositionError
+	assert.ErrorAs(t, err, &posErr)
+	_, err = lm.MoveToLane(items, moved.GetID(), "P7", 1)
+	assert.ErrorIs(t, err, order.ErrUnknownLane)
+}
+
+func TestLaneManagerMoveToLaneWithIndexBase(t *testing.T) {
+	lm := order.NewLaneManager[*LanedItem]([]string{"P0", "P1"}, order.WithIndexBase(0), order.WithPositionStep(10))
+	items := createLanedItems("P0", "P0", "P1")
+	require.NoError(t, lm.Sort(items))
+	moved := items[2]
+
+	result, err := lm.MoveToLane(items, moved.GetID(), "P0", 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, result.NewPosition)
+	assert.Contains(t, result.Affected, moved)
+	assert.Same(t, moved, items[0])
+	assert.Equal(t, []int{0, 10, 20}, []int{items[0].Position, items[1].Position, items[2].Position})
+
+	_, err = lm.MoveToLane(items, moved.GetID(), "P1", 1)
+	var posErr *order.PositionError
+	require.ErrorAs(t, err, &posErr)
+	assert.Equal(t, 0, posErr.Max)
+
+	// Below the last item of P0 is its slot 2, not its position 20 plus one.
+	other := createLanedItems("P1")[0]
+	items = append(items, other)
+	require.NoError(t, lm.Sort(items))
+	_, err = lm.Below(items, other.GetID(), items[2].GetID())
+	require.NoError(t, err)
+	assert.Same(t, other, items[3])
+	assert.Equal(t, "P0", other.Lane)
+}
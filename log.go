@@ -0,0 +1,75 @@
+package order
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogLevels sets the levels at which an OrderManager configured with WithLogger
+// logs each kind of event.
+type LogLevels struct {
+	// Operation is used for moves that succeeded.
+	Operation slog.Level
+	// Failure is used for moves that returned an error.
+	Failure slog.Level
+	// Validation is used when strict validation rejects a slice.
+	Validation slog.Level
+	// Rebalance is used for NormalizePositions.
+	Rebalance slog.Level
+}
+
+// DefaultLogLevels are the levels used by WithLogger unless WithLogLevels is given.
+var DefaultLogLevels = LogLevels{
+	Operation:  slog.LevelDebug,
+	Failure:    slog.LevelWarn,
+	Validation: slog.LevelWarn,
+	Rebalance:  slog.LevelInfo,
+}
+
+func (o *options) levels() LogLevels {
+	if o.logLevels != nil {
+		return *o.logLevels
+	}
+	return DefaultLogLevels
+}
+
+func (os *KeyedManager[T, ID]) logOperation(stats OperationStats, itemID ID) {
+	levels := os.opts.levels()
+	level, msg := levels.Operation, "order operation"
+	switch {
+	case stats.Err != nil:
+		level, msg = levels.Failure, "order operation failed"
+	case stats.Op == "NormalizePositions":
+		level, msg = levels.Rebalance, "order positions normalized"
+	}
+
+	attrs := []slog.Attr{
+		slog.String("op", stats.Op),
+		slog.Int("list_size", stats.ListSize),
+		slog.Int("affected", stats.Affected),
+		slog.Duration("duration", stats.Duration),
+	}
+	var none ID
+	if itemID != none {
+		attrs = append(attrs, slog.String("item_id", formatID(itemID)))
+	}
+	if stats.Err != nil {
+		attrs = append(attrs, slog.Any("error", stats.Err))
+	}
+	os.opts.logger.LogAttrs(context.Background(), level, msg, attrs...)
+}
+
+func (os *KeyedManager[T, ID]) logValidationFailure(err *ValidationError) {
+	if os.opts.logger == nil {
+		return
+	}
+	kinds := make([]string, len(err.Issues))
+	for i, issue := range err.Issues {
+		kinds[i] = issue.Kind.String()
+	}
+	os.opts.logger.LogAttrs(context.Background(), os.opts.levels().Validation, "order validation failed",
+		slog.Int("issues", len(err.Issues)),
+		slog.Any("kinds", kinds),
+		slog.String("first", err.Issues[0].Message),
+	)
+}
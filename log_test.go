@@ -0,0 +1,77 @@
+package order_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func logRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var records []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var record map[string]any
+		require.NoError(t, json.Unmarshal([]byte(line), &record))
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	os := order.NewOrderManager[*TestItem](order.WithLogger(logger))
+	items := createTestItems(3)
+
+	_, err := os.Down(items, items[0].GetID())
+	require.NoError(t, err)
+	_, err = os.To(items, items[0].GetID(), 0)
+	require.Error(t, err)
+	os.NormalizePositions(items)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 3)
+
+	assert.Equal(t, "DEBUG", records[0]["level"])
+	assert.Equal(t, "Down", records[0]["op"])
+	assert.Equal(t, float64(2), records[0]["affected"])
+	assert.NotEmpty(t, records[0]["item_id"])
+
+	assert.Equal(t, "WARN", records[1]["level"])
+	assert.Contains(t, records[1]["error"], "invalid position")
+
+	assert.Equal(t, "INFO", records[2]["level"])
+	assert.Equal(t, "NormalizePositions", records[2]["op"])
+}
+
+func TestLoggerValidationFailure(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&buf, nil))
+	os := order.NewOrderManager[*TestItem](
+		order.WithLogger(logger),
+		order.WithStrictValidation(),
+		order.WithLogLevels(order.LogLevels{Validation: slog.LevelError, Failure: slog.LevelInfo}),
+	)
+	items := createTestItems(2)
+	items[1].SetPosition(1)
+
+	_, err := os.Up(items, items[1].GetID())
+	require.Error(t, err)
+
+	records := logRecords(t, &buf)
+	require.Len(t, records, 2)
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.Equal(t, "order validation failed", records[0]["msg"])
+	assert.Equal(t, []any{"duplicate position"}, records[0]["kinds"])
+	assert.Equal(t, "INFO", records[1]["level"])
+}
@@ -0,0 +1,76 @@
+package order
+
+import "slices"
+
+// IndexMap returns the index of every item by its ID, for callers that look up
+// many items, where calling GetItemIndexByID for each would be quadratic. For
+// an ID that occurs more than once it holds the first index.
+func (os *KeyedManager[T, ID]) IndexMap(items []T) map[ID]int {
+	index := make(map[ID]int, len(items))
+	for i, item := range items {
+		if _, ok := index[os.getID(item)]; !ok {
+			index[os.getID(item)] = i
+		}
+	}
+	return index
+}
+
+// PositionOf returns the position of the item with itemID. It fails like
+// GetItemIndexByID.
+func (os *KeyedManager[T, ID]) PositionOf(items []T, itemID ID) (int, error) {
+	index, err := os.GetItemIndexByID(items, itemID)
+	if err != nil {
+		return 0, err
+	}
+	return os.getPos(items[index]), nil
+}
+
+// Distance returns how many slots the item with idB is below the item with
+// idA: positive if it is below, negative if it is above and 0 for the same
+// item. It fails like GetItemIndexByID.
+func (os *KeyedManager[T, ID]) Distance(items []T, idA, idB ID) (int, error) {
+	a, err := os.GetItemIndexByID(items, idA)
+	if err != nil {
+		return 0, err
+	}
+	b, err := os.GetItemIndexByID(items, idB)
+	if err != nil {
+		return 0, err
+	}
+	return b - a, nil
+}
+
+// Between returns the items between the items with idA and idB in list order,
+// whichever of the two comes first. With inclusive it includes those two
+// items as well. It fails like GetItemIndexByID. The returned slice shares the
+// items, but not the backing array, with items.
+func (os *KeyedManager[T, ID]) Between(items []T, idA, idB ID, inclusive bool) ([]T, error) {
+	a, err := os.GetItemIndexByID(items, idA)
+	if err != nil {
+		return nil, err
+	}
+	b, err := os.GetItemIndexByID(items, idB)
+	if err != nil {
+		return nil, err
+	}
+	from, to := min(a, b), max(a, b)
+	if !inclusive {
+		from++
+		to = max(to-1, from-1)
+	}
+	return slices.Clone(items[from : to+1]), nil
+}
+
+// GetRange returns the items from position fromPos to toPos, both included,
+// counting positions from 1 in slice order as after NormalizePositions. It
+// fails with a *PositionError unless 1 <= fromPos <= toPos <= len(items). The
+// returned slice shares the items, but not the backing array, with items.
+func (os *KeyedManager[T, ID]) GetRange(items []T, fromPos, toPos int) ([]T, error) {
+	if fromPos < 1 || fromPos > len(items) {
+		return nil, &PositionError{Op: "GetRange", Requested: fromPos, Min: 1, Max: len(items)}
+	}
+	if toPos < fromPos || toPos > len(items) {
+		return nil, &PositionError{Op: "GetRange", Requested: toPos, Min: fromPos, Max: len(items)}
+	}
+	return slices.Clone(items[fromPos-1 : toPos]), nil
+}
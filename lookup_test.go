@@ -0,0 +1,100 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIndexMap(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c", "b")
+
+	assert.Equal(t, map[string]int{"a": 0, "b": 1, "c": 2}, om.IndexMap(items))
+	assert.Empty(t, om.IndexMap(nil))
+}
+
+func TestPositionOf(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c")
+	items[2].Position = 7
+
+	position, err := om.PositionOf(items, "c")
+	require.NoError(t, err)
+	assert.Equal(t, 7, position)
+
+	_, err = om.PositionOf(items, "x")
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+
+	strict := order.NewOrderManager[*ordertest.Item](order.WithDuplicateDetection())
+	_, err = strict.PositionOf(ordertest.ItemsWithIDs("a", "a"), "a")
+	assert.ErrorIs(t, err, order.ErrDuplicateID)
+}
+
+func TestDistance(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c", "d")
+
+	distance, err := om.Distance(items, "a", "d")
+	require.NoError(t, err)
+	assert.Equal(t, 3, distance)
+	distance, err = om.Distance(items, "c", "b")
+	require.NoError(t, err)
+	assert.Equal(t, -1, distance)
+	distance, err = om.Distance(items, "b", "b")
+	require.NoError(t, err)
+	assert.Zero(t, distance)
+
+	_, err = om.Distance(items, "a", "x")
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
+
+func TestBetween(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c", "d", "e")
+
+	between, err := om.Between(items, "b", "e", false)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, between, "c", "d")
+	between, err = om.Between(items, "e", "b", true)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, between, "b", "c", "d", "e")
+
+	between, err = om.Between(items, "b", "c", false)
+	require.NoError(t, err)
+	assert.Empty(t, between)
+	between, err = om.Between(items, "c", "c", false)
+	require.NoError(t, err)
+	assert.Empty(t, between)
+	between, err = om.Between(items, "c", "c", true)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, between, "c")
+
+	_, err = om.Between(items, "x", "c", true)
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
+
+func TestGetRange(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.Items(5)
+
+	window, err := om.GetRange(items, 2, 4)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, window, "item-2", "item-3", "item-4")
+	window, err = om.GetRange(items, 5, 5)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, window, "item-5")
+
+	for _, bounds := range [][2]int{{0, 2}, {6, 6}, {3, 2}, {1, 6}} {
+		_, err := om.GetRange(items, bounds[0], bounds[1])
+		assert.ErrorIs(t, err, order.ErrInvalidPosition, "bounds %v", bounds)
+	}
+	var posErr *order.PositionError
+	_, err = om.GetRange(items, 4, 2)
+	require.ErrorAs(t, err, &posErr)
+	assert.Equal(t, order.PositionError{Op: "GetRange", Requested: 2, Min: 4, Max: 5}, *posErr)
+}
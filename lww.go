@@ -0,0 +1,109 @@
+package order
+
+import (
+	"cmp"
+	"slices"
+	"time"
+)
+
+// TimedMove is a move stamped with when and by whom it was made, so that
+// replicas which received concurrent moves in different orders can agree on
+// the outcome with Reconcile.
+type TimedMove[ID comparable] struct {
+	Move[ID]
+	Timestamp time.Time
+	ActorID   string
+}
+
+// SkippedMove is a move that Reconcile could not apply, for example because
+// its item or target has been removed.
+type SkippedMove[ID comparable] struct {
+	TimedMove[ID]
+	Err error
+}
+
+// ReconcileResult reports the outcome of Reconcile.
+type ReconcileResult[T any, ID comparable] struct {
+	// Applied lists the moves that were applied, in the order they were applied.
+	Applied []TimedMove[ID]
+	// Skipped lists the moves that failed against the order at their turn.
+	Skipped []SkippedMove[ID]
+	// Changed reports whether any position changed; Affected holds the items
+	// whose position differs from before Reconcile.
+	Changed  bool
+	Affected []T
+}
+
+// Reconcile merges concurrent moves with last-writer-wins semantics. The moves
+// are sorted by Timestamp, then ActorID, then their content, and applied to
+// items one after the other, so a later move has the final say over an earlier
+// one that it conflicts with. Replicas that start from the same order and
+// reconcile the same set of moves reach the same order, whatever order the
+// moves arrived in.
+//
+// Moves that fail at their turn are skipped and reported rather than stopping
+// the reconciliation. Unlike a CRDT, an earlier move can be overwritten where
+// two writers disagree; that is the price for needing only a clock.
+func (os *KeyedManager[T, ID]) Reconcile(items []T, ops []TimedMove[ID]) ReconcileResult[T, ID] {
+	sorted := slices.Clone(ops)
+	slices.SortFunc(sorted, compareTimedMoves[ID])
+
+	before := make(map[ID]int, len(items))
+	for _, item := range items {
+		before[os.getID(item)] = os.getPos(item)
+	}
+
+	var result ReconcileResult[T, ID]
+	for _, op := range sorted {
+		if _, err := os.Apply(items, op.Move); err != nil {
+			result.Skipped = append(result.Skipped, SkippedMove[ID]{TimedMove: op, Err: err})
+			continue
+		}
+		result.Applied = append(result.Applied, op)
+	}
+
+	for _, item := range items {
+		if before[os.getID(item)] != os.getPos(item) {
+			result.Affected = append(result.Affected, item)
+		}
+	}
+	result.Changed = len(result.Affected) > 0
+	return result
+}
+
+// compareTimedMoves orders moves by timestamp and actor, and falls back to the
+// content of the move so that the order is total: only identical moves
+// compare equal, and their relative order does not matter.
+func compareTimedMoves[ID comparable](a, b TimedMove[ID]) int {
+	if c := a.Timestamp.Compare(b.Timestamp); c != 0 {
+		return c
+	}
+	return cmp.Or(
+		cmp.Compare(a.ActorID, b.ActorID),
+		cmp.Compare(a.Kind, b.Kind),
+		cmp.Compare(formatID(a.ItemID), formatID(b.ItemID)),
+		cmp.Compare(a.Position, b.Position),
+		cmp.Compare(formatID(a.TargetID), formatID(b.TargetID)),
+		compareExpect(a.Expect, b.Expect),
+	)
+}
+
+// compareExpect orders preconditions by their IDs and then by Position, with
+// an unchecked Position first.
+func compareExpect[ID comparable](a, b Precondition[ID]) int {
+	if c := cmp.Or(
+		cmp.Compare(formatID(a.PrevID), formatID(b.PrevID)),
+		cmp.Compare(formatID(a.NextID), formatID(b.NextID)),
+	); c != 0 {
+		return c
+	}
+	switch {
+	case a.Position == nil && b.Position == nil:
+		return 0
+	case a.Position == nil:
+		return -1
+	case b.Position == nil:
+		return 1
+	}
+	return cmp.Compare(*a.Position, *b.Position)
+}
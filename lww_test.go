@@ -0,0 +1,76 @@
+package order_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcileLastWriterWins(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	ops := []order.TimedMove[string]{
+		{Move: order.Move[string]{Kind: order.MoveBottom, ItemID: "item-1"}, Timestamp: t0.Add(2 * time.Second), ActorID: "bob"},
+		{Move: order.Move[string]{Kind: order.MoveTop, ItemID: "item-1"}, Timestamp: t0.Add(2 * time.Second), ActorID: "alice"},
+		{Move: order.Move[string]{Kind: order.MoveTo, ItemID: "item-3", Position: 1}, Timestamp: t0, ActorID: "carol"},
+	}
+
+	items := ordertest.Items(4)
+	result := om.Reconcile(items, ops)
+	assert.Equal(t, []string{"item-3", "item-2", "item-4", "item-1"}, ordertest.IDs(items), "bob wins the tie with alice")
+	assert.Len(t, result.Applied, 3)
+	assert.Empty(t, result.Skipped)
+	assert.True(t, result.Changed)
+	assert.True(t, order.IsNormalized(items))
+
+	// Every arrival order converges on the same result.
+	for _, perm := range [][]int{{1, 0, 2}, {2, 1, 0}, {0, 2, 1}} {
+		shuffled := []order.TimedMove[string]{ops[perm[0]], ops[perm[1]], ops[perm[2]]}
+		replica := ordertest.Items(4)
+		om.Reconcile(replica, shuffled)
+		assert.Equal(t, ordertest.IDs(items), ordertest.IDs(replica))
+	}
+}
+
+func TestReconcileOrdersByPrecondition(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	first, second := 1, 2
+	ops := []order.TimedMove[string]{
+		{Move: order.Move[string]{Kind: order.MoveDown, ItemID: "item-1", Expect: order.Precondition[string]{Position: &second}}, Timestamp: t0, ActorID: "alice"},
+		{Move: order.Move[string]{Kind: order.MoveDown, ItemID: "item-1", Expect: order.Precondition[string]{Position: &first}}, Timestamp: t0, ActorID: "alice"},
+	}
+
+	for _, perm := range [][]int{{0, 1}, {1, 0}} {
+		items := ordertest.Items(3)
+		result := om.Reconcile(items, []order.TimedMove[string]{ops[perm[0]], ops[perm[1]]})
+		assert.Equal(t, []string{"item-2", "item-3", "item-1"}, ordertest.IDs(items))
+		assert.Len(t, result.Applied, 2)
+	}
+}
+
+func TestReconcileSkipsFailingMoves(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	t0 := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	items := ordertest.Items(3)
+
+	result := om.Reconcile(items, []order.TimedMove[string]{
+		{Move: order.Move[string]{Kind: order.MoveAbove, ItemID: "item-3", TargetID: "gone"}, Timestamp: t0},
+		{Move: order.Move[string]{Kind: order.MoveDown, ItemID: "item-2"}, Timestamp: t0.Add(time.Second)},
+	})
+	require.Len(t, result.Skipped, 1)
+	assert.ErrorIs(t, result.Skipped[0].Err, order.ErrItemNotFound)
+	assert.Equal(t, "item-3", result.Skipped[0].ItemID)
+	assert.Len(t, result.Applied, 1)
+	assert.Equal(t, []string{"item-1", "item-3", "item-2"}, ordertest.IDs(items))
+	assert.Len(t, result.Affected, 2)
+
+	result = om.Reconcile(items, nil)
+	assert.False(t, result.Changed)
+	assert.Empty(t, result.Affected)
+}
@@ -0,0 +1,215 @@
+// Package memstore provides an in-memory order.Store for single-process
+// applications, prototypes and tests that should not need a database.
+//
+// A Store holds any number of lists and is safe for concurrent use. The store
+// keeps its own copy of every position, so Snapshot and Restore capture and
+// bring back the complete state, including versions.
+package memstore
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/yacobolo/order"
+)
+
+// Store is an in-memory order.Store.
+//
+// By default the store keeps and hands out copies made with order.CopyItem, so
+// that callers can move loaded items while other goroutines load the same
+// list. WithClone sets a different copy, and WithSharedItems turns copying
+// off. Either way the stored positions change only through SavePositions, Put
+// and Restore.
+type Store[T order.Orderable] struct {
+	clone func(T) T
+
+	mu    sync.Mutex
+	lists map[string]*list[T]
+}
+
+// Option configures a Store.
+type Option[T order.Orderable] func(*Store[T])
+
+// WithClone makes the Store keep and hand out copies made with clone instead
+// of order.CopyItem, for items whose shallow copies share mutable state.
+func WithClone[T order.Orderable](clone func(T) T) Option[T] {
+	return func(s *Store[T]) {
+		s.clone = clone
+	}
+}
+
+// WithSharedItems makes the Store keep and hand out the items themselves.
+// Load still sets their positions to the stored ones, so items of pointer
+// type must not be moved while another goroutine loads the same list.
+func WithSharedItems[T order.Orderable]() Option[T] {
+	return func(s *Store[T]) {
+		s.clone = nil
+	}
+}
+
+var _ order.Store[order.Orderable] = (*Store[order.Orderable])(nil)
+var _ order.HealthChecker = (*Store[order.Orderable])(nil)
+
+type list[T order.Orderable] struct {
+	entries []entry[T]
+	version int64
+}
+
+type entry[T order.Orderable] struct {
+	item     T
+	position int
+}
+
+// New returns an empty Store.
+func New[T order.Orderable](opts ...Option[T]) *Store[T] {
+	s := &Store[T]{clone: order.CopyItem[T], lists: make(map[string]*list[T])}
+	for _, opt := range opts {
+		opt(s)
+	}
+	return s
+}
+
+// Put replaces the contents of a list with items at their current positions,
+// creating the list if needed, and bumps its version. It returns the new
+// version.
+func (s *Store[T]) Put(listID string, items []T) int64 {
+	entries := make([]entry[T], len(items))
+	for i, item := range items {
+		entries[i] = entry[T]{item: s.copy(item), position: item.GetPosition()}
+	}
+	sortEntries(entries)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.lists[listID]
+	if !ok {
+		l = &list[T]{}
+		s.lists[listID] = l
+	}
+	l.entries = entries
+	l.version++
+	return l.version
+}
+
+// Delete removes a list. Deleting a missing list is a no-op.
+func (s *Store[T]) Delete(listID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.lists, listID)
+}
+
+// Lists returns the IDs of all lists, sorted.
+func (s *Store[T]) Lists() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, 0, len(s.lists))
+	for id := range s.lists {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Load implements order.Store.
+func (s *Store[T]) Load(ctx context.Context, listID string) ([]T, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.lists[listID]
+	if !ok {
+		return nil, 0, fmt.Errorf("Load %s: %w", listID, order.ErrListNotFound)
+	}
+	items := make([]T, len(l.entries))
+	for i, e := range l.entries {
+		items[i] = s.copy(e.item)
+		items[i].SetPosition(e.position)
+	}
+	return items, l.version, nil
+}
+
+// SavePositions implements order.Store. It fails with a *order.NotFoundError
+// if a change names an item that is not in the list, and saves nothing then.
+func (s *Store[T]) SavePositions(ctx context.Context, listID string, expectedVersion int64, changes order.ChangeSet) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	l, ok := s.lists[listID]
+	if !ok {
+		return 0, fmt.Errorf("SavePositions %s: %w", listID, order.ErrListNotFound)
+	}
+	if l.version != expectedVersion {
+		return 0, fmt.Errorf("SavePositions %s: version %d, expected %d: %w", listID, l.version, expectedVersion, order.ErrVersionConflict)
+	}
+
+	index := make(map[string]int, len(l.entries))
+	for i, e := range l.entries {
+		index[e.item.GetID()] = i
+	}
+	for _, change := range changes {
+		if _, ok := index[change.ItemID]; !ok {
+			return 0, &order.NotFoundError{Op: "SavePositions", ItemID: change.ItemID}
+		}
+	}
+	for _, change := range changes {
+		l.entries[index[change.ItemID]].position = change.NewPosition
+	}
+	sortEntries(l.entries)
+	l.version++
+	return l.version, nil
+}
+
+// Health implements order.HealthChecker. A Store in memory is always
+// healthy, unless ctx is done.
+func (s *Store[T]) Health(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// Snapshot is the state of a Store at one point in time, see Store.Snapshot.
+type Snapshot[T order.Orderable] struct {
+	lists map[string]list[T]
+}
+
+// Lists returns the IDs of the lists in the snapshot, sorted.
+func (snap *Snapshot[T]) Lists() []string {
+	ids := make([]string, 0, len(snap.lists))
+	for id := range snap.lists {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	return ids
+}
+
+// Snapshot captures the items, positions and versions of all lists.
+func (s *Store[T]) Snapshot() *Snapshot[T] {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snap := &Snapshot[T]{lists: make(map[string]list[T], len(s.lists))}
+	for id, l := range s.lists {
+		snap.lists[id] = list[T]{entries: slices.Clone(l.entries), version: l.version}
+	}
+	return snap
+}
+
+// Restore brings the store back to the state captured in snap. Lists created
+// after the snapshot are removed, and versions go back to the captured ones.
+func (s *Store[T]) Restore(snap *Snapshot[T]) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists = make(map[string]*list[T], len(snap.lists))
+	for id, l := range snap.lists {
+		s.lists[id] = &list[T]{entries: slices.Clone(l.entries), version: l.version}
+	}
+}
+
+func (s *Store[T]) copy(item T) T {
+	if s.clone == nil {
+		return item
+	}
+	return s.clone(item)
+}
+
+func sortEntries[T order.Orderable](entries []entry[T]) {
+	slices.SortStableFunc(entries, func(a, b entry[T]) int {
+		return a.position - b.position
+	})
+}
@@ -0,0 +1,122 @@
+package memstore_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/memstore"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New[*ordertest.Item]()
+	_, _, err := store.Load(ctx, "list")
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+
+	assert.Equal(t, int64(1), store.Put("list", ordertest.Items(3)))
+	store.Put("other", ordertest.Items(1))
+	assert.Equal(t, []string{"list", "other"}, store.Lists())
+
+	version, err := store.SavePositions(ctx, "list", 1, order.ChangeSet{
+		{ItemID: "item-3", OldPosition: 3, NewPosition: 1},
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 3},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+
+	items, version, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+	ordertest.AssertOrder(t, items, "item-3", "item-2", "item-1")
+	ordertest.AssertNormalized(t, items)
+
+	_, err = store.SavePositions(ctx, "list", 1, nil)
+	assert.ErrorIs(t, err, order.ErrVersionConflict)
+	_, err = store.SavePositions(ctx, "list", 2, order.ChangeSet{{ItemID: "gone", NewPosition: 1}})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	_, version, _ = store.Load(ctx, "list")
+	assert.Equal(t, int64(2), version, "failed saves do not bump the version")
+
+	store.Delete("other")
+	assert.Equal(t, []string{"list"}, store.Lists())
+
+	assert.NoError(t, store.Health(ctx))
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	assert.ErrorIs(t, store.Health(canceled), context.Canceled)
+}
+
+func TestStoreSnapshotRestore(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	snap := store.Snapshot()
+	assert.Equal(t, []string{"list"}, snap.Lists())
+
+	_, err := store.SavePositions(ctx, "list", 1, order.ChangeSet{
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+		{ItemID: "item-2", OldPosition: 2, NewPosition: 1},
+	})
+	require.NoError(t, err)
+	store.Put("new", ordertest.Items(1))
+
+	store.Restore(snap)
+	assert.Equal(t, []string{"list"}, store.Lists())
+	items, version, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), version)
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3")
+	ordertest.AssertNormalized(t, items)
+}
+
+func TestStoreWithClone(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New(memstore.WithClone(func(item *ordertest.Item) *ordertest.Item {
+		copied := *item
+		return &copied
+	}))
+	seeded := ordertest.Items(2)
+	store.Put("list", seeded)
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	items[0].SetPosition(9)
+	assert.NotSame(t, seeded[0], items[0])
+
+	again, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, 1, again[0].GetPosition(), "moving loaded items does not touch the store")
+}
+
+func TestStoreCopiesByDefault(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New[*ordertest.Item]()
+	seeded := ordertest.Items(2)
+	store.Put("list", seeded)
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.NotSame(t, seeded[0], items[0])
+	items[0].SetPosition(9)
+
+	again, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.NotSame(t, items[0], again[0])
+	assert.Equal(t, 1, again[0].GetPosition())
+}
+
+func TestStoreWithSharedItems(t *testing.T) {
+	ctx := context.Background()
+	store := memstore.New(memstore.WithSharedItems[*ordertest.Item]())
+	seeded := ordertest.Items(2)
+	store.Put("list", seeded)
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Same(t, seeded[0], items[0])
+}
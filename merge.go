@@ -0,0 +1,136 @@
+package order
+
+// MergeStrategy decides how MergeLists combines two lists. Create one with
+// MergeAppend, MergeByPosition or MergeWeighted.
+type MergeStrategy struct {
+	kind             mergeKind
+	weightA, weightB int
+}
+
+type mergeKind int
+
+const (
+	mergeAppend mergeKind = iota
+	mergeByPosition
+	mergeWeighted
+)
+
+// MergeAppend places all items of a before the items of b.
+func MergeAppend() MergeStrategy {
+	return MergeStrategy{kind: mergeAppend}
+}
+
+// MergeByPosition interleaves the lists by their current positions, as if both
+// were one list. Items of a come first when positions are equal.
+func MergeByPosition() MergeStrategy {
+	return MergeStrategy{kind: mergeByPosition}
+}
+
+// MergeWeighted interleaves the lists in slice order so that, over any stretch
+// of the result, a contributes weightA items for every weightB items of b. The
+// k-th item of a ranks at k/weightA, the k-th item of b at k/weightB; items of a
+// win ties. Weights below 1 count as 1.
+func MergeWeighted(weightA, weightB int) MergeStrategy {
+	return MergeStrategy{kind: mergeWeighted, weightA: max(weightA, 1), weightB: max(weightB, 1)}
+}
+
+// MergeLists combines a and b into a new slice using strategy and renumbers the
+// result from 1. Items of b whose ID also occurs in a are dropped. The returned
+// ChangeSet lists every item whose position changed; positions are updated on
+// the items themselves, but neither input slice is reordered.
+func MergeLists[T Orderable](a, b []T, strategy MergeStrategy) ([]T, ChangeSet) {
+	seen := make(map[string]struct{}, len(a))
+	for _, item := range a {
+		seen[item.GetID()] = struct{}{}
+	}
+	rest := make([]T, 0, len(b))
+	for _, item := range b {
+		if _, ok := seen[item.GetID()]; !ok {
+			rest = append(rest, item)
+		}
+	}
+
+	// takeA reports whether the next item comes from a, given the indices of
+	// the next candidates of both lists.
+	var takeA func(i, j int) bool
+	switch strategy.kind {
+	case mergeByPosition:
+		takeA = func(i, j int) bool { return a[i].GetPosition() <= rest[j].GetPosition() }
+	case mergeWeighted:
+		takeA = func(i, j int) bool { return i*strategy.weightB <= j*strategy.weightA }
+	default:
+		takeA = func(int, int) bool { return true }
+	}
+
+	merged := make([]T, 0, len(a)+len(rest))
+	i, j := 0, 0
+	for i < len(a) && j < len(rest) {
+		if takeA(i, j) {
+			merged = append(merged, a[i])
+			i++
+		} else {
+			merged = append(merged, rest[j])
+			j++
+		}
+	}
+	merged = append(merged, a[i:]...)
+	merged = append(merged, rest[j:]...)
+	return merged, renumber(merged)
+}
+
+// renumber sets the positions of items to 1, 2, ..., n in slice order and
+// returns the changes.
+func renumber[T Orderable](items []T) ChangeSet {
+	return renumberWith(items, &options{})
+}
+
+// renumberWith is renumber with the index base and position step of opts.
+func renumberWith[T Orderable](items []T, opts *options) ChangeSet {
+	var changes ChangeSet
+	for i, item := range items {
+		if old, position := item.GetPosition(), opts.position(i); old != position {
+			changes = append(changes, PositionChange{ItemID: item.GetID(), OldPosition: old, NewPosition: position})
+			item.SetPosition(position)
+		}
+	}
+	return changes
+}
+
+// Interleave combines lists round-robin: the first item of every list, then the
+// second item of every list, and so on, skipping lists that have run out. The
+// result is a new slice renumbered from 1.
+func Interleave[T Orderable](lists ...[]T) []T {
+	total, longest := 0, 0
+	for _, list := range lists {
+		total += len(list)
+		longest = max(longest, len(list))
+	}
+	result := make([]T, 0, total)
+	for i := 0; i < longest; i++ {
+		for _, list := range lists {
+			if i < len(list) {
+				result = append(result, list[i])
+			}
+		}
+	}
+	renumber(result)
+	return result
+}
+
+// InterleaveEvery inserts one item of inserts after every n items of main, such
+// as one ad every n organic results. Inserts left over once main runs out are
+// dropped. n below 1 counts as 1. The result is a new slice renumbered from 1.
+func InterleaveEvery[T Orderable](n int, main, inserts []T) []T {
+	n = max(n, 1)
+	result := make([]T, 0, len(main)+min(len(inserts), len(main)/n))
+	next := 0
+	for i, item := range main {
+		result = append(result, item)
+		if (i+1)%n == 0 && next < len(inserts) {
+			result = append(result, inserts[next])
+			next++
+		}
+	}
+	renumber(result)
+	return result
+}
@@ -0,0 +1,62 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeListsAppend(t *testing.T) {
+	a, b := createTestItems(2), createTestItems(2)
+
+	merged, changes := order.MergeLists(a, b, order.MergeAppend())
+	assert.Equal(t, []*TestItem{a[0], a[1], b[0], b[1]}, merged)
+	assert.True(t, order.IsNormalized(merged))
+	assert.Equal(t, []string{b[0].GetID(), b[1].GetID()}, changes.IDs())
+	assert.Equal(t, 1, changes[0].OldPosition)
+	assert.Equal(t, 3, changes[0].NewPosition)
+}
+
+func TestMergeListsByPosition(t *testing.T) {
+	a, b := createTestItems(3), createTestItems(2)
+	a[1].SetPosition(4)
+	a[2].SetPosition(5)
+
+	merged, _ := order.MergeLists(a, b, order.MergeByPosition())
+	assert.Equal(t, []*TestItem{a[0], b[0], b[1], a[1], a[2]}, merged)
+	assert.True(t, order.IsNormalized(merged))
+}
+
+func TestMergeListsWeighted(t *testing.T) {
+	a, b := createTestItems(4), createTestItems(3)
+
+	merged, _ := order.MergeLists(a, b, order.MergeWeighted(2, 1))
+	assert.Equal(t, []*TestItem{a[0], b[0], a[1], a[2], b[1], a[3], b[2]}, merged)
+}
+
+func TestMergeListsDropsDuplicates(t *testing.T) {
+	a, b := createTestItems(2), createTestItems(1)
+	b = append(b, &TestItem{ID: a[0].ID, Position: 2})
+
+	merged, _ := order.MergeLists(a, b, order.MergeAppend())
+	assert.Equal(t, []*TestItem{a[0], a[1], b[0]}, merged)
+}
+
+func TestInterleave(t *testing.T) {
+	a, b, c := createTestItems(3), createTestItems(1), createTestItems(2)
+
+	result := order.Interleave(a, b, c)
+	assert.Equal(t, []*TestItem{a[0], b[0], c[0], a[1], c[1], a[2]}, result)
+	assert.True(t, order.IsNormalized(result))
+	assert.Empty(t, order.Interleave[*TestItem]())
+}
+
+func TestInterleaveEvery(t *testing.T) {
+	organic, ads := createTestItems(5), createTestItems(3)
+
+	result := order.InterleaveEvery(2, organic, ads)
+	assert.Equal(t, []*TestItem{organic[0], organic[1], ads[0], organic[2], organic[3], ads[1], organic[4]}, result)
+	assert.True(t, order.IsNormalized(result))
+}
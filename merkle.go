@@ -0,0 +1,154 @@
+package order
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// MerkleTree hashes a large ordering in fixed-size chunks of IDs and combines
+// the chunk hashes pairwise up to a single root. Two replicas that disagree on
+// the root can find the chunks that differ by comparing one level at a time,
+// exchanging only the hashes below mismatching nodes instead of the full ID
+// list.
+//
+// Chunks are defined by index, so moving items within the list changes only
+// the chunks they left and entered, while an insertion or removal also changes
+// every chunk after it.
+type MerkleTree struct {
+	chunkSize int
+	length    int
+	// levels[0] holds the root, the last level the chunk hashes.
+	levels [][]string
+}
+
+// MerkleSource provides the hashes of a MerkleTree, typically one held by a
+// remote replica.
+type MerkleSource interface {
+	// MerkleHashes returns the hashes of the nodes at the given indices of a
+	// level, where level 0 is the root, one hash per index. Indices past the
+	// end of the level yield an empty string.
+	MerkleHashes(ctx context.Context, level int, indices []int) ([]string, error)
+}
+
+var _ MerkleSource = (*MerkleTree)(nil)
+
+// NewMerkleTree builds the tree of the ID sequence of items. A chunkSize of 0
+// or less uses 256.
+func NewMerkleTree[T Orderable](items []T, chunkSize int) *MerkleTree {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.GetID()
+	}
+	return NewMerkleTreeIDs(ids, chunkSize)
+}
+
+// NewMerkleTreeIDs is NewMerkleTree for a list of IDs.
+func NewMerkleTreeIDs(ids []string, chunkSize int) *MerkleTree {
+	if chunkSize <= 0 {
+		chunkSize = 256
+	}
+	var leaves []string
+	for start := 0; start < len(ids); start += chunkSize {
+		leaves = append(leaves, HashIDs(ids[start:min(start+chunkSize, len(ids))]))
+	}
+	if len(leaves) == 0 {
+		leaves = []string{HashIDs(nil)}
+	}
+	levels := [][]string{leaves}
+	for level := leaves; len(level) > 1; {
+		parents := make([]string, (len(level)+1)/2)
+		for i := range parents {
+			if 2*i+1 < len(level) {
+				parents[i] = hashPair(level[2*i], level[2*i+1])
+			} else {
+				parents[i] = hashPair(level[2*i], "")
+			}
+		}
+		levels = append([][]string{parents}, levels...)
+		level = parents
+	}
+	return &MerkleTree{chunkSize: chunkSize, length: len(ids), levels: levels}
+}
+
+func hashPair(left, right string) string {
+	h := sha256.New()
+	h.Write([]byte(hashVersion + "/node"))
+	h.Write([]byte(left))
+	h.Write([]byte{0})
+	h.Write([]byte(right))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Root returns the root hash.
+func (t *MerkleTree) Root() string {
+	return t.levels[0][0]
+}
+
+// Depth returns the number of levels, including the root and the chunks.
+func (t *MerkleTree) Depth() int {
+	return len(t.levels)
+}
+
+// Chunks returns the number of chunks.
+func (t *MerkleTree) Chunks() int {
+	return len(t.levels[len(t.levels)-1])
+}
+
+// Chunk returns the range [start, end) of ID indices covered by a chunk.
+func (t *MerkleTree) Chunk(index int) (start, end int) {
+	start = min(index*t.chunkSize, t.length)
+	return start, min(start+t.chunkSize, t.length)
+}
+
+// MerkleHashes implements MerkleSource.
+func (t *MerkleTree) MerkleHashes(ctx context.Context, level int, indices []int) ([]string, error) {
+	hashes := make([]string, len(indices))
+	if level < 0 || level >= len(t.levels) {
+		return hashes, nil
+	}
+	for i, index := range indices {
+		if index >= 0 && index < len(t.levels[level]) {
+			hashes[i] = t.levels[level][index]
+		}
+	}
+	return hashes, nil
+}
+
+// Diverged returns the indices of the chunks whose hashes differ from the
+// corresponding chunks of remote, which must be a tree with the same chunk
+// size and depth. It requests hashes one level at a time, only below the
+// nodes that differ, and returns nil if the roots match. Chunks remote has
+// but t lacks are not reported; compare the list lengths for those. It fails
+// if remote does not return one hash per requested index.
+func (t *MerkleTree) Diverged(ctx context.Context, remote MerkleSource) ([]int, error) {
+	candidates := []int{0}
+	for level := range t.levels {
+		hashes, err := remote.MerkleHashes(ctx, level, candidates)
+		if err != nil {
+			return nil, err
+		}
+		if len(hashes) != len(candidates) {
+			return nil, fmt.Errorf("Diverged: remote returned %d hashes for %d nodes at level %d", len(hashes), len(candidates), level)
+		}
+		var differing []int
+		for i, index := range candidates {
+			if hashes[i] != t.levels[level][index] {
+				differing = append(differing, index)
+			}
+		}
+		if level == len(t.levels)-1 || len(differing) == 0 {
+			return differing, nil
+		}
+		candidates = candidates[:0]
+		for _, index := range differing {
+			for _, child := range []int{2 * index, 2*index + 1} {
+				if child < len(t.levels[level+1]) {
+					candidates = append(candidates, child)
+				}
+			}
+		}
+	}
+	return nil, nil
+}
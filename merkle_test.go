@@ -0,0 +1,79 @@
+package order_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingSource counts the hashes requested from a MerkleSource.
+type countingSource struct {
+	order.MerkleSource
+	requested int
+}
+
+func (s *countingSource) MerkleHashes(ctx context.Context, level int, indices []int) ([]string, error) {
+	s.requested += len(indices)
+	return s.MerkleSource.MerkleHashes(ctx, level, indices)
+}
+
+func TestMerkleTree(t *testing.T) {
+	ctx := context.Background()
+	local := ordertest.Items(1000)
+	remote := ordertest.Items(1000)
+	_, err := order.NewOrderManager[*ordertest.Item]().To(remote, "item-450", 460)
+	require.NoError(t, err)
+
+	a := order.NewMerkleTree(local, 10)
+	b := order.NewMerkleTree(remote, 10)
+	assert.Equal(t, 100, a.Chunks())
+	assert.Equal(t, 8, a.Depth())
+	assert.NotEqual(t, a.Root(), b.Root())
+
+	source := &countingSource{MerkleSource: b}
+	diverged, err := a.Diverged(ctx, source)
+	require.NoError(t, err)
+	assert.Equal(t, []int{44, 45}, diverged)
+	assert.Less(t, source.requested, 30)
+
+	start, end := a.Chunk(45)
+	assert.Equal(t, 450, start)
+	assert.Equal(t, 460, end)
+
+	same, err := a.Diverged(ctx, order.NewMerkleTree(ordertest.Items(1000), 10))
+	require.NoError(t, err)
+	assert.Empty(t, same)
+}
+
+func TestMerkleTreeSmallLists(t *testing.T) {
+	empty := order.NewMerkleTreeIDs(nil, 0)
+	assert.Equal(t, 1, empty.Chunks())
+	assert.Equal(t, 1, empty.Depth())
+
+	one := order.NewMerkleTreeIDs([]string{"a"}, 0)
+	assert.Equal(t, order.HashIDs([]string{"a"}), one.Root())
+	diverged, err := one.Diverged(context.Background(), empty)
+	require.NoError(t, err)
+	assert.Equal(t, []int{0}, diverged)
+}
+
+// shortSource returns one hash too few.
+type shortSource struct {
+	order.MerkleSource
+}
+
+func (s shortSource) MerkleHashes(ctx context.Context, level int, indices []int) ([]string, error) {
+	hashes, err := s.MerkleSource.MerkleHashes(ctx, level, indices)
+	return hashes[:len(hashes)-1], err
+}
+
+func TestMerkleTreeDivergedChecksHashCount(t *testing.T) {
+	tree := order.NewMerkleTree(ordertest.Items(100), 10)
+	_, err := tree.Diverged(context.Background(), shortSource{tree})
+	assert.Error(t, err)
+}
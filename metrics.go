@@ -0,0 +1,31 @@
+package order
+
+import "time"
+
+// OperationStats describes a single finished OrderManager operation.
+type OperationStats struct {
+	// Op is the name of the operation, e.g. "Up", "Above" or "NormalizePositions".
+	Op string
+	// ListSize is the number of items in the slice.
+	ListSize int
+	// Affected is the number of items whose position was updated.
+	Affected int
+	Duration time.Duration
+	// Err is the error returned by the operation, if any.
+	Err error
+}
+
+// Metrics receives the stats of every operation of an OrderManager configured
+// with WithMetrics. It is called synchronously, so implementations should be
+// cheap; typically they increment counters and observe histograms.
+type Metrics interface {
+	ObserveOperation(stats OperationStats)
+}
+
+// MetricsFunc adapts a function to the Metrics interface.
+type MetricsFunc func(stats OperationStats)
+
+// ObserveOperation calls f(stats).
+func (f MetricsFunc) ObserveOperation(stats OperationStats) {
+	f(stats)
+}
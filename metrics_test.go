@@ -0,0 +1,39 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	var stats []order.OperationStats
+	metrics := order.MetricsFunc(func(s order.OperationStats) {
+		stats = append(stats, s)
+	})
+	os := order.NewOrderManager[*TestItem](order.WithMetrics(metrics))
+	items := createTestItems(5)
+
+	_, err := os.Top(items, items[2].GetID())
+	require.NoError(t, err)
+	_, err = os.To(items, items[0].GetID(), 9)
+	require.Error(t, err)
+	os.NormalizePositions(items)
+
+	require.Len(t, stats, 3)
+	assert.Equal(t, "Top", stats[0].Op)
+	assert.Equal(t, 5, stats[0].ListSize)
+	assert.Equal(t, 3, stats[0].Affected)
+	assert.NoError(t, stats[0].Err)
+
+	assert.Equal(t, "To", stats[1].Op)
+	assert.True(t, errors.Is(stats[1].Err, order.ErrInvalidPosition))
+	assert.Zero(t, stats[1].Affected)
+
+	assert.Equal(t, "NormalizePositions", stats[2].Op)
+	assert.Equal(t, 5, stats[2].Affected)
+}
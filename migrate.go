@@ -0,0 +1,190 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Rank is how a position is stored by a RankScheme: as an integer, as a
+// string key that sorts lexicographically, or both.
+type Rank struct {
+	Position int
+	Key      string
+}
+
+// RankScheme decides the rank stored for the item at a dense 1-based
+// position. Use DenseRanks, GappedRanks or KeyRanks.
+type RankScheme interface {
+	Rank(position int) Rank
+}
+
+type rankFunc func(position int) Rank
+
+func (f rankFunc) Rank(position int) Rank {
+	return f(position)
+}
+
+// DenseRanks stores positions as they are, 1..n.
+func DenseRanks() RankScheme {
+	return rankFunc(func(position int) Rank {
+		return Rank{Position: position}
+	})
+}
+
+// GappedRanks stores positions as multiples of step, leaving step-1 free
+// integers between neighbors.
+func GappedRanks(step int) RankScheme {
+	return rankFunc(func(position int) Rank {
+		return Rank{Position: position * step}
+	})
+}
+
+// keyRankGap is the distance between the keys of neighbors in KeyRanks.
+const keyRankGap = 36 * 36
+
+// KeyRanks stores positions as base-36 string keys of 8 digits, for columns
+// ranked by string. Neighbors are 36² keys apart, which leaves room to insert
+// between them, and keys sort like positions for lists of up to two billion
+// items.
+func KeyRanks() RankScheme {
+	return rankFunc(func(position int) Rank {
+		key := strconv.FormatInt(int64(position)*keyRankGap, 36)
+		return Rank{Key: strings.Repeat("0", max(8-len(key), 0)) + key}
+	})
+}
+
+// RankChange is the new rank of an item written by a migration.
+type RankChange struct {
+	ItemID      string
+	OldPosition int
+	Rank        Rank
+}
+
+// RankWriter writes ranks to the target of a migration, such as a new column.
+type RankWriter interface {
+	WriteRanks(ctx context.Context, listID string, changes []RankChange) error
+}
+
+// PositionRankWriter writes the integer ranks of changes as positions with w,
+// for migrations between integer schemes in the same column.
+func PositionRankWriter(w PositionWriter) RankWriter {
+	return positionRankWriter{w}
+}
+
+type positionRankWriter struct {
+	w PositionWriter
+}
+
+func (p positionRankWriter) WriteRanks(ctx context.Context, listID string, changes []RankChange) error {
+	cs := make(ChangeSet, len(changes))
+	for i, change := range changes {
+		cs[i] = PositionChange{ItemID: change.ItemID, OldPosition: change.OldPosition, NewPosition: change.Rank.Position}
+	}
+	return p.w.WritePositions(ctx, listID, cs)
+}
+
+// MigrationState records how far MigrateRanks has come, so that an
+// interrupted migration can be resumed.
+type MigrationState struct {
+	// Cursor is the ScanPage cursor of the next page.
+	Cursor string
+	// Migrated is the number of items written so far.
+	Migrated int
+	// Done reports that the whole list was migrated.
+	Done bool
+}
+
+// MigrateOptions configures MigrateRanks.
+type MigrateOptions struct {
+	// PageSize is the number of items read and written at a time; the
+	// default is 1000.
+	PageSize int
+	// Resume continues a migration from a saved state instead of starting
+	// from the top of the list.
+	Resume MigrationState
+	// Checkpoint, if set, is called after every page with the state to
+	// resume from, typically to persist it. An error stops the migration.
+	Checkpoint func(MigrationState) error
+}
+
+// MigrateRanks converts a list to scheme: it reads the list from scanner page
+// by page in position order and writes the rank of every item to writer, so a
+// list of any size migrates without being loaded into memory. The ranks only
+// depend on the order, so the list may use any scheme before. To resume an
+// interrupted migration, pass the last checkpointed state as opts.Resume;
+// this requires cursors of scanner that stay valid across runs.
+//
+// Like NormalizeList, MigrateRanks does not check versions. Keep the target up
+// to date with moves made during the migration with a DualWriteStore.
+func MigrateRanks[T Orderable](ctx context.Context, scanner ListScanner[T], writer RankWriter, listID string, scheme RankScheme, opts MigrateOptions) (MigrationState, error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	state := opts.Resume
+	for !state.Done {
+		page, next, err := scanner.ScanPage(ctx, listID, state.Cursor, pageSize)
+		if err != nil {
+			return state, fmt.Errorf("MigrateRanks %s: %w", listID, err)
+		}
+		changes := make([]RankChange, len(page))
+		for i, item := range page {
+			changes[i] = RankChange{ItemID: item.GetID(), OldPosition: item.GetPosition(), Rank: scheme.Rank(state.Migrated + i + 1)}
+		}
+		if len(changes) > 0 {
+			if err := writer.WriteRanks(ctx, listID, changes); err != nil {
+				return state, fmt.Errorf("MigrateRanks %s: %w", listID, err)
+			}
+		}
+		state = MigrationState{Cursor: next, Migrated: state.Migrated + len(page), Done: next == ""}
+		if opts.Checkpoint != nil {
+			if err := opts.Checkpoint(state); err != nil {
+				return state, fmt.Errorf("MigrateRanks %s: %w", listID, err)
+			}
+		}
+	}
+	return state, nil
+}
+
+// DualWriteStore is a Store that, while a migration rolls out, saves every
+// change to the old Store and also writes its rank in the new scheme, so the
+// target stays in step with moves made during and after MigrateRanks. Ranks
+// are computed from the new positions of the changes, which moves keep dense.
+// Reads go to the old Store.
+type DualWriteStore[T Orderable] struct {
+	Store[T]
+	writer  RankWriter
+	scheme  RankScheme
+	onError func(listID string, err error)
+}
+
+// NewDualWriteStore wraps store to also write changed ranks to writer in
+// scheme. A failed write of the ranks does not fail the save; it is reported
+// to onError, if not nil, and the list should be migrated again.
+func NewDualWriteStore[T Orderable](store Store[T], writer RankWriter, scheme RankScheme, onError func(listID string, err error)) *DualWriteStore[T] {
+	return &DualWriteStore[T]{Store: store, writer: writer, scheme: scheme, onError: onError}
+}
+
+// Unwrap implements Wrapper.
+func (s *DualWriteStore[T]) Unwrap() Store[T] {
+	return s.Store
+}
+
+// SavePositions saves changes to the wrapped Store and, if that succeeds,
+// writes the new ranks of the changed items.
+func (s *DualWriteStore[T]) SavePositions(ctx context.Context, listID string, expectedVersion int64, changes ChangeSet) (int64, error) {
+	version, err := s.Store.SavePositions(ctx, listID, expectedVersion, changes)
+	if err != nil || len(changes) == 0 {
+		return version, err
+	}
+	ranks := make([]RankChange, len(changes))
+	for i, change := range changes {
+		ranks[i] = RankChange{ItemID: change.ItemID, OldPosition: change.OldPosition, Rank: s.scheme.Rank(change.NewPosition)}
+	}
+	if err := s.writer.WriteRanks(ctx, listID, ranks); err != nil && s.onError != nil {
+		s.onError(listID, fmt.Errorf("DualWriteStore %s: %w", listID, err))
+	}
+	return version, nil
+}
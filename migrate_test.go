@@ -0,0 +1,127 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// rankRecorder is a RankWriter that keeps the last rank written per item.
+type rankRecorder struct {
+	ranks  map[string]order.Rank
+	writes int
+	fail   error
+}
+
+func (r *rankRecorder) WriteRanks(ctx context.Context, listID string, changes []order.RankChange) error {
+	if r.fail != nil {
+		return r.fail
+	}
+	if r.ranks == nil {
+		r.ranks = make(map[string]order.Rank)
+	}
+	for _, change := range changes {
+		r.ranks[change.ItemID] = change.Rank
+	}
+	r.writes++
+	return nil
+}
+
+func TestRankSchemes(t *testing.T) {
+	assert.Equal(t, order.Rank{Position: 3}, order.DenseRanks().Rank(3))
+	assert.Equal(t, order.Rank{Position: 3000}, order.GappedRanks(1000).Rank(3))
+
+	keys := make([]string, 0, 2000)
+	for position := 1; position <= 2000; position++ {
+		keys = append(keys, order.KeyRanks().Rank(position).Key)
+	}
+	assert.Equal(t, "00000100", keys[0])
+	assert.Len(t, keys[1999], 8)
+	assert.True(t, slices.IsSorted(keys))
+}
+
+func TestMigrateRanks(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(5))
+	writer := &rankRecorder{}
+
+	var checkpoints []order.MigrationState
+	state, err := order.MigrateRanks[*ordertest.Item](ctx, store, writer, "list", order.KeyRanks(), order.MigrateOptions{
+		PageSize: 2,
+		Checkpoint: func(s order.MigrationState) error {
+			checkpoints = append(checkpoints, s)
+			return nil
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, order.MigrationState{Migrated: 5, Done: true}, state)
+	assert.Len(t, checkpoints, 3)
+	assert.Equal(t, 3, writer.writes)
+	assert.Equal(t, order.KeyRanks().Rank(5), writer.ranks["item-5"])
+}
+
+func TestMigrateRanksResume(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(5))
+
+	// The first run stops after two pages.
+	stop := errors.New("interrupted")
+	var saved order.MigrationState
+	_, err := order.MigrateRanks[*ordertest.Item](ctx, store, order.PositionRankWriter(store), "list", order.GappedRanks(10), order.MigrateOptions{
+		PageSize: 2,
+		Checkpoint: func(s order.MigrationState) error {
+			saved = s
+			if s.Migrated == 4 {
+				return stop
+			}
+			return nil
+		},
+	})
+	require.ErrorIs(t, err, stop)
+	assert.Equal(t, 4, saved.Migrated)
+
+	state, err := order.MigrateRanks[*ordertest.Item](ctx, store, order.PositionRankWriter(store), "list", order.GappedRanks(10), order.MigrateOptions{PageSize: 2, Resume: saved})
+	require.NoError(t, err)
+	assert.True(t, state.Done)
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3", "item-4", "item-5")
+	assert.Equal(t, []int{10, 20, 30, 40, 50}, []int{items[0].Position, items[1].Position, items[2].Position, items[3].Position, items[4].Position})
+}
+
+func TestDualWriteStore(t *testing.T) {
+	ctx := context.Background()
+	inner := ordertest.NewStore[*ordertest.Item]()
+	inner.Put("list", ordertest.Items(3))
+	writer := &rankRecorder{}
+	var reported []error
+	store := order.NewDualWriteStore[*ordertest.Item](inner, writer, order.KeyRanks(), func(listID string, err error) {
+		reported = append(reported, err)
+	})
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]order.Rank{
+		"item-3": order.KeyRanks().Rank(1),
+		"item-1": order.KeyRanks().Rank(2),
+		"item-2": order.KeyRanks().Rank(3),
+	}, writer.ranks)
+
+	writer.fail = errors.New("column missing")
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveBottom, ItemID: "item-3"})
+	require.NoError(t, err, "the old store stays authoritative")
+	require.Len(t, reported, 1)
+	assert.ErrorIs(t, reported[0], writer.fail)
+	assert.Equal(t, int64(3), inner.Version("list"))
+}
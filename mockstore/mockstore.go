@@ -0,0 +1,177 @@
+// Package mockstore provides an order.Store for testing code that persists
+// lists: it records every call and fails calls on demand, so tests can check
+// what was written and how errors of the store are handled.
+//
+// A Store wraps another order.Store, by default a memstore.Store, and passes
+// every call on to it unless a failure is programmed for the call:
+//
+//	store := mockstore.New[*Item](nil)
+//	store.Put("list", items)
+//	store.FailNext(mockstore.SavePositions, order.ErrVersionConflict)
+package mockstore
+
+import (
+	"context"
+	"sync"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/memstore"
+)
+
+// Method names a method of order.Store, or Health of order.HealthChecker.
+type Method string
+
+const (
+	Load          Method = "Load"
+	SavePositions Method = "SavePositions"
+	Health        Method = "Health"
+)
+
+// Call records one call to a Store.
+type Call struct {
+	Method Method
+	ListID string
+	// ExpectedVersion and Changes are the arguments of SavePositions.
+	ExpectedVersion int64
+	Changes         order.ChangeSet
+	// Err is the error the call returned.
+	Err error
+}
+
+// Store is a programmable order.Store. It is safe for concurrent use.
+type Store[T order.Orderable] struct {
+	next order.Store[T]
+	mem  *memstore.Store[T]
+
+	mu       sync.Mutex
+	calls    []Call
+	failNext map[Method][]error
+	failWhen func(Call) error
+}
+
+var _ order.Store[order.Orderable] = (*Store[order.Orderable])(nil)
+var _ order.HealthChecker = (*Store[order.Orderable])(nil)
+
+// New returns a Store that passes calls on to next. If next is nil, calls go
+// to a new memstore.Store, which Put fills.
+func New[T order.Orderable](next order.Store[T]) *Store[T] {
+	s := &Store[T]{next: next, failNext: make(map[Method][]error)}
+	if next == nil {
+		s.mem = memstore.New[T]()
+		s.next = s.mem
+	}
+	return s
+}
+
+// Put replaces the contents of a list in the default memstore.Store. It
+// panics if the Store wraps a store passed to New. Put is not recorded.
+func (s *Store[T]) Put(listID string, items []T) {
+	if s.mem == nil {
+		panic("mockstore: Put on a Store that wraps another store")
+	}
+	s.mem.Put(listID, items)
+}
+
+// FailNext makes the next call to method fail with err without reaching the
+// wrapped store. Repeated calls queue failures for the calls after that.
+func (s *Store[T]) FailNext(method Method, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failNext[method] = append(s.failNext[method], err)
+}
+
+// FailWhen consults fail before every call that has no failure queued by
+// FailNext. A non-nil error fails the call with it without reaching the
+// wrapped store. The Err field of the Call is not set yet. Pass nil to stop.
+func (s *Store[T]) FailWhen(fail func(Call) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.failWhen = fail
+}
+
+// Calls returns the calls made so far, in order.
+func (s *Store[T]) Calls() []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]Call(nil), s.calls...)
+}
+
+// CallCount returns the number of calls made to method so far.
+func (s *Store[T]) CallCount(method Method) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for _, call := range s.calls {
+		if call.Method == method {
+			n++
+		}
+	}
+	return n
+}
+
+// Reset forgets the recorded calls and all programmed failures.
+func (s *Store[T]) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = nil
+	s.failNext = make(map[Method][]error)
+	s.failWhen = nil
+}
+
+// Load implements order.Store.
+func (s *Store[T]) Load(ctx context.Context, listID string) ([]T, int64, error) {
+	call := Call{Method: Load, ListID: listID}
+	if err := s.failure(call); err != nil {
+		return nil, 0, s.record(call, err)
+	}
+	items, version, err := s.next.Load(ctx, listID)
+	return items, version, s.record(call, err)
+}
+
+// SavePositions implements order.Store.
+func (s *Store[T]) SavePositions(ctx context.Context, listID string, expectedVersion int64, changes order.ChangeSet) (int64, error) {
+	call := Call{Method: SavePositions, ListID: listID, ExpectedVersion: expectedVersion, Changes: append(order.ChangeSet(nil), changes...)}
+	if err := s.failure(call); err != nil {
+		return 0, s.record(call, err)
+	}
+	version, err := s.next.SavePositions(ctx, listID, expectedVersion, changes)
+	return version, s.record(call, err)
+}
+
+// Health implements order.HealthChecker. It passes the call on if the wrapped
+// store implements order.HealthChecker and succeeds otherwise.
+func (s *Store[T]) Health(ctx context.Context) error {
+	call := Call{Method: Health}
+	if err := s.failure(call); err != nil {
+		return s.record(call, err)
+	}
+	var err error
+	if checker, ok := s.next.(order.HealthChecker); ok {
+		err = checker.Health(ctx)
+	}
+	return s.record(call, err)
+}
+
+// failure returns the programmed error for call, if any.
+func (s *Store[T]) failure(call Call) error {
+	s.mu.Lock()
+	if queued := s.failNext[call.Method]; len(queued) > 0 {
+		s.failNext[call.Method] = queued[1:]
+		s.mu.Unlock()
+		return queued[0]
+	}
+	fail := s.failWhen
+	s.mu.Unlock()
+	if fail != nil {
+		return fail(call)
+	}
+	return nil
+}
+
+func (s *Store[T]) record(call Call, err error) error {
+	call.Err = err
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls = append(s.calls, call)
+	return err
+}
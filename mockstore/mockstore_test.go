@@ -0,0 +1,97 @@
+package mockstore_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/mockstore"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoreRecordsCalls(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"})
+	require.NoError(t, err)
+
+	calls := store.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, mockstore.Load, calls[0].Method)
+	assert.Equal(t, mockstore.Call{
+		Method:          mockstore.SavePositions,
+		ListID:          "list",
+		ExpectedVersion: 1,
+		Changes: order.ChangeSet{
+			{ItemID: "item-2", OldPosition: 2, NewPosition: 1},
+			{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+		},
+	}, calls[1])
+	assert.Equal(t, 1, store.CallCount(mockstore.SavePositions))
+
+	store.Reset()
+	assert.Empty(t, store.Calls())
+}
+
+func TestStoreFailures(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	top := order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"}
+
+	store.FailNext(mockstore.SavePositions, order.ErrVersionConflict)
+	_, err := pm.Apply(ctx, "list", top)
+	assert.ErrorIs(t, err, order.ErrVersionConflict)
+	assert.ErrorIs(t, store.Calls()[1].Err, order.ErrVersionConflict)
+
+	unavailable := errors.New("connection refused")
+	store.FailWhen(func(call mockstore.Call) error {
+		if call.ListID == "list" {
+			return unavailable
+		}
+		return nil
+	})
+	_, err = pm.Apply(ctx, "list", top)
+	assert.ErrorIs(t, err, unavailable)
+	_, _, err = store.Load(ctx, "missing")
+	assert.ErrorIs(t, err, order.ErrListNotFound, "passed on to the wrapped store")
+
+	store.FailWhen(nil)
+	_, err = pm.Apply(ctx, "list", top)
+	require.NoError(t, err)
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-3", "item-1", "item-2")
+}
+
+func TestStoreWrapsAnotherStore(t *testing.T) {
+	inner := ordertest.NewStore[*ordertest.Item]()
+	inner.Put("list", ordertest.Items(2))
+	store := mockstore.New[*ordertest.Item](inner)
+
+	items, version, err := store.Load(context.Background(), "list")
+	require.NoError(t, err)
+	assert.Len(t, items, 2)
+	assert.Equal(t, int64(1), version)
+	assert.Panics(t, func() { store.Put("list", nil) })
+}
+
+func TestStoreHealth(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	assert.NoError(t, pm.Health(ctx))
+	store.FailNext(mockstore.Health, errors.New("schema missing"))
+	assert.ErrorIs(t, pm.Health(ctx), order.ErrUnhealthy)
+	assert.NoError(t, pm.Health(ctx))
+	assert.Equal(t, 3, store.CallCount(mockstore.Health))
+}
@@ -0,0 +1,93 @@
+package order
+
+import "fmt"
+
+// MoveKind identifies one of the move verbs.
+type MoveKind int
+
+const (
+	MoveUp MoveKind = iota + 1
+	MoveDown
+	MoveTo
+	MoveTop
+	MoveBottom
+	MoveAbove
+	MoveBelow
+)
+
+var moveKindNames = map[MoveKind]string{
+	MoveUp:     "Up",
+	MoveDown:   "Down",
+	MoveTo:     "To",
+	MoveTop:    "Top",
+	MoveBottom: "Bottom",
+	MoveAbove:  "Above",
+	MoveBelow:  "Below",
+}
+
+// String returns the name of the verb, such as "Above".
+func (k MoveKind) String() string {
+	if name, ok := moveKindNames[k]; ok {
+		return name
+	}
+	return fmt.Sprintf("MoveKind(%d)", int(k))
+}
+
+// Move describes a single move as data, so it can be passed around, queued or
+// applied later with Apply. Position is used by MoveTo, TargetID by MoveAbove
+// and MoveBelow. Compare moves with Equal, since == compares the expected
+// position of Expect by pointer.
+type Move[ID comparable] struct {
+	Kind     MoveKind
+	ItemID   ID
+	Position int
+	TargetID ID
+	// Expect is checked by Apply before the move is made, see Precondition.
+	Expect Precondition[ID]
+}
+
+// Equal reports whether m and other describe the same move with the same
+// precondition.
+func (m Move[ID]) Equal(other Move[ID]) bool {
+	return m.Kind == other.Kind && m.ItemID == other.ItemID && m.Position == other.Position &&
+		m.TargetID == other.TargetID && m.Expect.Equal(other.Expect)
+}
+
+// Apply performs the move described by m. It fails with a
+// *PreconditionError if m.Expect does not hold.
+func (os *KeyedManager[T, ID]) Apply(items []T, m Move[ID]) (Result[T], error) {
+	if err := os.check(items, m); err != nil {
+		return Result[T]{}, err
+	}
+	switch m.Kind {
+	case MoveUp:
+		return os.Up(items, m.ItemID)
+	case MoveDown:
+		return os.Down(items, m.ItemID)
+	case MoveTo:
+		return os.To(items, m.ItemID, m.Position)
+	case MoveTop:
+		return os.Top(items, m.ItemID)
+	case MoveBottom:
+		return os.Bottom(items, m.ItemID)
+	case MoveAbove:
+		return os.Above(items, m.ItemID, m.TargetID)
+	case MoveBelow:
+		return os.Below(items, m.ItemID, m.TargetID)
+	}
+	return Result[T]{}, fmt.Errorf("Apply: unknown move kind %v", m.Kind)
+}
+
+// MoveWhere applies m to the first item for which match reports true, skipping
+// soft-deleted items; m.ItemID is ignored. It fails with ErrItemNotFound if no item matches:
+//
+//	_, err := om.MoveWhere(tasks, (*Task).Overdue, order.Move[string]{Kind: order.MoveTop})
+func (os *KeyedManager[T, ID]) MoveWhere(items []T, match func(T) bool, m Move[ID]) (Result[T], error) {
+	for _, item := range items {
+		if !isDeleted(item) && match(item) {
+			m.ItemID = os.getID(item)
+			return os.Apply(items, m)
+		}
+	}
+	return Result[T]{}, fmt.Errorf("MoveWhere: no item matches: %w", ErrItemNotFound)
+}
@@ -0,0 +1,54 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApply(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(4)
+	ids := []string{items[0].GetID(), items[1].GetID(), items[2].GetID(), items[3].GetID()}
+
+	for _, tc := range []struct {
+		move     order.Move[string]
+		position int
+	}{
+		{order.Move[string]{Kind: order.MoveBottom, ItemID: ids[0]}, 4},
+		{order.Move[string]{Kind: order.MoveUp, ItemID: ids[0]}, 3},
+		{order.Move[string]{Kind: order.MoveDown, ItemID: ids[0]}, 4},
+		{order.Move[string]{Kind: order.MoveTo, ItemID: ids[0], Position: 2}, 2},
+		{order.Move[string]{Kind: order.MoveTop, ItemID: ids[0]}, 1},
+		{order.Move[string]{Kind: order.MoveBelow, ItemID: ids[0], TargetID: ids[2]}, 3},
+		{order.Move[string]{Kind: order.MoveAbove, ItemID: ids[0], TargetID: ids[1]}, 1},
+	} {
+		result, err := os.Apply(items, tc.move)
+		assert.NoError(t, err, tc.move.Kind.String())
+		assert.Equal(t, tc.position, result.NewPosition, tc.move.Kind.String())
+	}
+
+	_, err := os.Apply(items, order.Move[string]{ItemID: ids[0]})
+	assert.Error(t, err)
+	assert.Equal(t, "Above", order.MoveAbove.String())
+	assert.Equal(t, "MoveKind(0)", order.MoveKind(0).String())
+}
+
+func TestMoveWhere(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(4)
+	target := items[2]
+	matchFrom := func(index int) func(*TestItem) bool {
+		return func(item *TestItem) bool { return item.GetPosition() >= index }
+	}
+
+	result, err := os.MoveWhere(items, matchFrom(3), order.Move[string]{Kind: order.MoveTop})
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.OldPosition)
+	assert.Same(t, target, items[0])
+
+	_, err = os.MoveWhere(items, matchFrom(9), order.Move[string]{Kind: order.MoveTop})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
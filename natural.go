@@ -0,0 +1,74 @@
+package order
+
+import "strings"
+
+// NaturalCompare compares strings the way people expect lists of names to be
+// sorted: runs of digits compare by their numeric value, so "Item 2" sorts
+// before "Item 10". Everything else compares byte-wise.
+func NaturalCompare(a, b string) int {
+	return naturalCompare(a, b, strings.Compare)
+}
+
+// NaturalCompareFunc returns a natural comparator that compares the text
+// between digit runs with compare. Pass a collator for locale-aware ordering:
+//
+//	c := collate.New(language.German, collate.IgnoreCase)
+//	cmp := order.NaturalCompareFunc(c.CompareString)
+func NaturalCompareFunc(compare func(a, b string) int) func(a, b string) int {
+	return func(a, b string) int {
+		return naturalCompare(a, b, compare)
+	}
+}
+
+// NaturalBy returns a comparator for SortBy ordering items naturally by the
+// string key returns, such as their title.
+func NaturalBy[T any](key func(T) string) func(a, b T) int {
+	return func(a, b T) int {
+		return NaturalCompare(key(a), key(b))
+	}
+}
+
+func naturalCompare(a, b string, compare func(a, b string) int) int {
+	for a != "" && b != "" {
+		var ca, cb string
+		ca, a = nextChunk(a)
+		cb, b = nextChunk(b)
+		var c int
+		if isDigit(ca[0]) && isDigit(cb[0]) {
+			c = compareNumbers(ca, cb)
+		} else {
+			c = compare(ca, cb)
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return len(a) - len(b)
+}
+
+// nextChunk splits s after its leading run of digits or non-digits.
+func nextChunk(s string) (chunk, rest string) {
+	digits := isDigit(s[0])
+	i := 1
+	for i < len(s) && isDigit(s[i]) == digits {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+// compareNumbers compares two runs of digits by value without parsing them, so
+// arbitrarily long numbers work. Equal values with more leading zeros sort last.
+func compareNumbers(a, b string) int {
+	ta, tb := strings.TrimLeft(a, "0"), strings.TrimLeft(b, "0")
+	if len(ta) != len(tb) {
+		return len(ta) - len(tb)
+	}
+	if c := strings.Compare(ta, tb); c != 0 {
+		return c
+	}
+	return len(a) - len(b)
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
@@ -0,0 +1,56 @@
+package order_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNaturalCompare(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"Item 2", "Item 10", -1},
+		{"Item 10", "Item 2", 1},
+		{"Item 2", "Item 2", 0},
+		{"Item 02", "Item 2", 1},
+		{"Item", "Item 1", -1},
+		{"a1b2", "a1b10", -1},
+		{"x99999999999999999999", "x100000000000000000000", -1},
+		{"B", "a", -1},
+	} {
+		got := order.NaturalCompare(tc.a, tc.b)
+		switch {
+		case tc.want < 0:
+			assert.Negative(t, got, "%q vs %q", tc.a, tc.b)
+		case tc.want > 0:
+			assert.Positive(t, got, "%q vs %q", tc.a, tc.b)
+		default:
+			assert.Zero(t, got, "%q vs %q", tc.a, tc.b)
+		}
+	}
+
+	fold := order.NaturalCompareFunc(func(a, b string) int {
+		return strings.Compare(strings.ToLower(a), strings.ToLower(b))
+	})
+	assert.Positive(t, fold("B", "a"))
+	assert.Negative(t, fold("a 9", "A 10"))
+}
+
+func TestSortByNatural(t *testing.T) {
+	items := make([]*LabeledItem, 4)
+	for i, item := range createTestItems(4) {
+		items[i] = &LabeledItem{TestItem: *item}
+	}
+	items[0].Name, items[1].Name, items[2].Name, items[3].Name = "Item 10", "Item 2", "Item 1", "Item 2"
+	want := []*LabeledItem{items[2], items[1], items[3], items[0]}
+
+	changes := order.SortBy(items, order.NaturalBy((*LabeledItem).Label))
+	assert.Equal(t, want, items)
+	assert.True(t, order.IsNormalized(items))
+	assert.Len(t, changes, 3)
+}
@@ -0,0 +1,86 @@
+package order
+
+import (
+	"context"
+	"fmt"
+)
+
+// ListScanner gives access to lists too large to load into memory at once.
+// Stores that can page through a list implement it next to Store.
+type ListScanner[T Orderable] interface {
+	// ScanPage returns up to limit items of a list in position order, starting
+	// after cursor, or at the beginning for an empty cursor, together with the
+	// cursor of the next page, which is empty after the last page. A scan must
+	// return every item exactly once even while WritePositions changes
+	// positions, for example by paging over a snapshot or a server-side cursor.
+	// limit is positive. It fails with ErrListNotFound if the list does not
+	// exist.
+	ScanPage(ctx context.Context, listID, cursor string, limit int) ([]T, string, error)
+
+	// WritePositions applies changes without a version check and bumps the
+	// version of the list.
+	PositionWriter
+}
+
+// NormalizeListOptions configures NormalizeList.
+type NormalizeListOptions struct {
+	// PageSize is the number of items read per page; the default is 1000.
+	PageSize int
+	// Batch controls how the changed positions are written. Progress counts
+	// writes and changes over the whole list; its Total is 0.
+	Batch BatchOptions
+}
+
+// NormalizeListStats reports what NormalizeList did.
+type NormalizeListStats struct {
+	// Scanned is the number of items read.
+	Scanned int
+	// Changed is the number of positions written.
+	Changed int
+	// Pages is the number of pages read.
+	Pages int
+}
+
+// NormalizeList renumbers a list in a ListScanner to positions 1..n without
+// loading it into memory: it reads the list page by page and writes the changed
+// positions of each page, in batches as set by opts.Batch, before reading the
+// next one. As with NormalizePositions, the current order is kept and only
+// positions change.
+//
+// NormalizeList bypasses version checks, so concurrent moves on the list can
+// be lost; run it while the list is not being edited. If it fails part way, the
+// pages written so far stay normalized and running it again completes the job.
+func NormalizeList[T Orderable](ctx context.Context, scanner ListScanner[T], listID string, opts NormalizeListOptions) (NormalizeListStats, error) {
+	pageSize := opts.PageSize
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+	var stats NormalizeListStats
+	writer := newBatchWriter(scanner, listID, opts.Batch)
+	cursor := ""
+	for {
+		page, next, err := scanner.ScanPage(ctx, listID, cursor, pageSize)
+		if err != nil {
+			return stats, fmt.Errorf("NormalizeList %s: %w", listID, err)
+		}
+		stats.Pages++
+
+		var changes ChangeSet
+		for _, item := range page {
+			stats.Scanned++
+			if item.GetPosition() != stats.Scanned {
+				changes = append(changes, PositionChange{ItemID: item.GetID(), OldPosition: item.GetPosition(), NewPosition: stats.Scanned})
+			}
+		}
+		err = writer.write(ctx, changes)
+		stats.Changed = writer.progress.Written
+		if err != nil {
+			return stats, fmt.Errorf("NormalizeList %s: %w", listID, err)
+		}
+
+		if next == "" {
+			return stats, nil
+		}
+		cursor = next
+	}
+}
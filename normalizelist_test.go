@@ -0,0 +1,44 @@
+package order_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeList(t *testing.T) {
+	ctx := context.Background()
+	items := ordertest.Items(10)
+	// Duplicate positions and gaps, kept in slice order.
+	for i, position := range []int{1, 1, 2, 2, 7, 8, 8, 20, 21, 40} {
+		items[i].Position = position
+	}
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", items)
+
+	var last order.BatchProgress
+	stats, err := order.NormalizeList[*ordertest.Item](ctx, store, "list", order.NormalizeListOptions{
+		PageSize: 3,
+		Batch:    order.BatchOptions{Size: 2, Progress: func(p order.BatchProgress) { last = p }},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, order.NormalizeListStats{Scanned: 10, Changed: 9, Pages: 4}, stats)
+	assert.Equal(t, order.BatchProgress{Batches: 6, Written: 9}, last)
+
+	loaded, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertNormalized(t, loaded)
+	ordertest.AssertOrder(t, loaded, ordertest.IDs(ordertest.Items(10))...)
+
+	stats, err = order.NormalizeList[*ordertest.Item](ctx, store, "list", order.NormalizeListOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, order.NormalizeListStats{Scanned: 10, Pages: 1}, stats)
+
+	_, err = order.NormalizeList[*ordertest.Item](ctx, store, "missing", order.NormalizeListOptions{})
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+}
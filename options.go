@@ -0,0 +1,119 @@
+package order
+
+import "log/slog"
+
+// Option configures an OrderManager.
+type Option func(*options)
+
+type options struct {
+	strict           bool
+	detectDuplicates bool
+	boundaryErrors   bool
+	sameItemErrors   bool
+	metrics          Metrics
+	logger           *slog.Logger
+	logLevels        *LogLevels
+	deletedPolicy    DeletedPolicy
+	// baseOffset is the index base minus 1, so that the zero value numbers from 1.
+	baseOffset int
+	// step is the distance between adjacent positions; 0 means 1.
+	step int
+}
+
+// base returns the position of the first item.
+func (o *options) base() int {
+	return o.baseOffset + 1
+}
+
+// position returns the position of the item at index i.
+func (o *options) position(i int) int {
+	return o.base() + i*max(o.step, 1)
+}
+
+// WithStrictValidation makes every move validate the slice before touching it.
+// If Validate reports duplicate IDs, duplicate or non-positive positions, or a
+// slice that is not sorted by position, the move fails with a *ValidationError
+// (matching ErrInvalidOrder) and the items are left unchanged. Gaps are still
+// accepted because every move renumbers the slice.
+func WithStrictValidation() Option {
+	return func(o *options) {
+		o.strict = true
+	}
+}
+
+// WithDuplicateDetection makes every ID lookup scan the whole slice and fail with
+// a *DuplicateIDError (matching ErrDuplicateID) when the ID occurs more than once,
+// instead of silently using the first match. Lookups become O(n) in all cases.
+func WithDuplicateDetection() Option {
+	return func(o *options) {
+		o.detectDuplicates = true
+	}
+}
+
+// WithBoundaryErrors makes Up on the first item fail with ErrAlreadyAtTop and
+// Down on the last item fail with ErrAlreadyAtBottom. By default both are no-ops
+// that return nil.
+func WithBoundaryErrors() Option {
+	return func(o *options) {
+		o.boundaryErrors = true
+	}
+}
+
+// WithSameItemErrors makes Above and Below fail with ErrSameItem when the item
+// and the target are the same. By default such moves are no-ops.
+func WithSameItemErrors() Option {
+	return func(o *options) {
+		o.sameItemErrors = true
+	}
+}
+
+// WithMetrics reports every operation to m.
+func WithMetrics(m Metrics) Option {
+	return func(o *options) {
+		o.metrics = m
+	}
+}
+
+// WithLogger logs operations, validation failures and renormalizations to logger
+// at the levels set with WithLogLevels, or DefaultLogLevels.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+// WithLogLevels sets the levels used by WithLogger.
+func WithLogLevels(levels LogLevels) Option {
+	return func(o *options) {
+		o.logLevels = &levels
+	}
+}
+
+// WithDeletedPolicy sets how moves treat soft-deleted items (see Deletable).
+// The default is KeepDeletedPositions.
+func WithDeletedPolicy(policy DeletedPolicy) Option {
+	return func(o *options) {
+		o.deletedPolicy = policy
+	}
+}
+
+// WithIndexBase numbers the first item base instead of 1, for example 0 for
+// lists stored with zero-based positions. To, ToFraction and MoveMany then take
+// positions counted from base, and strict validation accepts non-positive
+// positions when base is below 1.
+func WithIndexBase(base int) Option {
+	return func(o *options) {
+		o.baseOffset = base - 1
+	}
+}
+
+// WithPositionStep numbers adjacent items step apart (base, base+step, ...)
+// instead of densely, matching lists stored with GappedRanks so that other
+// writers can insert between neighbors without renumbering. Every move still
+// renumbers the whole slice, and To and MoveMany still count slots, not
+// positions. A step below 1 is treated as 1.
+func WithPositionStep(step int) Option {
+	return func(o *options) {
+		o.step = step
+	}
+}
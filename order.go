@@ -1,132 +1,642 @@
 package order
 
 import (
-	"errors"
 	"fmt"
+	"math"
+	"runtime"
+	"sync"
+	"time"
 )
 
 // Orderable is an interface that items must implement to be orderable.
 type Orderable interface {
-	GetID() string
+	OrderableBy[string]
+}
+
+// OrderableBy is Orderable with an ID of any comparable type, so items keyed by
+// an int64 or uuid.UUID can be looked up without formatting every ID as a string.
+type OrderableBy[ID comparable] interface {
+	GetID() ID
 	GetPosition() int
 	SetPosition(position int)
 }
 
-var (
-	ErrItemNotFound    = errors.New("item not found")
-	ErrInvalidPosition = errors.New("invalid position")
-)
+// Lockable can be implemented by items that must not be moved. Moving an item
+// whose IsLocked reports true fails with a *LockedError. Locked items can still
+// be displaced by moves of other items.
+type Lockable interface {
+	IsLocked() bool
+}
 
 // OrderManager provides methods to manage the order of items.
-type OrderManager[T Orderable] struct{}
+type OrderManager[T Orderable] struct {
+	*FuncManager[T]
+}
 
 // NewOrderManager creates a new instance of OrderManager.
-func NewOrderManager[T Orderable]() *OrderManager[T] {
-	return &OrderManager[T]{}
+func NewOrderManager[T Orderable](opts ...Option) *OrderManager[T] {
+	return &OrderManager[T]{FuncManager: NewFuncManager(
+		T.GetID,
+		T.GetPosition,
+		func(item *T, position int) { (*item).SetPosition(position) },
+		opts...,
+	)}
 }
 
-// NormalizePositions ensures that the positions of items are sequential starting from 1.
-func (os *OrderManager[T]) NormalizePositions(items []T) {
-	for i, item := range items {
-		item.SetPosition(i + 1)
+// NewValueManager creates a manager for slices of values ([]Item) whose
+// Orderable methods have pointer receivers. Positions are set through a pointer
+// into the slice, so the items are updated in place:
+//
+//	vm := order.NewValueManager[Item]()
+//	_, err := vm.Top(items, "b") // items is []Item
+func NewValueManager[T any, PT interface {
+	*T
+	Orderable
+}](opts ...Option) *FuncManager[T] {
+	return NewFuncManager(
+		func(item T) string { return PT(&item).GetID() },
+		func(item T) int { return PT(&item).GetPosition() },
+		func(item *T, position int) { PT(item).SetPosition(position) },
+		opts...,
+	)
+}
+
+// FuncManager provides the methods of OrderManager for any item type, reading
+// and writing IDs and positions through accessor functions instead of the
+// Orderable interface. OrderManager is a FuncManager using the interface methods.
+type FuncManager[T any] struct {
+	*KeyedManager[T, string]
+}
+
+// NewFuncManager creates a FuncManager using the given accessors. setPos
+// receives a pointer into the slice, so plain structs can be ordered in a
+// []T as well as a []*T.
+func NewFuncManager[T any](getID func(T) string, getPos func(T) int, setPos func(*T, int), opts ...Option) *FuncManager[T] {
+	return &FuncManager[T]{KeyedManager: NewKeyedManager(getID, getPos, setPos, opts...)}
+}
+
+// NewOrderManagerBy creates a manager for items implementing OrderableBy, whose
+// methods take IDs of type ID:
+//
+//	om := order.NewOrderManagerBy[*Task, int64]()
+//	_, err := om.Top(tasks, 42)
+func NewOrderManagerBy[T OrderableBy[ID], ID comparable](opts ...Option) *KeyedManager[T, ID] {
+	return NewKeyedManager(
+		T.GetID,
+		T.GetPosition,
+		func(item *T, position int) { (*item).SetPosition(position) },
+		opts...,
+	)
+}
+
+// KeyedManager is the manager behind OrderManager and FuncManager, with IDs of
+// any comparable type. IDs are only formatted as strings for errors, validation
+// issues and logs.
+type KeyedManager[T any, ID comparable] struct {
+	getID     func(T) ID
+	getPos    func(T) int
+	setPos    func(*T, int)
+	deletable bool
+	opts      options
+}
+
+// NewKeyedManager creates a KeyedManager using the given accessors, like
+// NewFuncManager.
+func NewKeyedManager[T any, ID comparable](getID func(T) ID, getPos func(T) int, setPos func(*T, int), opts ...Option) *KeyedManager[T, ID] {
+	km := &KeyedManager[T, ID]{getID: getID, getPos: getPos, setPos: setPos, deletable: mayBeDeletable[T]()}
+	for _, opt := range opts {
+		opt(&km.opts)
+	}
+	return km
+}
+
+// NormalizePositions ensures that the positions of items are sequential starting from 1,
+// or from the base and step set with WithIndexBase and WithPositionStep.
+func (os *KeyedManager[T, ID]) NormalizePositions(items []T) {
+	var err error
+	result := Result[T]{Changed: len(items) > 0, Affected: items}
+	var none ID
+	defer os.instrument("NormalizePositions", items, none)(&result, &err)
+	for i := range items {
+		os.setPos(&items[i], os.opts.position(i))
 	}
 }
 
+// minParallelChunk is the smallest number of items worth handing to a worker
+// of NormalizePositionsParallel; below it the goroutines cost more than they save.
+const minParallelChunk = 16 << 10
+
+// NormalizePositionsParallel is NormalizePositions for very large slices,
+// renumbering contiguous chunks of items on up to workers goroutines. A workers
+// of 0 or less uses GOMAXPROCS. Slices too small to benefit are renumbered on
+// the calling goroutine. setPos must be safe to call concurrently for
+// different items.
+func (os *KeyedManager[T, ID]) NormalizePositionsParallel(items []T, workers int) {
+	var err error
+	result := Result[T]{Changed: len(items) > 0, Affected: items}
+	var none ID
+	defer os.instrument("NormalizePositionsParallel", items, none)(&result, &err)
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	workers = min(workers, len(items)/minParallelChunk)
+	if workers <= 1 {
+		for i := range items {
+			os.setPos(&items[i], os.opts.position(i))
+		}
+		return
+	}
+
+	var wg sync.WaitGroup
+	chunk := (len(items) + workers - 1) / workers
+	for start := 0; start < len(items); start += chunk {
+		end := min(start+chunk, len(items))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := start; i < end; i++ {
+				os.setPos(&items[i], os.opts.position(i))
+			}
+		}()
+	}
+	wg.Wait()
+}
+
 // GetItemIndexByID returns the index of an item by its ID.
-func (os *OrderManager[T]) GetItemIndexByID(items []T, itemID string) (int, error) {
+// With WithDuplicateDetection it scans the whole slice and fails with a
+// *DuplicateIDError if the ID occurs more than once.
+func (os *KeyedManager[T, ID]) GetItemIndexByID(items []T, itemID ID) (int, error) {
+	found := -1
 	for index, item := range items {
-		if item.GetID() == itemID {
+		if os.getID(item) != itemID {
+			continue
+		}
+		if !os.opts.detectDuplicates {
 			return index, nil
 		}
+		if found >= 0 {
+			return -1, &DuplicateIDError{Op: "GetItemIndexByID", ItemID: formatID(itemID), FirstIndex: found, SecondIndex: index}
+		}
+		found = index
+	}
+	if found >= 0 {
+		return found, nil
 	}
-	return -1, fmt.Errorf("GetItemIndexByID: %w", ErrItemNotFound)
+	return -1, &NotFoundError{Op: "GetItemIndexByID", ItemID: formatID(itemID)}
 }
 
-// Up moves an item up by one position.
-func (os *OrderManager[T]) Up(items []T, itemID string) error {
+// Up moves an item up by one position. If the item and the one above it
+// already hold the positions of their slots, only those two positions are
+// updated; otherwise the whole slice is normalized.
+func (os *KeyedManager[T, ID]) Up(items []T, itemID ID) (result Result[T], err error) {
+	defer os.instrument("Up", items, itemID)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("Up: %w", err)
+	}
+	items, done := os.withoutDeleted(items, itemID)
+	defer done(&result, &err)
 	index, err := os.GetItemIndexByID(items, itemID)
 	if err != nil {
-		return err
+		return Result[T]{}, err
+	}
+	if isLocked(items[index]) {
+		return Result[T]{}, &LockedError{Op: "Up", ItemID: formatID(itemID)}
 	}
+	oldPosition := os.getPos(items[index])
 	if index == 0 {
 		// Item is already at the top
-		return nil
+		if os.opts.boundaryErrors {
+			return Result[T]{}, fmt.Errorf("Up: %w", ErrAlreadyAtTop)
+		}
+		return Result[T]{OldPosition: oldPosition, NewPosition: oldPosition}, nil
 	}
 	// Swap with the item above
-	items[index], items[index-1] = items[index-1], items[index]
-	// Normalize positions
-	os.NormalizePositions(items)
-	return nil
+	return os.swap(items, index-1, index, index-1, oldPosition), nil
 }
 
-// Down moves an item down by one position.
-func (os *OrderManager[T]) Down(items []T, itemID string) error {
+// Down moves an item down by one position, updating positions like Up.
+func (os *KeyedManager[T, ID]) Down(items []T, itemID ID) (result Result[T], err error) {
+	defer os.instrument("Down", items, itemID)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("Down: %w", err)
+	}
+	items, done := os.withoutDeleted(items, itemID)
+	defer done(&result, &err)
 	index, err := os.GetItemIndexByID(items, itemID)
 	if err != nil {
-		return err
+		return Result[T]{}, err
+	}
+	if isLocked(items[index]) {
+		return Result[T]{}, &LockedError{Op: "Down", ItemID: formatID(itemID)}
 	}
+	oldPosition := os.getPos(items[index])
 	if index == len(items)-1 {
 		// Item is already at the bottom
-		return nil
+		if os.opts.boundaryErrors {
+			return Result[T]{}, fmt.Errorf("Down: %w", ErrAlreadyAtBottom)
+		}
+		return Result[T]{OldPosition: oldPosition, NewPosition: oldPosition}, nil
 	}
 	// Swap with the item below
-	items[index], items[index+1] = items[index+1], items[index]
-	// Normalize positions
-	os.NormalizePositions(items)
-	return nil
+	return os.swap(items, index, index+1, index+1, oldPosition), nil
+}
+
+// To moves an item to a specific position. Without metrics, logging or
+// soft-deleted items, the only allocation is the Affected slice of the Result.
+func (os *KeyedManager[T, ID]) To(items []T, itemID ID, newPosition int) (Result[T], error) {
+	if os.bare() {
+		if err := os.validate(items); err != nil {
+			return Result[T]{}, fmt.Errorf("To: %w", err)
+		}
+		return os.to(items, itemID, newPosition)
+	}
+	return os.observedTo(items, itemID, newPosition)
 }
 
-// To moves an item to a specific position.
-func (os *OrderManager[T]) To(items []T, itemID string, newPosition int) error {
-	if newPosition < 1 || newPosition > len(items) {
-		return fmt.Errorf("To: %w", ErrInvalidPosition)
+// observedTo is To with instrumentation and soft-delete handling. It is kept
+// apart because the deferred calls make its results escape to the heap.
+func (os *KeyedManager[T, ID]) observedTo(items []T, itemID ID, newPosition int) (result Result[T], err error) {
+	defer os.instrument("To", items, itemID)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("To: %w", err)
+	}
+	items, done := os.withoutDeleted(items, itemID)
+	defer done(&result, &err)
+	return os.to(items, itemID, newPosition)
+}
+
+func (os *KeyedManager[T, ID]) to(items []T, itemID ID, newPosition int) (Result[T], error) {
+	base := os.opts.base()
+	if newPosition < base || newPosition > base+len(items)-1 {
+		return Result[T]{}, &PositionError{Op: "To", Requested: newPosition, Min: base, Max: base + len(items) - 1}
 	}
 
 	currentIndex, err := os.GetItemIndexByID(items, itemID)
 	if err != nil {
-		return err
+		return Result[T]{}, err
 	}
 
-	// Remove the item from its current position
-	itemToMove := items[currentIndex]
-	items = append(items[:currentIndex], items[currentIndex+1:]...)
-
 	// Adjust for zero-based index
-	insertIndex := newPosition - 1
+	return os.move("To", items, currentIndex, newPosition-base)
+}
+
+// ToFraction moves an item to the given fraction of the list: 0 is the first
+// position, 1 the last and 0.5 the middle, rounding to the nearest position.
+// A fraction outside [0, 1] fails with ErrInvalidPosition.
+func (os *KeyedManager[T, ID]) ToFraction(items []T, itemID ID, fraction float64) (result Result[T], err error) {
+	defer os.instrument("ToFraction", items, itemID)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("ToFraction: %w", err)
+	}
+	items, done := os.withoutDeleted(items, itemID)
+	defer done(&result, &err)
+	if !(fraction >= 0 && fraction <= 1) {
+		return Result[T]{}, fmt.Errorf("ToFraction: fraction %v: %w", fraction, ErrInvalidPosition)
+	}
+	if len(items) == 0 {
+		return Result[T]{}, &NotFoundError{Op: "ToFraction", ItemID: formatID(itemID)}
+	}
+	return os.to(items, itemID, os.opts.base()+int(math.Round(fraction*float64(len(items)-1))))
+}
 
-	// Insert the item at the new position
-	items = append(items[:insertIndex], append([]T{itemToMove}, items[insertIndex:]...)...)
+// swap exchanges the adjacent items at i and i+1 for Up and Down and returns
+// the Result for the moved item, which ends up at index. When both items are
+// numbered like their slots, their positions are exchanged without scanning the
+// rest of the slice and Affected holds just the two of them.
+func (os *KeyedManager[T, ID]) swap(items []T, i, j, index, oldPosition int) Result[T] {
+	items[i], items[j] = items[j], items[i]
+	if os.getPos(items[i]) != os.opts.position(j) || os.getPos(items[j]) != os.opts.position(i) {
+		return os.result(items, index, oldPosition, os.normalize(items))
+	}
+	os.setPos(&items[i], os.opts.position(i))
+	os.setPos(&items[j], os.opts.position(j))
+	return os.result(items, index, oldPosition, []T{items[i], items[j]})
+}
 
-	// Normalize positions
-	os.NormalizePositions(items)
+// move moves the item at currentIndex so that it ends up at insertIndex and
+// normalizes positions.
+func (os *KeyedManager[T, ID]) move(op string, items []T, currentIndex, insertIndex int) (Result[T], error) {
+	itemToMove := items[currentIndex]
+	if isLocked(itemToMove) {
+		return Result[T]{}, &LockedError{Op: op, ItemID: formatID(os.getID(itemToMove))}
+	}
+	oldPosition := os.getPos(itemToMove)
 
-	return nil
+	// Rotate the items between the two indices by one slot, in place
+	if currentIndex < insertIndex {
+		copy(items[currentIndex:insertIndex], items[currentIndex+1:insertIndex+1])
+	} else {
+		copy(items[insertIndex+1:currentIndex+1], items[insertIndex:currentIndex])
+	}
+	items[insertIndex] = itemToMove
+
+	// Normalize positions. On a normalized slice only the rotated items change.
+	span := currentIndex - insertIndex
+	if span < 0 {
+		span = -span
+	}
+	return os.result(items, insertIndex, oldPosition, os.renumber(items, span+1)), nil
 }
 
 // Top moves an item to the first position.
-func (os *OrderManager[T]) Top(items []T, itemID string) error {
-	return os.To(items, itemID, 1)
+func (os *KeyedManager[T, ID]) Top(items []T, itemID ID) (result Result[T], err error) {
+	defer os.instrument("Top", items, itemID)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("Top: %w", err)
+	}
+	items, done := os.withoutDeleted(items, itemID)
+	defer done(&result, &err)
+	return os.to(items, itemID, os.opts.base())
 }
 
 // Bottom moves an item to the last position.
-func (os *OrderManager[T]) Bottom(items []T, itemID string) error {
-	return os.To(items, itemID, len(items))
+func (os *KeyedManager[T, ID]) Bottom(items []T, itemID ID) (result Result[T], err error) {
+	defer os.instrument("Bottom", items, itemID)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("Bottom: %w", err)
+	}
+	items, done := os.withoutDeleted(items, itemID)
+	defer done(&result, &err)
+	return os.to(items, itemID, os.opts.base()+len(items)-1)
 }
 
-// Above moves an item to be directly above the target item.
-func (os *OrderManager[T]) Above(items []T, itemID string, targetID string) error {
-	targetIndex, err := os.GetItemIndexByID(items, targetID)
+// Above moves an item to be directly above the target item. If the item already
+// is directly above the target, or is the target itself, nothing changes; with
+// WithSameItemErrors the latter fails with ErrSameItem instead.
+func (os *KeyedManager[T, ID]) Above(items []T, itemID ID, targetID ID) (result Result[T], err error) {
+	defer os.instrument("Above", items, itemID)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("Above: %w", err)
+	}
+	items, done := os.withoutDeleted(items, itemID)
+	defer done(&result, &err)
+	itemIndex, targetIndex, err := os.relativeIndices("Above", items, itemID, targetID)
+	if err != nil || itemIndex == targetIndex || itemIndex == targetIndex-1 {
+		return os.unmoved(items, itemIndex), err
+	}
+	if itemIndex < targetIndex {
+		// The target shifts up once the item is removed
+		targetIndex--
+	}
+	return os.move("Above", items, itemIndex, targetIndex)
+}
+
+// Below moves an item to be directly below the target item. If the item already
+// is directly below the target, or is the target itself, nothing changes; with
+// WithSameItemErrors the latter fails with ErrSameItem instead.
+func (os *KeyedManager[T, ID]) Below(items []T, itemID ID, targetID ID) (result Result[T], err error) {
+	defer os.instrument("Below", items, itemID)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("Below: %w", err)
+	}
+	items, done := os.withoutDeleted(items, itemID)
+	defer done(&result, &err)
+	itemIndex, targetIndex, err := os.relativeIndices("Below", items, itemID, targetID)
+	if err != nil || itemIndex == targetIndex || itemIndex == targetIndex+1 {
+		return os.unmoved(items, itemIndex), err
+	}
+	if itemIndex > targetIndex {
+		targetIndex++
+	}
+	return os.move("Below", items, itemIndex, targetIndex)
+}
+
+// MoveMany moves the items with the given IDs as one block, keeping their
+// relative order, so that the first of them ends up at position. Positions
+// count in the slice after the move, so position ranges from 1 to the number
+// of items not being moved plus one. The move is a single partition-and-rebuild
+// pass followed by one renumbering, which keeps multi-select drags linear in
+// the length of the slice. The Result describes the item itemIDs[0].
+func (os *KeyedManager[T, ID]) MoveMany(items []T, itemIDs []ID, position int) (result Result[T], err error) {
+	var first ID
+	if len(itemIDs) > 0 {
+		first = itemIDs[0]
+	}
+	defer os.instrument("MoveMany", items, first)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("MoveMany: %w", err)
+	}
+	items, done := os.withoutDeleted(items, first)
+	defer done(&result, &err)
+
+	wanted := make(map[ID]bool, len(itemIDs))
+	for _, id := range itemIDs {
+		wanted[id] = false
+	}
+	moved := make([]T, 0, len(wanted))
+	oldPosition := 0
+	for _, item := range items {
+		id := os.getID(item)
+		if _, ok := wanted[id]; !ok {
+			continue
+		}
+		if isLocked(item) {
+			return Result[T]{}, &LockedError{Op: "MoveMany", ItemID: formatID(id)}
+		}
+		if id == first {
+			oldPosition = os.getPos(item)
+		}
+		wanted[id] = true
+		moved = append(moved, item)
+	}
+	for _, id := range itemIDs {
+		if !wanted[id] {
+			return Result[T]{}, &NotFoundError{Op: "MoveMany", ItemID: formatID(id)}
+		}
+	}
+	rest := len(items) - len(moved)
+	base := os.opts.base()
+	if position < base || position > base+rest {
+		return Result[T]{}, &PositionError{Op: "MoveMany", Requested: position, Min: base, Max: base + rest}
+	}
+	if len(moved) == 0 {
+		return Result[T]{}, nil
+	}
+
+	// Compact the remaining items to the front, then shift the tail to open a
+	// gap for the block
+	kept := 0
+	for _, item := range items {
+		if _, ok := wanted[os.getID(item)]; !ok {
+			items[kept] = item
+			kept++
+		}
+	}
+	insertIndex := position - base
+	copy(items[insertIndex+len(moved):], items[insertIndex:kept])
+	copy(items[insertIndex:], moved)
+
+	affected := os.normalize(items)
+	for i := insertIndex; i < insertIndex+len(moved); i++ {
+		if os.getID(items[i]) == first {
+			return os.result(items, i, oldPosition, affected), nil
+		}
+	}
+	return os.result(items, insertIndex, oldPosition, affected), nil
+}
+
+// MoveInView moves an item to viewIndex (0-based) of a filtered view of items,
+// the subset for which visible reports true, leaving hidden items where they
+// are. The item lands directly below the visible item before viewIndex, or
+// directly above the first visible item for viewIndex 0. The item itself does
+// not need to be visible. A viewIndex outside the view fails with a
+// *PositionError; if nothing else is visible, the item stays in place.
+func (os *KeyedManager[T, ID]) MoveInView(items []T, visible func(T) bool, itemID ID, viewIndex int) (result Result[T], err error) {
+	defer os.instrument("MoveInView", items, itemID)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("MoveInView: %w", err)
+	}
+	items, done := os.withoutDeleted(items, itemID)
+	defer done(&result, &err)
+	itemIndex, err := os.GetItemIndexByID(items, itemID)
 	if err != nil {
-		return err
+		return Result[T]{}, err
 	}
-	return os.To(items, itemID, targetIndex+1)
+	var view []int
+	for i, item := range items {
+		if i != itemIndex && visible(item) {
+			view = append(view, i)
+		}
+	}
+	if viewIndex < 0 || viewIndex > len(view) {
+		return Result[T]{}, &PositionError{Op: "MoveInView", Requested: viewIndex, Min: 0, Max: len(view)}
+	}
+	if len(view) == 0 {
+		return os.unmoved(items, itemIndex), nil
+	}
+
+	var insertIndex int
+	if viewIndex == 0 {
+		insertIndex = view[0]
+	} else {
+		insertIndex = view[viewIndex-1] + 1
+	}
+	if itemIndex < insertIndex {
+		// The slots shift up once the item is removed
+		insertIndex--
+	}
+	return os.move("MoveInView", items, itemIndex, insertIndex)
 }
 
-// Below moves an item to be directly below the target item.
-func (os *OrderManager[T]) Below(items []T, itemID string, targetID string) error {
+// relativeIndices looks up the item and target of an Above or Below move.
+func (os *KeyedManager[T, ID]) relativeIndices(op string, items []T, itemID, targetID ID) (int, int, error) {
+	if itemID == targetID && os.opts.sameItemErrors {
+		return -1, -1, fmt.Errorf("%s: %w", op, ErrSameItem)
+	}
+	itemIndex, err := os.GetItemIndexByID(items, itemID)
+	if err != nil {
+		return -1, -1, err
+	}
 	targetIndex, err := os.GetItemIndexByID(items, targetID)
 	if err != nil {
+		return -1, -1, err
+	}
+	return itemIndex, targetIndex, nil
+}
+
+// bare reports whether a move needs neither instrumentation nor soft-delete
+// handling, so it can skip the deferred bookkeeping.
+func (os *KeyedManager[T, ID]) bare() bool {
+	return os.opts.metrics == nil && os.opts.logger == nil && !os.deletable
+}
+
+// unmoved returns the Result of a move that left the item at index in place.
+func (os *KeyedManager[T, ID]) unmoved(items []T, index int) Result[T] {
+	if index < 0 {
+		return Result[T]{}
+	}
+	position := os.getPos(items[index])
+	return Result[T]{OldPosition: position, NewPosition: position}
+}
+
+// normalize renumbers items like NormalizePositions and returns the items whose
+// position actually changed.
+func (os *KeyedManager[T, ID]) normalize(items []T) []T {
+	return os.renumber(items, 0)
+}
+
+// renumber is normalize with the capacity of the returned slice, allocated on
+// the first change, sized for the number of items expected to change.
+func (os *KeyedManager[T, ID]) renumber(items []T, expected int) []T {
+	var affected []T
+	for i := range items {
+		if os.getPos(items[i]) != os.opts.position(i) {
+			if affected == nil && expected > 0 {
+				affected = make([]T, 0, expected)
+			}
+			os.setPos(&items[i], os.opts.position(i))
+			affected = append(affected, items[i])
+		}
+	}
+	return affected
+}
+
+// result builds the Result of moving the item now at index from oldPosition.
+func (os *KeyedManager[T, ID]) result(items []T, index, oldPosition int, affected []T) Result[T] {
+	return Result[T]{
+		Changed:     len(affected) > 0,
+		OldPosition: oldPosition,
+		NewPosition: os.getPos(items[index]),
+		Affected:    affected,
+	}
+}
+
+// validate returns a *ValidationError if strict validation is enabled and items
+// are not consistently ordered. Gaps are tolerated since every move closes them.
+func (os *KeyedManager[T, ID]) validate(items []T) error {
+	if !os.opts.strict {
+		return nil
+	}
+	var issues []ValidationIssue
+	for _, issue := range validate(items, os.getID, os.getPos) {
+		if issue.Kind != IssueGap && (issue.Kind != IssueNonPositivePosition || os.opts.base() >= 1) {
+			issues = append(issues, issue)
+		}
+	}
+	if len(issues) > 0 {
+		err := &ValidationError{Issues: issues}
+		os.logValidationFailure(err)
 		return err
 	}
-	return os.To(items, itemID, targetIndex+2)
+	return nil
+}
+
+// instrument starts observing an operation and returns the function that
+// reports it to the configured metrics and logger once finished:
+//
+//	defer os.instrument(op, items, itemID)(&result, &err)
+func (os *KeyedManager[T, ID]) instrument(op string, items []T, itemID ID) func(*Result[T], *error) {
+	metrics, logger := os.opts.metrics, os.opts.logger
+	if metrics == nil && logger == nil {
+		return func(*Result[T], *error) {}
+	}
+	start := time.Now()
+	return func(result *Result[T], err *error) {
+		stats := OperationStats{
+			Op:       op,
+			ListSize: len(items),
+			Affected: len(result.Affected),
+			Duration: time.Since(start),
+			Err:      *err,
+		}
+		if metrics != nil {
+			metrics.ObserveOperation(stats)
+		}
+		if logger != nil {
+			os.logOperation(stats, itemID)
+		}
+	}
+}
+
+// formatID renders an ID for errors and logs.
+func formatID[ID comparable](id ID) string {
+	if s, ok := any(id).(string); ok {
+		return s
+	}
+	return fmt.Sprint(id)
+}
+
+func isLocked[T any](item T) bool {
+	l, ok := any(item).(Lockable)
+	return ok && l.IsLocked()
 }
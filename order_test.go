@@ -3,9 +3,11 @@ package order_test
 
 import (
 	"errors"
+	"fmt"
 	"testing"
 
 	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
@@ -46,7 +48,7 @@ func TestUp(t *testing.T) {
 	items := createTestItems(3)
 
 	itemID := items[1].GetID() // Middle item
-	err := os.Up(items, itemID)
+	_, err := os.Up(items, itemID)
 	assert.NoError(t, err)
 
 	index, _ := os.GetItemIndexByID(items, itemID)
@@ -59,7 +61,7 @@ func TestDown(t *testing.T) {
 	items := createTestItems(3)
 
 	itemID := items[1].GetID() // Middle item
-	err := os.Down(items, itemID)
+	_, err := os.Down(items, itemID)
 	assert.NoError(t, err)
 
 	index, _ := os.GetItemIndexByID(items, itemID)
@@ -72,7 +74,7 @@ func TestTo(t *testing.T) {
 	items := createTestItems(5)
 
 	itemID := items[0].GetID() // First item
-	err := os.To(items, itemID, 3)
+	_, err := os.To(items, itemID, 3)
 	assert.NoError(t, err)
 
 	index, _ := os.GetItemIndexByID(items, itemID)
@@ -85,7 +87,7 @@ func TestTop(t *testing.T) {
 	items := createTestItems(5)
 
 	itemID := items[3].GetID() // Item at position 4
-	err := os.Top(items, itemID)
+	_, err := os.Top(items, itemID)
 	assert.NoError(t, err)
 
 	index, _ := os.GetItemIndexByID(items, itemID)
@@ -98,7 +100,7 @@ func TestBottom(t *testing.T) {
 	items := createTestItems(5)
 
 	itemID := items[1].GetID() // Item at position 2
-	err := os.Bottom(items, itemID)
+	_, err := os.Bottom(items, itemID)
 	assert.NoError(t, err)
 
 	index, _ := os.GetItemIndexByID(items, itemID)
@@ -112,7 +114,7 @@ func TestAbove(t *testing.T) {
 
 	itemID := items[4].GetID()   // Last item
 	targetID := items[1].GetID() // Target is at position 2
-	err := os.Above(items, itemID, targetID)
+	_, err := os.Above(items, itemID, targetID)
 	assert.NoError(t, err)
 
 	index, _ := os.GetItemIndexByID(items, itemID)
@@ -126,12 +128,13 @@ func TestBelow(t *testing.T) {
 
 	itemID := items[0].GetID()   // First item
 	targetID := items[2].GetID() // Target is at position 3
-	err := os.Below(items, itemID, targetID)
+	_, err := os.Below(items, itemID, targetID)
 	assert.NoError(t, err)
 
 	index, _ := os.GetItemIndexByID(items, itemID)
-	assert.Equal(t, 3, index)
-	assert.Equal(t, 4, items[index].GetPosition())
+	assert.Equal(t, 2, index)
+	assert.Equal(t, 3, items[index].GetPosition())
+	assert.Equal(t, targetID, items[index-1].GetID())
 }
 
 func TestNormalizePositions(t *testing.T) {
@@ -167,11 +170,517 @@ func TestInvalidPosition(t *testing.T) {
 
 	itemID := items[0].GetID()
 
-	err := os.To(items, itemID, 0)
+	_, err := os.To(items, itemID, 0)
 	assert.Error(t, err)
 	assert.Equal(t, order.ErrInvalidPosition, errors.Unwrap(err))
 
-	err = os.To(items, itemID, 5)
+	_, err = os.To(items, itemID, 5)
 	assert.Error(t, err)
 	assert.Equal(t, order.ErrInvalidPosition, errors.Unwrap(err))
 }
+
+func TestStrictValidation(t *testing.T) {
+	os := order.NewOrderManager[*TestItem](order.WithStrictValidation())
+	items := createTestItems(3)
+	items[0].SetPosition(2)
+	items[1].SetPosition(2)
+
+	_, err := os.Up(items, items[1].GetID())
+	assert.True(t, errors.Is(err, order.ErrInvalidOrder))
+
+	var validationErr *order.ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, order.IssueDuplicatePosition, validationErr.Issues[0].Kind)
+
+	// Items are left untouched.
+	assert.Equal(t, 2, items[0].GetPosition())
+	assert.Equal(t, 2, items[1].GetPosition())
+
+	for name, move := range map[string]func() error{
+		"Down":   func() error { _, err := os.Down(items, items[0].GetID()); return err },
+		"To":     func() error { _, err := os.To(items, items[0].GetID(), 3); return err },
+		"Top":    func() error { _, err := os.Top(items, items[2].GetID()); return err },
+		"Bottom": func() error { _, err := os.Bottom(items, items[0].GetID()); return err },
+		"Above":  func() error { _, err := os.Above(items, items[2].GetID(), items[0].GetID()); return err },
+		"Below":  func() error { _, err := os.Below(items, items[0].GetID(), items[2].GetID()); return err },
+	} {
+		assert.True(t, errors.Is(move(), order.ErrInvalidOrder), name)
+	}
+}
+
+func TestStrictValidationAllowsGaps(t *testing.T) {
+	os := order.NewOrderManager[*TestItem](order.WithStrictValidation())
+	items := createTestItems(3)
+	items[0].SetPosition(10)
+	items[1].SetPosition(20)
+	items[2].SetPosition(30)
+
+	_, err := os.Top(items, items[2].GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, 1, items[0].GetPosition())
+}
+
+func TestDuplicateDetection(t *testing.T) {
+	items := createTestItems(4)
+	items[3].ID = items[1].ID
+	duplicateID := items[1].GetID()
+
+	// Without detection the first match wins.
+	index, err := order.NewOrderManager[*TestItem]().GetItemIndexByID(items, duplicateID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, index)
+
+	os := order.NewOrderManager[*TestItem](order.WithDuplicateDetection())
+	index, err = os.GetItemIndexByID(items, duplicateID)
+	assert.Equal(t, -1, index)
+	assert.True(t, errors.Is(err, order.ErrDuplicateID))
+
+	var dupErr *order.DuplicateIDError
+	assert.True(t, errors.As(err, &dupErr))
+	assert.Equal(t, duplicateID, dupErr.ItemID)
+	assert.Equal(t, 1, dupErr.FirstIndex)
+	assert.Equal(t, 3, dupErr.SecondIndex)
+
+	_, err = os.Top(items, duplicateID)
+	assert.True(t, errors.Is(err, order.ErrDuplicateID))
+
+	index, err = os.GetItemIndexByID(items, items[0].GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, index)
+}
+
+func TestBoundaryMoves(t *testing.T) {
+	items := createTestItems(3)
+
+	os := order.NewOrderManager[*TestItem]()
+	_, err := os.Up(items, items[0].GetID())
+	assert.NoError(t, err)
+	_, err = os.Down(items, items[2].GetID())
+	assert.NoError(t, err)
+
+	os = order.NewOrderManager[*TestItem](order.WithBoundaryErrors())
+	_, err = os.Up(items, items[0].GetID())
+	assert.True(t, errors.Is(err, order.ErrAlreadyAtTop))
+	_, err = os.Down(items, items[2].GetID())
+	assert.True(t, errors.Is(err, order.ErrAlreadyAtBottom))
+	_, err = os.Up(items, items[2].GetID())
+	assert.NoError(t, err)
+}
+
+func TestResult(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(5)
+	moved := items[3]
+
+	result, err := os.To(items, moved.GetID(), 2)
+	assert.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Equal(t, 4, result.OldPosition)
+	assert.Equal(t, 2, result.NewPosition)
+	assert.Equal(t, []*TestItem{items[1], items[2], items[3]}, result.Affected)
+
+	result, err = os.To(items, moved.GetID(), 2)
+	assert.NoError(t, err)
+	assert.False(t, result.Changed)
+	assert.Equal(t, 2, result.OldPosition)
+	assert.Equal(t, 2, result.NewPosition)
+	assert.Empty(t, result.Affected)
+
+	result, err = os.Up(items, items[0].GetID())
+	assert.NoError(t, err)
+	assert.False(t, result.Changed)
+
+	result, err = os.Down(items, items[0].GetID())
+	assert.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Len(t, result.Affected, 2)
+}
+
+func TestAboveBelowFromEitherSide(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(5)
+	a, c, e := items[0], items[2], items[4]
+
+	_, err := os.Above(items, a.GetID(), e.GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, e.GetPosition()-1, a.GetPosition())
+
+	_, err = os.Below(items, e.GetID(), c.GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, c.GetPosition()+1, e.GetPosition())
+
+	_, err = os.Below(items, c.GetID(), a.GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, a.GetPosition()+1, c.GetPosition())
+
+	for i, item := range items {
+		assert.Equal(t, i+1, item.GetPosition())
+	}
+}
+
+func TestAboveBelowAlreadyInPlace(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(3)
+
+	result, err := os.Above(items, items[0].GetID(), items[1].GetID())
+	assert.NoError(t, err)
+	assert.False(t, result.Changed)
+	assert.Equal(t, 1, result.NewPosition)
+
+	result, err = os.Below(items, items[2].GetID(), items[1].GetID())
+	assert.NoError(t, err)
+	assert.False(t, result.Changed)
+	assert.Equal(t, 3, result.NewPosition)
+}
+
+func TestAboveBelowSameItem(t *testing.T) {
+	items := createTestItems(3)
+	itemID := items[1].GetID()
+
+	os := order.NewOrderManager[*TestItem]()
+	result, err := os.Above(items, itemID, itemID)
+	assert.NoError(t, err)
+	assert.False(t, result.Changed)
+	assert.Equal(t, 2, result.OldPosition)
+	result, err = os.Below(items, itemID, itemID)
+	assert.NoError(t, err)
+	assert.False(t, result.Changed)
+
+	os = order.NewOrderManager[*TestItem](order.WithSameItemErrors())
+	_, err = os.Above(items, itemID, itemID)
+	assert.True(t, errors.Is(err, order.ErrSameItem))
+	_, err = os.Below(items, itemID, itemID)
+	assert.True(t, errors.Is(err, order.ErrSameItem))
+	assert.Equal(t, itemID, items[1].GetID())
+}
+
+func TestIndexBase(t *testing.T) {
+	os := order.NewOrderManager[*TestItem](order.WithIndexBase(0), order.WithStrictValidation())
+	items := createTestItems(3)
+	os.NormalizePositions(items)
+	assert.Equal(t, []int{0, 1, 2}, positionsOf(items))
+
+	lastID := items[2].GetID()
+	result, err := os.To(items, lastID, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, result.OldPosition)
+	assert.Equal(t, 0, result.NewPosition)
+	assert.Equal(t, lastID, items[0].GetID())
+
+	result, err = os.Down(items, lastID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.NewPosition)
+	assert.Len(t, result.Affected, 2)
+
+	_, err = os.To(items, lastID, 3)
+	var posErr *order.PositionError
+	assert.True(t, errors.As(err, &posErr))
+	assert.Equal(t, 0, posErr.Min)
+	assert.Equal(t, 2, posErr.Max)
+}
+
+func TestPositionStep(t *testing.T) {
+	os := order.NewOrderManager[*TestItem](order.WithIndexBase(10), order.WithPositionStep(10))
+	items := createTestItems(3)
+	os.NormalizePositions(items)
+	assert.Equal(t, []int{10, 20, 30}, positionsOf(items))
+
+	firstID := items[0].GetID()
+	result, err := os.Bottom(items, firstID)
+	assert.NoError(t, err)
+	assert.Equal(t, 30, result.NewPosition)
+	assert.Equal(t, []int{10, 20, 30}, positionsOf(items))
+
+	result, err = os.Up(items, firstID)
+	assert.NoError(t, err)
+	assert.Equal(t, 20, result.NewPosition)
+	assert.Len(t, result.Affected, 2)
+
+	// To counts slots from the base, not positions.
+	result, err = os.To(items, firstID, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 10, result.NewPosition)
+}
+
+func positionsOf(items []*TestItem) []int {
+	positions := make([]int, len(items))
+	for i, item := range items {
+		positions[i] = item.GetPosition()
+	}
+	return positions
+}
+
+// plainItem has no methods and is ordered by value through a FuncManager.
+type plainItem struct {
+	Key  string
+	Rank int
+}
+
+func newPlainManager(opts ...order.Option) *order.FuncManager[plainItem] {
+	return order.NewFuncManager(
+		func(p plainItem) string { return p.Key },
+		func(p plainItem) int { return p.Rank },
+		func(p *plainItem, rank int) { p.Rank = rank },
+		opts...,
+	)
+}
+
+func TestFuncManagerValues(t *testing.T) {
+	items := []plainItem{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}}
+	os := newPlainManager()
+
+	result, err := os.Top(items, "c")
+	assert.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Equal(t, 3, result.OldPosition)
+	assert.Equal(t, 1, result.NewPosition)
+	assert.Equal(t, []plainItem{{"c", 1}, {"a", 2}, {"b", 3}, {"d", 4}}, items)
+	assert.Equal(t, []plainItem{{"c", 1}, {"a", 2}, {"b", 3}}, result.Affected)
+
+	_, err = os.Below(items, "c", "b")
+	assert.NoError(t, err)
+	assert.Equal(t, []plainItem{{"a", 1}, {"b", 2}, {"c", 3}, {"d", 4}}, items)
+
+	_, err = os.Down(items, "missing")
+	var notFound *order.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+}
+
+func TestFuncManagerNormalizeAndStrict(t *testing.T) {
+	items := []plainItem{{"a", 4}, {"b", 9}}
+	newPlainManager().NormalizePositions(items)
+	assert.Equal(t, []plainItem{{"a", 1}, {"b", 2}}, items)
+
+	items[1].Rank = 1
+	_, err := newPlainManager(order.WithStrictValidation()).Up(items, "b")
+	assert.ErrorIs(t, err, order.ErrInvalidOrder)
+}
+
+func TestValueManager(t *testing.T) {
+	values := make([]TestItem, 3)
+	for i, item := range createTestItems(3) {
+		values[i] = *item
+	}
+	firstID := values[0].GetID()
+
+	vm := order.NewValueManager[TestItem]()
+	result, err := vm.Bottom(values, firstID)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, result.NewPosition)
+	assert.Equal(t, firstID, values[2].GetID())
+	for i, item := range values {
+		assert.Equal(t, i+1, item.Position)
+	}
+
+	_, err = vm.Above(values, firstID, values[0].GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, firstID, values[0].GetID())
+	assert.Equal(t, 1, values[0].Position)
+}
+
+// uuidItem is keyed by its uuid.UUID rather than a string.
+type uuidItem struct {
+	ID       uuid.UUID
+	Position int
+}
+
+func (u *uuidItem) GetID() uuid.UUID         { return u.ID }
+func (u *uuidItem) GetPosition() int         { return u.Position }
+func (u *uuidItem) SetPosition(position int) { u.Position = position }
+
+func TestOrderManagerBy(t *testing.T) {
+	items := []*uuidItem{{uuid.New(), 1}, {uuid.New(), 2}, {uuid.New(), 3}}
+	third := items[2].ID
+
+	om := order.NewOrderManagerBy[*uuidItem, uuid.UUID]()
+	result, err := om.Above(items, third, items[0].ID)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.NewPosition)
+	assert.Equal(t, third, items[0].ID)
+
+	index, err := om.GetItemIndexByID(items, third)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, index)
+
+	missing := uuid.New()
+	_, err = om.Up(items, missing)
+	var notFound *order.NotFoundError
+	assert.ErrorAs(t, err, &notFound)
+	assert.Equal(t, missing.String(), notFound.ItemID)
+}
+
+func TestToFraction(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(5)
+	itemID := items[0].GetID()
+
+	for _, tc := range []struct {
+		fraction float64
+		position int
+	}{{0.5, 3}, {1, 5}, {0, 1}, {0.25, 2}, {0.9, 5}} {
+		result, err := os.ToFraction(items, itemID, tc.fraction)
+		assert.NoError(t, err)
+		assert.Equal(t, tc.position, result.NewPosition, "fraction %v", tc.fraction)
+	}
+
+	_, err := os.ToFraction(items, itemID, 1.5)
+	assert.ErrorIs(t, err, order.ErrInvalidPosition)
+	_, err = os.ToFraction(nil, itemID, 0.5)
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
+
+func TestMoveInView(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(6)
+	all := append([]*TestItem(nil), items...)
+	// Only items at odd positions are shown: all[0], all[2], all[4].
+	visible := func(item *TestItem) bool { return item != all[1] && item != all[3] && item != all[5] }
+
+	// Drop all[4] at the top of the view: above all[0].
+	result, err := os.MoveInView(items, visible, all[4].GetID(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, result.NewPosition)
+	assert.Equal(t, []*TestItem{all[4], all[0], all[1], all[2], all[3], all[5]}, items)
+
+	// Drop all[4] at view index 1: directly below all[0], before hidden all[1].
+	_, err = os.MoveInView(items, visible, all[4].GetID(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestItem{all[0], all[4], all[1], all[2], all[3], all[5]}, items)
+
+	// Drop hidden all[5] at the end of the view: directly below all[2].
+	_, err = os.MoveInView(items, visible, all[5].GetID(), 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestItem{all[0], all[4], all[1], all[2], all[5], all[3]}, items)
+	assert.True(t, order.IsNormalized(items))
+
+	_, err = os.MoveInView(items, visible, all[0].GetID(), 4)
+	var posErr *order.PositionError
+	assert.ErrorAs(t, err, &posErr)
+}
+
+func TestMoveMany(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(6)
+	all := append([]*TestItem(nil), items...)
+
+	// Drag all[4] and all[1] to the top; the block keeps slice order.
+	result, err := os.MoveMany(items, []string{all[4].GetID(), all[1].GetID()}, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestItem{all[1], all[4], all[0], all[2], all[3], all[5]}, items)
+	assert.Equal(t, 5, result.OldPosition)
+	assert.Equal(t, 2, result.NewPosition)
+	assert.Len(t, result.Affected, 5)
+	assert.True(t, order.IsNormalized(items))
+
+	// Position 5 is after the last of the four remaining items.
+	_, err = os.MoveMany(items, []string{all[1].GetID(), all[0].GetID()}, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestItem{all[4], all[2], all[3], all[5], all[1], all[0]}, items)
+
+	_, err = os.MoveMany(items, []string{all[1].GetID()}, 7)
+	var posErr *order.PositionError
+	assert.ErrorAs(t, err, &posErr)
+	assert.Equal(t, 6, posErr.Max)
+	_, err = os.MoveMany(items, []string{all[1].GetID(), "missing"}, 1)
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+
+	result, err = os.MoveMany(items, nil, 1)
+	assert.NoError(t, err)
+	assert.False(t, result.Changed)
+}
+
+func BenchmarkMoveMany(b *testing.B) {
+	os := order.NewOrderManager[*TestItem]()
+	for _, n := range []int{1_000, 10_000, 100_000} {
+		items := createTestItems(n)
+		ids := make([]string, 0, n/20)
+		for i := 0; i < n; i += 20 {
+			ids = append(ids, items[i].GetID())
+		}
+		b.Run(fmt.Sprintf("n=%d/k=%d", n, len(ids)), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				if _, err := os.MoveMany(items, ids, 1+i%2); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
+
+func TestToAllocations(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.Items(10_000)
+	i := 0
+	allocs := testing.AllocsPerRun(100, func() {
+		i++
+		if _, err := om.To(items, "item-1", 1+i%2*9_999); err != nil {
+			t.Fatal(err)
+		}
+	})
+	// Only Result.Affected is allocated; the splice itself works in place.
+	assert.LessOrEqual(t, allocs, 1.0)
+}
+
+func BenchmarkTo(b *testing.B) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.Items(100_000)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := om.To(items, "item-50000", 1+i%100_000); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestUpDownOnlyTouchSwappedItems(t *testing.T) {
+	os := order.NewOrderManager[*TestItem]()
+	items := createTestItems(5)
+	all := append([]*TestItem(nil), items...)
+	all[4].Position = 9 // a gap elsewhere is left alone
+
+	result, err := os.Up(items, all[2].GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestItem{all[2], all[1]}, result.Affected)
+	assert.Equal(t, 2, all[2].Position)
+	assert.Equal(t, 3, all[1].Position)
+	assert.Equal(t, 9, all[4].Position)
+
+	result, err = os.Down(items, all[2].GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, []*TestItem{all[1], all[2]}, result.Affected)
+
+	// Moving next to the out-of-place item renumbers the whole slice.
+	result, err = os.Down(items, all[3].GetID())
+	assert.NoError(t, err)
+	assert.Equal(t, 4, result.OldPosition)
+	assert.Equal(t, 5, result.NewPosition)
+	assert.True(t, order.IsNormalized(items))
+}
+
+func TestNormalizePositionsParallel(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	for _, n := range []int{0, 10, 100_000} {
+		items := ordertest.Shuffled(n, 1)
+		for _, item := range items {
+			item.Position *= 3
+		}
+		om.NormalizePositionsParallel(items, 4)
+		assert.True(t, order.IsNormalized(items), "n=%d", n)
+	}
+}
+
+func BenchmarkNormalizePositions(b *testing.B) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.Items(1_000_000)
+	b.Run("serial", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			om.NormalizePositions(items)
+		}
+	})
+	b.Run("parallel", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			om.NormalizePositionsParallel(items, 0)
+		}
+	})
+}
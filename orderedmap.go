@@ -0,0 +1,142 @@
+package order
+
+import "iter"
+
+// OrderedMap maps IDs to values and keeps the entries in a user-controlled order
+// that is changed with the same verbs as an OrderManager.
+type OrderedMap[V any] struct {
+	entries []*mapEntry[V]
+	index   map[string]*mapEntry[V]
+	manager *OrderManager[*mapEntry[V]]
+}
+
+type mapEntry[V any] struct {
+	key      string
+	value    V
+	position int
+}
+
+func (e *mapEntry[V]) GetID() string            { return e.key }
+func (e *mapEntry[V]) GetPosition() int         { return e.position }
+func (e *mapEntry[V]) SetPosition(position int) { e.position = position }
+
+// NewOrderedMap creates an empty OrderedMap. The options configure the moves.
+func NewOrderedMap[V any](opts ...Option) *OrderedMap[V] {
+	return &OrderedMap[V]{
+		index:   make(map[string]*mapEntry[V]),
+		manager: NewOrderManager[*mapEntry[V]](opts...),
+	}
+}
+
+// Set stores value under key. A new key is added at the last position; an
+// existing key keeps its position.
+func (m *OrderedMap[V]) Set(key string, value V) {
+	if e, ok := m.index[key]; ok {
+		e.value = value
+		return
+	}
+	e := &mapEntry[V]{key: key, value: value, position: m.manager.opts.position(len(m.entries))}
+	m.entries = append(m.entries, e)
+	m.index[key] = e
+}
+
+// Get returns the value stored under key.
+func (m *OrderedMap[V]) Get(key string) (V, bool) {
+	e, ok := m.index[key]
+	if !ok {
+		var zero V
+		return zero, false
+	}
+	return e.value, true
+}
+
+// Position returns the position of key, counted from 1 or as set with
+// WithIndexBase and WithPositionStep.
+func (m *OrderedMap[V]) Position(key string) (int, bool) {
+	e, ok := m.index[key]
+	if !ok {
+		return 0, false
+	}
+	return e.position, true
+}
+
+// Delete removes key and closes the gap it leaves. It reports whether the key
+// was present.
+func (m *OrderedMap[V]) Delete(key string) bool {
+	e, ok := m.index[key]
+	if !ok {
+		return false
+	}
+	index, _ := m.manager.GetItemIndexByID(m.entries, e.key)
+	delete(m.index, key)
+	m.entries = append(m.entries[:index], m.entries[index+1:]...)
+	m.manager.normalize(m.entries)
+	return true
+}
+
+// Len returns the number of entries.
+func (m *OrderedMap[V]) Len() int {
+	return len(m.entries)
+}
+
+// Keys returns the keys in order.
+func (m *OrderedMap[V]) Keys() []string {
+	keys := make([]string, len(m.entries))
+	for i, e := range m.entries {
+		keys[i] = e.key
+	}
+	return keys
+}
+
+// All returns an iterator over the keys and values in order.
+func (m *OrderedMap[V]) All() iter.Seq2[string, V] {
+	return func(yield func(string, V) bool) {
+		for _, e := range m.entries {
+			if !yield(e.key, e.value) {
+				return
+			}
+		}
+	}
+}
+
+// Up moves key up by one position.
+func (m *OrderedMap[V]) Up(key string) error {
+	_, err := m.manager.Up(m.entries, key)
+	return err
+}
+
+// Down moves key down by one position.
+func (m *OrderedMap[V]) Down(key string) error {
+	_, err := m.manager.Down(m.entries, key)
+	return err
+}
+
+// To moves key to the given position.
+func (m *OrderedMap[V]) To(key string, position int) error {
+	_, err := m.manager.To(m.entries, key, position)
+	return err
+}
+
+// Top moves key to the first position.
+func (m *OrderedMap[V]) Top(key string) error {
+	_, err := m.manager.Top(m.entries, key)
+	return err
+}
+
+// Bottom moves key to the last position.
+func (m *OrderedMap[V]) Bottom(key string) error {
+	_, err := m.manager.Bottom(m.entries, key)
+	return err
+}
+
+// Above moves key directly above targetKey.
+func (m *OrderedMap[V]) Above(key, targetKey string) error {
+	_, err := m.manager.Above(m.entries, key, targetKey)
+	return err
+}
+
+// Below moves key directly below targetKey.
+func (m *OrderedMap[V]) Below(key, targetKey string) error {
+	_, err := m.manager.Below(m.entries, key, targetKey)
+	return err
+}
@@ -0,0 +1,91 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMap() *order.OrderedMap[int] {
+	m := order.NewOrderedMap[int]()
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+	return m
+}
+
+func TestOrderedMapSetGet(t *testing.T) {
+	m := newTestMap()
+	m.Set("a", 10)
+
+	value, ok := m.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 10, value)
+	_, ok = m.Get("missing")
+	assert.False(t, ok)
+
+	assert.Equal(t, 3, m.Len())
+	assert.Equal(t, []string{"a", "b", "c"}, m.Keys())
+	position, ok := m.Position("c")
+	assert.True(t, ok)
+	assert.Equal(t, 3, position)
+}
+
+func TestOrderedMapMoves(t *testing.T) {
+	m := newTestMap()
+
+	assert.NoError(t, m.Top("c"))
+	assert.Equal(t, []string{"c", "a", "b"}, m.Keys())
+	assert.NoError(t, m.Below("c", "b"))
+	assert.Equal(t, []string{"a", "b", "c"}, m.Keys())
+	assert.NoError(t, m.To("a", 2))
+	assert.Equal(t, []string{"b", "a", "c"}, m.Keys())
+	assert.NoError(t, m.Up("c"))
+	assert.NoError(t, m.Down("b"))
+	assert.NoError(t, m.Above("c", "b"))
+	assert.Equal(t, []string{"c", "b", "a"}, m.Keys())
+	assert.NoError(t, m.Bottom("c"))
+	assert.Equal(t, []string{"b", "a", "c"}, m.Keys())
+
+	position, _ := m.Position("a")
+	assert.Equal(t, 2, position)
+	assert.ErrorIs(t, m.Up("missing"), order.ErrItemNotFound)
+}
+
+func TestOrderedMapDelete(t *testing.T) {
+	m := newTestMap()
+
+	assert.True(t, m.Delete("a"))
+	assert.False(t, m.Delete("a"))
+	assert.Equal(t, []string{"b", "c"}, m.Keys())
+	position, _ := m.Position("c")
+	assert.Equal(t, 2, position)
+
+	var keys []string
+	var values []int
+	for key, value := range m.All() {
+		keys = append(keys, key)
+		values = append(values, value)
+	}
+	assert.Equal(t, []string{"b", "c"}, keys)
+	assert.Equal(t, []int{2, 3}, values)
+}
+
+func TestOrderedMapWithPositionStep(t *testing.T) {
+	m := order.NewOrderedMap[int](order.WithIndexBase(0), order.WithPositionStep(10))
+	m.Set("a", 1)
+	m.Set("b", 2)
+	m.Set("c", 3)
+	position, _ := m.Position("c")
+	assert.Equal(t, 20, position)
+
+	assert.True(t, m.Delete("b"))
+	assert.Equal(t, []string{"a", "c"}, m.Keys())
+	position, _ = m.Position("c")
+	assert.Equal(t, 10, position)
+	require.NoError(t, m.To("c", 0))
+	assert.Equal(t, []string{"c", "a"}, m.Keys())
+}
@@ -0,0 +1,137 @@
+package order
+
+// OrderedSet is an ordered list of items with unique IDs. Adding an item whose
+// ID is already present fails with a *DuplicateIDError.
+type OrderedSet[T Orderable] struct {
+	items   []T
+	ids     map[string]struct{}
+	manager *OrderManager[T]
+}
+
+// NewOrderedSet creates a set from items in their current slice order and
+// normalizes their positions. It fails with a *DuplicateIDError if an ID occurs
+// more than once. The options configure the moves.
+func NewOrderedSet[T Orderable](items []T, opts ...Option) (*OrderedSet[T], error) {
+	s := &OrderedSet[T]{ids: make(map[string]struct{}, len(items)), manager: NewOrderManager[T](opts...)}
+	for _, item := range items {
+		if err := s.add("NewOrderedSet", item); err != nil {
+			return nil, err
+		}
+	}
+	s.manager.normalize(s.items)
+	return s, nil
+}
+
+// Add appends item at the last position.
+func (s *OrderedSet[T]) Add(item T) error {
+	if err := s.add("Add", item); err != nil {
+		return err
+	}
+	s.manager.setPos(&s.items[len(s.items)-1], s.manager.opts.position(len(s.items)-1))
+	return nil
+}
+
+func (s *OrderedSet[T]) add(op string, item T) error {
+	id := item.GetID()
+	if _, ok := s.ids[id]; ok {
+		first, _ := s.manager.GetItemIndexByID(s.items, id)
+		return &DuplicateIDError{Op: op, ItemID: id, FirstIndex: first, SecondIndex: len(s.items)}
+	}
+	s.ids[id] = struct{}{}
+	s.items = append(s.items, item)
+	return nil
+}
+
+// Remove removes the item with the given ID and closes the gap it leaves. It
+// reports whether the item was present.
+func (s *OrderedSet[T]) Remove(itemID string) bool {
+	if _, ok := s.ids[itemID]; !ok {
+		return false
+	}
+	index, _ := s.manager.GetItemIndexByID(s.items, itemID)
+	delete(s.ids, itemID)
+	s.items = append(s.items[:index], s.items[index+1:]...)
+	s.manager.normalize(s.items)
+	return true
+}
+
+// Contains reports whether an item with the given ID is in the set.
+func (s *OrderedSet[T]) Contains(itemID string) bool {
+	_, ok := s.ids[itemID]
+	return ok
+}
+
+// Items returns the items of the set in order.
+func (s *OrderedSet[T]) Items() []T {
+	return s.items
+}
+
+// Len returns the number of items in the set.
+func (s *OrderedSet[T]) Len() int {
+	return len(s.items)
+}
+
+// Union returns a set with the items of s followed by the items of other that
+// are not in s, each in their own order. The result shares its items with the
+// operands and renumbers their positions.
+func (s *OrderedSet[T]) Union(other *OrderedSet[T]) *OrderedSet[T] {
+	u := &OrderedSet[T]{ids: make(map[string]struct{}, len(s.items)+len(other.items)), manager: s.manager}
+	for _, item := range s.items {
+		_ = u.add("Union", item)
+	}
+	for _, item := range other.items {
+		if !u.Contains(item.GetID()) {
+			_ = u.add("Union", item)
+		}
+	}
+	u.manager.normalize(u.items)
+	return u
+}
+
+// Intersection returns a set with the items of s that are also in other, in the
+// order of s. Like Union, it renumbers the positions of the shared items.
+func (s *OrderedSet[T]) Intersection(other *OrderedSet[T]) *OrderedSet[T] {
+	in := &OrderedSet[T]{ids: make(map[string]struct{}), manager: s.manager}
+	for _, item := range s.items {
+		if other.Contains(item.GetID()) {
+			_ = in.add("Intersection", item)
+		}
+	}
+	in.manager.normalize(in.items)
+	return in
+}
+
+// Up moves an item up by one position.
+func (s *OrderedSet[T]) Up(itemID string) (Result[T], error) {
+	return s.manager.Up(s.items, itemID)
+}
+
+// Down moves an item down by one position.
+func (s *OrderedSet[T]) Down(itemID string) (Result[T], error) {
+	return s.manager.Down(s.items, itemID)
+}
+
+// To moves an item to a specific position.
+func (s *OrderedSet[T]) To(itemID string, newPosition int) (Result[T], error) {
+	return s.manager.To(s.items, itemID, newPosition)
+}
+
+// Top moves an item to the first position.
+func (s *OrderedSet[T]) Top(itemID string) (Result[T], error) {
+	return s.manager.Top(s.items, itemID)
+}
+
+// Bottom moves an item to the last position.
+func (s *OrderedSet[T]) Bottom(itemID string) (Result[T], error) {
+	return s.manager.Bottom(s.items, itemID)
+}
+
+// Above moves an item to be directly above the target item.
+func (s *OrderedSet[T]) Above(itemID, targetID string) (Result[T], error) {
+	return s.manager.Above(s.items, itemID, targetID)
+}
+
+// Below moves an item to be directly below the target item.
+func (s *OrderedSet[T]) Below(itemID, targetID string) (Result[T], error) {
+	return s.manager.Below(s.items, itemID, targetID)
+}
@@ -0,0 +1,79 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOrderedSetRejectsDuplicates(t *testing.T) {
+	items := createTestItems(3)
+	s, err := order.NewOrderedSet(items)
+	require.NoError(t, err)
+
+	err = s.Add(&TestItem{ID: items[1].ID})
+	var dup *order.DuplicateIDError
+	require.ErrorAs(t, err, &dup)
+	assert.Equal(t, "Add", dup.Op)
+	assert.Equal(t, 1, dup.FirstIndex)
+	assert.Equal(t, 3, s.Len())
+
+	_, err = order.NewOrderedSet([]*TestItem{items[0], items[0]})
+	assert.ErrorIs(t, err, order.ErrDuplicateID)
+}
+
+func TestOrderedSetAddRemove(t *testing.T) {
+	items := createTestItems(3)
+	s, err := order.NewOrderedSet(items[:2])
+	require.NoError(t, err)
+
+	require.NoError(t, s.Add(items[2]))
+	assert.True(t, s.Contains(items[2].GetID()))
+	assert.Equal(t, 3, items[2].GetPosition())
+
+	assert.True(t, s.Remove(items[0].GetID()))
+	assert.False(t, s.Remove(items[0].GetID()))
+	assert.False(t, s.Contains(items[0].GetID()))
+	assert.Equal(t, []*TestItem{items[1], items[2]}, s.Items())
+	assert.True(t, order.IsNormalized(s.Items()))
+}
+
+func TestOrderedSetMoves(t *testing.T) {
+	items := createTestItems(3)
+	s, err := order.NewOrderedSet(items)
+	require.NoError(t, err)
+
+	result, err := s.Top(items[2].GetID())
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewPosition)
+	_, err = s.Below(items[2].GetID(), items[1].GetID())
+	require.NoError(t, err)
+	assert.Equal(t, []*TestItem{items[0], items[1], items[2]}, s.Items())
+}
+
+func TestOrderedSetUnionIntersection(t *testing.T) {
+	items := createTestItems(4)
+	left, err := order.NewOrderedSet([]*TestItem{items[2], items[0], items[1]})
+	require.NoError(t, err)
+	right, err := order.NewOrderedSet([]*TestItem{items[3], items[1], items[2]})
+	require.NoError(t, err)
+
+	union := left.Union(right)
+	assert.Equal(t, []*TestItem{items[2], items[0], items[1], items[3]}, union.Items())
+	assert.True(t, order.IsNormalized(union.Items()))
+
+	intersection := left.Intersection(right)
+	assert.Equal(t, []*TestItem{items[2], items[1]}, intersection.Items())
+	assert.True(t, order.IsNormalized(intersection.Items()))
+}
+
+func TestOrderedSetWithIndexBase(t *testing.T) {
+	s, err := order.NewOrderedSet(createTestItems(2), order.WithIndexBase(0), order.WithPositionStep(10))
+	require.NoError(t, err)
+	item := &TestItem{ID: createTestItems(1)[0].ID}
+	require.NoError(t, s.Add(item))
+	assert.Equal(t, 20, item.Position)
+}
@@ -0,0 +1,60 @@
+package orderevents
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/yacobolo/order"
+)
+
+// CloudEventType is the CloudEvents type of order events.
+const CloudEventType = "com.github.yacobolo.order.changed"
+
+// CloudEvent is a CloudEvents 1.0 envelope of an order event in the
+// structured JSON format.
+type CloudEvent struct {
+	SpecVersion string `json:"specversion"`
+	ID          string `json:"id"`
+	Type        string `json:"type"`
+	Source      string `json:"source"`
+	// Subject is the ID of the moved item. It is empty for a complete order
+	// applied with ApplyOrder.
+	Subject         string                  `json:"subject,omitempty"`
+	Time            time.Time               `json:"time"`
+	DataContentType string                  `json:"datacontenttype"`
+	Data            order.OrderChangedEvent `json:"data"`
+}
+
+// ToCloudEvent wraps event in a CloudEvent from source, the URI of its list.
+// The operation ID of the event is the CloudEvent ID.
+func ToCloudEvent(event order.OrderChangedEvent, source string) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              event.OperationID,
+		Type:            CloudEventType,
+		Source:          source,
+		Subject:         event.Move.ItemID,
+		Time:            event.Time,
+		DataContentType: "application/json",
+		Data:            event,
+	}
+}
+
+// CloudEvents returns an Encoder that wraps events in CloudEvents. source
+// returns the URI of a list; if it is nil, lists are "/lists/<list ID>".
+func CloudEvents(source func(listID string) string) Encoder {
+	if source == nil {
+		source = func(listID string) string { return "/lists/" + listID }
+	}
+	return cloudEventsEncoder{source: source}
+}
+
+type cloudEventsEncoder struct {
+	source func(listID string) string
+}
+
+func (e cloudEventsEncoder) Encode(event order.OrderChangedEvent) ([]byte, error) {
+	return json.Marshal(ToCloudEvent(event, e.source(event.ListID)))
+}
+
+func (cloudEventsEncoder) ContentType() string { return "application/cloudevents+json" }
@@ -0,0 +1,67 @@
+package orderevents_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/orderevents"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloudEvents(t *testing.T) {
+	event := testEvent()
+	encoder := orderevents.CloudEvents(nil)
+	assert.Equal(t, "application/cloudevents+json", encoder.ContentType())
+	data, err := encoder.Encode(event)
+	require.NoError(t, err)
+
+	var raw map[string]any
+	require.NoError(t, json.Unmarshal(data, &raw))
+	assert.Equal(t, "1.0", raw["specversion"])
+	assert.Equal(t, "op-1", raw["id"])
+	assert.Equal(t, orderevents.CloudEventType, raw["type"])
+	assert.Equal(t, "/lists/list", raw["source"])
+	assert.Equal(t, "b", raw["subject"])
+	assert.Equal(t, "2024-05-01T12:00:00Z", raw["time"])
+	assert.Equal(t, "application/json", raw["datacontenttype"])
+
+	var ce orderevents.CloudEvent
+	require.NoError(t, json.Unmarshal(data, &ce))
+	assert.Equal(t, event, ce.Data)
+
+	// ApplyOrder moves no single item.
+	event.Move = order.Move[string]{}
+	ce = orderevents.ToCloudEvent(event, "https://example.com/lists/list")
+	assert.Equal(t, "https://example.com/lists/list", ce.Source)
+	data, err = json.Marshal(ce)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), `"subject"`)
+}
+
+func TestWebhookCloudEvents(t *testing.T) {
+	var contentType string
+	var ce orderevents.CloudEvent
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ := io.ReadAll(r.Body)
+		assert.NoError(t, json.Unmarshal(body, &ce))
+	}))
+	defer srv.Close()
+
+	source := func(listID string) string { return "urn:list:" + listID }
+	d := orderevents.NewWebhookDispatcher(orderevents.WithWebhookEncoder(orderevents.CloudEvents(source)))
+	d.Register(orderevents.Webhook{URL: srv.URL})
+	require.NoError(t, d.Publish(context.Background(), testEvent()))
+	require.NoError(t, d.Close(context.Background()))
+
+	assert.Equal(t, "application/cloudevents+json", contentType)
+	assert.Equal(t, "urn:list:list", ce.Source)
+	assert.Equal(t, "op-1", ce.ID)
+}
@@ -0,0 +1,205 @@
+// Package orderevents publishes the order.OrderChangedEvents of a
+// PersistentManager to message brokers.
+//
+// The publishers do not import a client library. KafkaPublisher writes to a
+// KafkaProducer, a one-method interface that is easy to adapt to any Kafka
+// client, and NATSPublisher writes to a NATSConn, which *nats.Conn
+// implements. WebhookDispatcher POSTs signed JSON to registered URLs. Events
+// are encoded as JSON, protobuf or CloudEvents. Observer turns a Publisher
+// into an observer for order.WithObserver:
+//
+//	pub := orderevents.NewNATSPublisher(nc)
+//	pm := order.NewPersistentManager[*Item](store, om,
+//		order.WithObserver(orderevents.Observer(pub, logPublishError)))
+package orderevents
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/orderpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// DefaultSubject is the Kafka topic of all lists, and the prefix of the NATS
+// subject of each list.
+const DefaultSubject = "order.events"
+
+// Publisher publishes order events.
+type Publisher interface {
+	Publish(ctx context.Context, event order.OrderChangedEvent) error
+}
+
+// Observer returns an observer for order.WithObserver that publishes every
+// event with p. Observers run on the goroutine that made the move, so the
+// move waits for the broker; errors are passed to onError, which may be nil,
+// and do not fail the move, which is already saved.
+func Observer(p Publisher, onError func(order.OrderChangedEvent, error)) func(context.Context, order.OrderChangedEvent) {
+	return func(ctx context.Context, event order.OrderChangedEvent) {
+		if err := p.Publish(ctx, event); err != nil && onError != nil {
+			onError(event, err)
+		}
+	}
+}
+
+// Encoder serializes an event into a message payload.
+type Encoder interface {
+	Encode(event order.OrderChangedEvent) ([]byte, error)
+	// ContentType is the MIME type of the payloads.
+	ContentType() string
+}
+
+// JSON encodes events as order.OrderChangedEvent in JSON.
+var JSON Encoder = jsonEncoder{}
+
+// Protobuf encodes events as the orderpb.OrderChangedEvent message.
+var Protobuf Encoder = protobufEncoder{}
+
+type jsonEncoder struct{}
+
+func (jsonEncoder) Encode(event order.OrderChangedEvent) ([]byte, error) {
+	return json.Marshal(event)
+}
+
+func (jsonEncoder) ContentType() string { return "application/json" }
+
+type protobufEncoder struct{}
+
+func (protobufEncoder) Encode(event order.OrderChangedEvent) ([]byte, error) {
+	return proto.Marshal(orderpb.FromEvent(event))
+}
+
+func (protobufEncoder) ContentType() string { return "application/x-protobuf" }
+
+// Option configures a KafkaPublisher or NATSPublisher.
+type Option func(*config)
+
+type config struct {
+	encoder Encoder
+	subject func(listID string) string
+	key     func(event order.OrderChangedEvent) []byte
+}
+
+// WithEncoder sets the payload encoding. Events are encoded as JSON by
+// default.
+func WithEncoder(encoder Encoder) Option {
+	return func(c *config) {
+		c.encoder = encoder
+	}
+}
+
+// WithSubject sets the Kafka topic or NATS subject that the events of a list
+// are published to.
+func WithSubject(subject func(listID string) string) Option {
+	return func(c *config) {
+		c.subject = subject
+	}
+}
+
+// WithKey sets the Kafka message key of an event. The key is the list ID by
+// default, so that the events of a list land on one partition in order. NATS
+// messages have no key.
+func WithKey(key func(event order.OrderChangedEvent) []byte) Option {
+	return func(c *config) {
+		c.key = key
+	}
+}
+
+func newConfig(subject func(string) string, opts []Option) config {
+	c := config{
+		encoder: JSON,
+		subject: subject,
+		key:     func(event order.OrderChangedEvent) []byte { return []byte(event.ListID) },
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// KafkaMessage is a message for a KafkaProducer.
+type KafkaMessage struct {
+	Topic   string
+	Key     []byte
+	Value   []byte
+	Headers map[string]string
+}
+
+// KafkaProducer writes messages to Kafka. Produce should return once the
+// message is acknowledged.
+type KafkaProducer interface {
+	Produce(ctx context.Context, msg KafkaMessage) error
+}
+
+// KafkaProducerFunc adapts a function to a KafkaProducer.
+type KafkaProducerFunc func(ctx context.Context, msg KafkaMessage) error
+
+// Produce calls f.
+func (f KafkaProducerFunc) Produce(ctx context.Context, msg KafkaMessage) error {
+	return f(ctx, msg)
+}
+
+// KafkaPublisher publishes events to Kafka, by default to the DefaultSubject
+// topic keyed by list ID. Every message carries a content-type header.
+type KafkaPublisher struct {
+	producer KafkaProducer
+	config   config
+}
+
+var _ Publisher = (*KafkaPublisher)(nil)
+
+// NewKafkaPublisher creates a KafkaPublisher that writes to producer.
+func NewKafkaPublisher(producer KafkaProducer, opts ...Option) *KafkaPublisher {
+	subject := func(string) string { return DefaultSubject }
+	return &KafkaPublisher{producer: producer, config: newConfig(subject, opts)}
+}
+
+// Publish implements Publisher.
+func (p *KafkaPublisher) Publish(ctx context.Context, event order.OrderChangedEvent) error {
+	value, err := p.config.encoder.Encode(event)
+	if err != nil {
+		return err
+	}
+	return p.producer.Produce(ctx, KafkaMessage{
+		Topic:   p.config.subject(event.ListID),
+		Key:     p.config.key(event),
+		Value:   value,
+		Headers: map[string]string{"content-type": p.config.encoder.ContentType()},
+	})
+}
+
+// NATSConn publishes NATS messages. *nats.Conn implements it.
+type NATSConn interface {
+	Publish(subject string, data []byte) error
+}
+
+// NATSPublisher publishes events to NATS, by default to the subject
+// "order.events.<list ID>". List IDs used in subjects must not contain dots,
+// spaces or wildcards.
+type NATSPublisher struct {
+	conn   NATSConn
+	config config
+}
+
+var _ Publisher = (*NATSPublisher)(nil)
+
+// NewNATSPublisher creates a NATSPublisher that publishes on conn.
+func NewNATSPublisher(conn NATSConn, opts ...Option) *NATSPublisher {
+	subject := func(listID string) string { return DefaultSubject + "." + listID }
+	return &NATSPublisher{conn: conn, config: newConfig(subject, opts)}
+}
+
+// Publish implements Publisher. NATSConn does not take a context, so ctx is
+// only checked before publishing.
+func (p *NATSPublisher) Publish(ctx context.Context, event order.OrderChangedEvent) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	data, err := p.config.encoder.Encode(event)
+	if err != nil {
+		return err
+	}
+	return p.conn.Publish(p.config.subject(event.ListID), data)
+}
@@ -0,0 +1,107 @@
+package orderevents_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/mockstore"
+	"github.com/yacobolo/order/orderevents"
+	"github.com/yacobolo/order/orderpb"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+type natsConn struct {
+	subjects []string
+	data     [][]byte
+}
+
+func (c *natsConn) Publish(subject string, data []byte) error {
+	c.subjects = append(c.subjects, subject)
+	c.data = append(c.data, data)
+	return nil
+}
+
+func testEvent() order.OrderChangedEvent {
+	return order.OrderChangedEvent{
+		ListID:      "list",
+		OperationID: "op-1",
+		Move:        order.Move[string]{Kind: order.MoveTop, ItemID: "b"},
+		Changes:     order.ChangeSet{{ItemID: "b", OldPosition: 2, NewPosition: 1}},
+		Version:     3,
+		Time:        time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestKafkaPublisher(t *testing.T) {
+	var msgs []orderevents.KafkaMessage
+	producer := orderevents.KafkaProducerFunc(func(_ context.Context, msg orderevents.KafkaMessage) error {
+		msgs = append(msgs, msg)
+		return nil
+	})
+	event := testEvent()
+
+	require.NoError(t, orderevents.NewKafkaPublisher(producer).Publish(context.Background(), event))
+	require.Len(t, msgs, 1)
+	assert.Equal(t, orderevents.DefaultSubject, msgs[0].Topic)
+	assert.Equal(t, []byte("list"), msgs[0].Key)
+	assert.Equal(t, "application/json", msgs[0].Headers["content-type"])
+	var got order.OrderChangedEvent
+	require.NoError(t, json.Unmarshal(msgs[0].Value, &got))
+	assert.Equal(t, event, got)
+
+	pub := orderevents.NewKafkaPublisher(producer,
+		orderevents.WithEncoder(orderevents.Protobuf),
+		orderevents.WithSubject(func(listID string) string { return "lists-" + listID }),
+		orderevents.WithKey(func(e order.OrderChangedEvent) []byte { return []byte(e.Move.ItemID) }))
+	require.NoError(t, pub.Publish(context.Background(), event))
+	require.Len(t, msgs, 2)
+	assert.Equal(t, "lists-list", msgs[1].Topic)
+	assert.Equal(t, []byte("b"), msgs[1].Key)
+	assert.Equal(t, "application/x-protobuf", msgs[1].Headers["content-type"])
+	var msg orderpb.OrderChangedEvent
+	require.NoError(t, proto.Unmarshal(msgs[1].Value, &msg))
+	got, err := orderpb.ToEvent(&msg)
+	require.NoError(t, err)
+	assert.Equal(t, event, got)
+}
+
+func TestNATSPublisher(t *testing.T) {
+	conn := &natsConn{}
+	pub := orderevents.NewNATSPublisher(conn)
+	require.NoError(t, pub.Publish(context.Background(), testEvent()))
+	assert.Equal(t, []string{"order.events.list"}, conn.subjects)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, pub.Publish(ctx, testEvent()), context.Canceled)
+	assert.Len(t, conn.subjects, 1)
+}
+
+func TestObserver(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	conn := &natsConn{}
+	failing := orderevents.KafkaProducerFunc(func(context.Context, orderevents.KafkaMessage) error {
+		return errors.New("broker down")
+	})
+	var failed []string
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](),
+		order.WithObserver(orderevents.Observer(orderevents.NewNATSPublisher(conn), nil)),
+		order.WithObserver(orderevents.Observer(orderevents.NewKafkaPublisher(failing), func(event order.OrderChangedEvent, err error) {
+			failed = append(failed, event.OperationID)
+		})))
+
+	result, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"order.events.list"}, conn.subjects)
+	assert.Equal(t, []string{result.OperationID}, failed)
+}
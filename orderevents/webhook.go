@@ -0,0 +1,349 @@
+package orderevents
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/yacobolo/order"
+)
+
+// Headers of webhook requests. The signature is "sha256=" followed by the hex
+// HMAC-SHA256, keyed with the webhook secret, of the timestamp, a dot and
+// the body.
+const (
+	SignatureHeader = "X-Order-Signature"
+	TimestampHeader = "X-Order-Timestamp"
+	EventIDHeader   = "X-Order-Event-Id"
+)
+
+var (
+	ErrDispatcherClosed = errors.New("webhook dispatcher closed")
+	ErrQueueFull        = errors.New("webhook queue full")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+)
+
+// Webhook is an endpoint that receives order events.
+type Webhook struct {
+	URL    string
+	Secret []byte
+	// ListIDs restricts the webhook to the events of these lists. An empty
+	// slice subscribes to all lists.
+	ListIDs []string
+}
+
+func (h Webhook) wants(listID string) bool {
+	return len(h.ListIDs) == 0 || slices.Contains(h.ListIDs, listID)
+}
+
+// DeadLetter is an event that could not be delivered to a webhook.
+type DeadLetter struct {
+	Webhook  Webhook
+	Event    order.OrderChangedEvent
+	Attempts int
+	Err      error
+}
+
+// WebhookOption configures a WebhookDispatcher.
+type WebhookOption func(*webhookConfig)
+
+type webhookConfig struct {
+	encoder    Encoder
+	client     *http.Client
+	retry      order.RetryPolicy
+	deadLetter func(DeadLetter)
+	queue      int
+	workers    int
+}
+
+// DefaultWebhookRetry makes up to 6 attempts at a delivery over about a minute.
+var DefaultWebhookRetry = order.RetryPolicy{Attempts: 6, Backoff: 2 * time.Second, MaxBackoff: 30 * time.Second, Jitter: 0.2}
+
+// WithWebhookEncoder sets the body encoding of webhook requests, for example
+// CloudEvents. Events are encoded as JSON by default.
+func WithWebhookEncoder(encoder Encoder) WebhookOption {
+	return func(c *webhookConfig) {
+		c.encoder = encoder
+	}
+}
+
+// WithWebhookClient sets the HTTP client of deliveries. It defaults to a
+// client with a 10 second timeout.
+func WithWebhookClient(client *http.Client) WebhookOption {
+	return func(c *webhookConfig) {
+		c.client = client
+	}
+}
+
+// WithWebhookRetry sets how often and how fast failed deliveries are retried.
+// It defaults to DefaultWebhookRetry.
+func WithWebhookRetry(policy order.RetryPolicy) WebhookOption {
+	return func(c *webhookConfig) {
+		c.retry = policy
+	}
+}
+
+// WithDeadLetter sets the handler of deliveries that failed for good. It is
+// called from the delivery goroutines.
+func WithDeadLetter(handle func(DeadLetter)) WebhookOption {
+	return func(c *webhookConfig) {
+		c.deadLetter = handle
+	}
+}
+
+// WithWebhookQueue sets the number of deliveries that may wait for a worker,
+// 1024 by default, and the number of workers, 4 by default. A size below 0
+// means 0, and fewer than 1 worker means 1.
+func WithWebhookQueue(size, workers int) WebhookOption {
+	return func(c *webhookConfig) {
+		c.queue = max(size, 0)
+		c.workers = max(workers, 1)
+	}
+}
+
+// WebhookDispatcher is a Publisher that POSTs every event, as JSON by
+// default, to the registered webhooks. Deliveries run in the background so
+// that slow endpoints do not hold up moves. A delivery that fails with a
+// network error, a 429 or a 5xx response is retried with backoff; one that
+// fails for good, gets another 4xx response or does not fit in the queue is
+// handed to the dead-letter handler.
+type WebhookDispatcher struct {
+	config webhookConfig
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu     sync.RWMutex
+	hooks  map[string]Webhook
+	queue  chan delivery
+	closed bool
+	wg     sync.WaitGroup
+}
+
+var _ Publisher = (*WebhookDispatcher)(nil)
+
+type delivery struct {
+	hook    Webhook
+	event   order.OrderChangedEvent
+	payload []byte
+}
+
+// NewWebhookDispatcher creates a WebhookDispatcher without webhooks and
+// starts its workers. Call Close to stop them.
+func NewWebhookDispatcher(opts ...WebhookOption) *WebhookDispatcher {
+	c := webhookConfig{
+		encoder: JSON,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		retry:   DefaultWebhookRetry,
+		queue:   1024,
+		workers: 4,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &WebhookDispatcher{
+		config: c,
+		ctx:    ctx,
+		cancel: cancel,
+		hooks:  make(map[string]Webhook),
+		queue:  make(chan delivery, c.queue),
+	}
+	for range c.workers {
+		d.wg.Add(1)
+		go d.work()
+	}
+	return d
+}
+
+// Register adds hook, replacing a webhook with the same URL.
+func (d *WebhookDispatcher) Register(hook Webhook) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.hooks[hook.URL] = hook
+}
+
+// Unregister removes the webhook with url. Queued deliveries to it are still
+// made.
+func (d *WebhookDispatcher) Unregister(url string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	delete(d.hooks, url)
+}
+
+// Publish implements Publisher. It queues a delivery of event to every
+// webhook subscribed to its list and returns without waiting for them. It
+// fails with ErrDispatcherClosed after Close.
+func (d *WebhookDispatcher) Publish(ctx context.Context, event order.OrderChangedEvent) error {
+	payload, err := d.config.encoder.Encode(event)
+	if err != nil {
+		return err
+	}
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	if d.closed {
+		return ErrDispatcherClosed
+	}
+	for _, hook := range d.hooks {
+		if !hook.wants(event.ListID) {
+			continue
+		}
+		select {
+		case d.queue <- delivery{hook: hook, event: event, payload: payload}:
+		default:
+			d.deadLetter(DeadLetter{Webhook: hook, Event: event, Err: ErrQueueFull})
+		}
+	}
+	return nil
+}
+
+// Close stops accepting events and waits until the queued deliveries are
+// made. If ctx is done first, the deliveries still running are cancelled and
+// dead-lettered and Close returns the error of ctx.
+func (d *WebhookDispatcher) Close(ctx context.Context) error {
+	d.mu.Lock()
+	if !d.closed {
+		d.closed = true
+		close(d.queue)
+	}
+	d.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		d.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		d.cancel()
+		return nil
+	case <-ctx.Done():
+		d.cancel()
+		<-done
+		return ctx.Err()
+	}
+}
+
+func (d *WebhookDispatcher) work() {
+	defer d.wg.Done()
+	for del := range d.queue {
+		d.deliver(del)
+	}
+}
+
+// deliver makes a delivery, retrying failures that may be temporary.
+func (d *WebhookDispatcher) deliver(del delivery) {
+	delay := d.config.retry.Backoff
+	for attempt := 1; ; attempt++ {
+		err := d.post(del)
+		if err == nil {
+			return
+		}
+		var status *statusError
+		permanent := errors.As(err, &status) && !status.retryable()
+		if permanent || attempt >= d.config.retry.Attempts || d.ctx.Err() != nil {
+			d.deadLetter(DeadLetter{Webhook: del.hook, Event: del.event, Attempts: attempt, Err: err})
+			return
+		}
+		timer := time.NewTimer(jittered(delay, d.config.retry.Jitter))
+		select {
+		case <-timer.C:
+		case <-d.ctx.Done():
+			timer.Stop()
+			d.deadLetter(DeadLetter{Webhook: del.hook, Event: del.event, Attempts: attempt, Err: d.ctx.Err()})
+			return
+		}
+		delay *= 2
+		if d.config.retry.MaxBackoff > 0 {
+			delay = min(delay, d.config.retry.MaxBackoff)
+		}
+	}
+}
+
+func jittered(delay time.Duration, jitter float64) time.Duration {
+	jitter = min(max(jitter, 0), 1)
+	return delay + time.Duration(float64(delay)*jitter*(2*rand.Float64()-1))
+}
+
+func (d *WebhookDispatcher) post(del delivery) error {
+	req, err := http.NewRequestWithContext(d.ctx, http.MethodPost, del.hook.URL, bytes.NewReader(del.payload))
+	if err != nil {
+		return err
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", d.config.encoder.ContentType())
+	req.Header.Set(EventIDHeader, del.event.OperationID)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(del.hook.Secret, timestamp, del.payload))
+	resp, err := d.config.client.Do(req)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return &statusError{code: resp.StatusCode}
+	}
+	return nil
+}
+
+func (d *WebhookDispatcher) deadLetter(letter DeadLetter) {
+	if d.config.deadLetter != nil {
+		d.config.deadLetter(letter)
+	}
+}
+
+// statusError is a delivery that got a response other than 2xx.
+type statusError struct {
+	code int
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("webhook responded %d %s", e.code, http.StatusText(e.code))
+}
+
+func (e *statusError) retryable() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= 500
+}
+
+// Sign returns the SignatureHeader value of a webhook request with body sent
+// at timestamp, in Unix seconds.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature checks the signature of a webhook request received with
+// header and body, for receivers written in Go. It fails with
+// ErrInvalidSignature if the signature does not match or, for a positive
+// tolerance, if the timestamp is further than tolerance from now, which
+// rejects replayed requests.
+func VerifySignature(secret []byte, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp := header.Get(TimestampHeader)
+	if tolerance > 0 {
+		unix, err := strconv.ParseInt(timestamp, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%w: bad timestamp %q", ErrInvalidSignature, timestamp)
+		}
+		if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+			return fmt.Errorf("%w: timestamp outside tolerance", ErrInvalidSignature)
+		}
+	}
+	if !hmac.Equal([]byte(header.Get(SignatureHeader)), []byte(Sign(secret, timestamp, body))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
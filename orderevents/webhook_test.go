@@ -0,0 +1,174 @@
+package orderevents_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/orderevents"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var fastRetry = order.RetryPolicy{Attempts: 3, Backoff: time.Millisecond}
+
+type deadLetters struct {
+	mu      sync.Mutex
+	letters []orderevents.DeadLetter
+}
+
+func (d *deadLetters) add(letter orderevents.DeadLetter) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.letters = append(d.letters, letter)
+}
+
+func (d *deadLetters) all() []orderevents.DeadLetter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]orderevents.DeadLetter(nil), d.letters...)
+}
+
+func TestWebhookDispatcher(t *testing.T) {
+	secret := []byte("secret")
+	var got []order.OrderChangedEvent
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := orderevents.VerifySignature(secret, r.Header, body, time.Minute); err != nil {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var event order.OrderChangedEvent
+		if err := json.Unmarshal(body, &event); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, event.OperationID, r.Header.Get(orderevents.EventIDHeader))
+		mu.Lock()
+		got = append(got, event)
+		mu.Unlock()
+	}))
+	defer srv.Close()
+
+	var dead deadLetters
+	d := orderevents.NewWebhookDispatcher(orderevents.WithWebhookRetry(fastRetry), orderevents.WithDeadLetter(dead.add))
+	d.Register(orderevents.Webhook{URL: srv.URL, Secret: secret, ListIDs: []string{"list"}})
+
+	ctx := context.Background()
+	require.NoError(t, d.Publish(ctx, testEvent()))
+	other := testEvent()
+	other.ListID = "other"
+	require.NoError(t, d.Publish(ctx, other))
+	require.NoError(t, d.Close(ctx))
+
+	require.Len(t, got, 1)
+	assert.Equal(t, testEvent(), got[0])
+	assert.Empty(t, dead.all())
+	assert.ErrorIs(t, d.Publish(ctx, testEvent()), orderevents.ErrDispatcherClosed)
+}
+
+func TestWebhookDispatcherRetries(t *testing.T) {
+	var calls atomic.Int32
+	flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer flaky.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer down.Close()
+	var rejected atomic.Int32
+	rejecting := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rejected.Add(1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer rejecting.Close()
+
+	var dead deadLetters
+	d := orderevents.NewWebhookDispatcher(orderevents.WithWebhookRetry(fastRetry), orderevents.WithDeadLetter(dead.add))
+	d.Register(orderevents.Webhook{URL: flaky.URL})
+	d.Register(orderevents.Webhook{URL: down.URL})
+	d.Register(orderevents.Webhook{URL: rejecting.URL})
+	require.NoError(t, d.Publish(context.Background(), testEvent()))
+	require.NoError(t, d.Close(context.Background()))
+
+	assert.Equal(t, int32(3), calls.Load())
+	// A 4xx other than 429 is not retried.
+	assert.Equal(t, int32(1), rejected.Load())
+	letters := dead.all()
+	require.Len(t, letters, 2)
+	attempts := map[string]int{}
+	for _, letter := range letters {
+		attempts[letter.Webhook.URL] = letter.Attempts
+		assert.Equal(t, testEvent(), letter.Event)
+		assert.Error(t, letter.Err)
+	}
+	assert.Equal(t, map[string]int{down.URL: 3, rejecting.URL: 1}, attempts)
+}
+
+func TestWebhookDispatcherCloseCancelsDeliveries(t *testing.T) {
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+	}))
+	defer srv.Close()
+	defer close(release)
+
+	var dead deadLetters
+	d := orderevents.NewWebhookDispatcher(orderevents.WithDeadLetter(dead.add), orderevents.WithWebhookQueue(1, 1))
+	d.Register(orderevents.Webhook{URL: srv.URL})
+	ctx := context.Background()
+	require.NoError(t, d.Publish(ctx, testEvent()))
+	<-started
+	require.NoError(t, d.Publish(ctx, testEvent()))
+	require.NoError(t, d.Publish(ctx, testEvent()))
+
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, d.Close(ctx), context.DeadlineExceeded)
+	// One delivery was running, one queued and one did not fit.
+	letters := dead.all()
+	require.Len(t, letters, 3)
+	assert.ErrorIs(t, letters[0].Err, orderevents.ErrQueueFull)
+}
+
+func TestWithWebhookQueueClampsSizes(t *testing.T) {
+	d := orderevents.NewWebhookDispatcher(orderevents.WithWebhookQueue(-1, 0))
+	assert.NoError(t, d.Close(context.Background()))
+}
+
+func TestVerifySignature(t *testing.T) {
+	secret := []byte("secret")
+	body := []byte(`{"list_id":"list"}`)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	header := http.Header{}
+	header.Set(orderevents.TimestampHeader, timestamp)
+	header.Set(orderevents.SignatureHeader, orderevents.Sign(secret, timestamp, body))
+
+	assert.NoError(t, orderevents.VerifySignature(secret, header, body, time.Minute))
+	assert.ErrorIs(t, orderevents.VerifySignature([]byte("other"), header, body, 0), orderevents.ErrInvalidSignature)
+	assert.ErrorIs(t, orderevents.VerifySignature(secret, header, []byte("{}"), 0), orderevents.ErrInvalidSignature)
+
+	old := strconv.FormatInt(time.Now().Add(-time.Hour).Unix(), 10)
+	header.Set(orderevents.TimestampHeader, old)
+	header.Set(orderevents.SignatureHeader, orderevents.Sign(secret, old, body))
+	assert.NoError(t, orderevents.VerifySignature(secret, header, body, 0))
+	assert.ErrorIs(t, orderevents.VerifySignature(secret, header, body, time.Minute), orderevents.ErrInvalidSignature)
+}
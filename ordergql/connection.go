@@ -0,0 +1,132 @@
+package ordergql
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/yacobolo/order"
+)
+
+// ErrInvalidCursor is returned for a cursor that was not produced by Cursor or
+// that refers to an item that is no longer in the list.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+const cursorPrefix = "item:"
+
+// ConnectionArgs holds the Relay pagination arguments of a connection field.
+type ConnectionArgs struct {
+	First  *int    `json:"first,omitempty"`
+	After  *string `json:"after,omitempty"`
+	Last   *int    `json:"last,omitempty"`
+	Before *string `json:"before,omitempty"`
+}
+
+// Edge is an item together with its cursor.
+type Edge[T any] struct {
+	Node   T      `json:"node"`
+	Cursor string `json:"cursor"`
+}
+
+// PageInfo describes the page returned in a Connection.
+type PageInfo struct {
+	HasNextPage     bool    `json:"hasNextPage"`
+	HasPreviousPage bool    `json:"hasPreviousPage"`
+	StartCursor     *string `json:"startCursor"`
+	EndCursor       *string `json:"endCursor"`
+}
+
+// Connection is a page of an ordered list.
+type Connection[T any] struct {
+	Edges      []Edge[T] `json:"edges"`
+	PageInfo   PageInfo  `json:"pageInfo"`
+	TotalCount int       `json:"totalCount"`
+}
+
+// Cursor returns the cursor of the item with the given ID. Cursors encode the
+// item ID rather than its position, so a cursor keeps pointing at the same item
+// when the list is reordered between requests.
+func Cursor(itemID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(cursorPrefix + itemID))
+}
+
+// ParseCursor returns the item ID encoded in cursor.
+func ParseCursor(cursor string) (string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || !strings.HasPrefix(string(raw), cursorPrefix) {
+		return "", fmt.Errorf("%w: %q", ErrInvalidCursor, cursor)
+	}
+	return strings.TrimPrefix(string(raw), cursorPrefix), nil
+}
+
+// Paginate returns the page of items selected by args. items must be sorted by
+// position. After and Before narrow the list to the items strictly between
+// the referenced items, then First keeps the leading and Last the trailing
+// items of what remains. A negative First or Last fails with
+// order.ErrInvalidPosition.
+func Paginate[T order.Orderable](items []T, args ConnectionArgs) (Connection[T], error) {
+	start, end := 0, len(items)
+	if args.After != nil {
+		i, err := cursorIndex(items, *args.After)
+		if err != nil {
+			return Connection[T]{}, err
+		}
+		start = i + 1
+	}
+	if args.Before != nil {
+		i, err := cursorIndex(items, *args.Before)
+		if err != nil {
+			return Connection[T]{}, err
+		}
+		end = i
+	}
+	if end < start {
+		end = start
+	}
+
+	conn := Connection[T]{TotalCount: len(items)}
+	conn.PageInfo.HasPreviousPage = start > 0
+	conn.PageInfo.HasNextPage = end < len(items)
+	if args.First != nil {
+		if *args.First < 0 {
+			return Connection[T]{}, fmt.Errorf("Paginate: first: %w", order.ErrInvalidPosition)
+		}
+		if end-start > *args.First {
+			end = start + *args.First
+			conn.PageInfo.HasNextPage = true
+		}
+	}
+	if args.Last != nil {
+		if *args.Last < 0 {
+			return Connection[T]{}, fmt.Errorf("Paginate: last: %w", order.ErrInvalidPosition)
+		}
+		if end-start > *args.Last {
+			start = end - *args.Last
+			conn.PageInfo.HasPreviousPage = true
+		}
+	}
+
+	conn.Edges = make([]Edge[T], 0, end-start)
+	for _, item := range items[start:end] {
+		conn.Edges = append(conn.Edges, Edge[T]{Node: item, Cursor: Cursor(item.GetID())})
+	}
+	if len(conn.Edges) > 0 {
+		conn.PageInfo.StartCursor = &conn.Edges[0].Cursor
+		conn.PageInfo.EndCursor = &conn.Edges[len(conn.Edges)-1].Cursor
+	}
+	return conn, nil
+}
+
+func cursorIndex[T order.Orderable](items []T, cursor string) (int, error) {
+	id, err := ParseCursor(cursor)
+	if err != nil {
+		return -1, err
+	}
+	for i, item := range items {
+		if item.GetID() == id {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%w: item %s not in list", ErrInvalidCursor, id)
+}
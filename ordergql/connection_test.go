@@ -0,0 +1,78 @@
+package ordergql_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordergql"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func nodeIDs(conn ordergql.Connection[*ordertest.Item]) []string {
+	ids := make([]string, len(conn.Edges))
+	for i, edge := range conn.Edges {
+		ids[i] = edge.Node.GetID()
+	}
+	return ids
+}
+
+func TestCursorRoundTrip(t *testing.T) {
+	id, err := ordergql.ParseCursor(ordergql.Cursor("item-1"))
+	require.NoError(t, err)
+	assert.Equal(t, "item-1", id)
+
+	_, err = ordergql.ParseCursor("not a cursor")
+	assert.ErrorIs(t, err, ordergql.ErrInvalidCursor)
+}
+
+func TestPaginate(t *testing.T) {
+	items := ordertest.Items(5)
+
+	conn, err := ordergql.Paginate(items, ordergql.ConnectionArgs{First: ptr(2)})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item-1", "item-2"}, nodeIDs(conn))
+	assert.True(t, conn.PageInfo.HasNextPage)
+	assert.False(t, conn.PageInfo.HasPreviousPage)
+	assert.Equal(t, 5, conn.TotalCount)
+
+	conn, err = ordergql.Paginate(items, ordergql.ConnectionArgs{First: ptr(2), After: conn.PageInfo.EndCursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item-3", "item-4"}, nodeIDs(conn))
+	assert.True(t, conn.PageInfo.HasNextPage)
+	assert.True(t, conn.PageInfo.HasPreviousPage)
+
+	conn, err = ordergql.Paginate(items, ordergql.ConnectionArgs{Last: ptr(2), Before: ptr(ordergql.Cursor("item-5"))})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item-3", "item-4"}, nodeIDs(conn))
+	assert.True(t, conn.PageInfo.HasNextPage)
+	assert.True(t, conn.PageInfo.HasPreviousPage)
+
+	conn, err = ordergql.Paginate(items, ordergql.ConnectionArgs{After: ptr(ordergql.Cursor("item-5"))})
+	require.NoError(t, err)
+	assert.Empty(t, conn.Edges)
+	assert.Nil(t, conn.PageInfo.EndCursor)
+}
+
+func TestPaginateFollowsReorderedItems(t *testing.T) {
+	items := ordertest.Items(4)
+	conn, err := ordergql.Paginate(items, ordergql.ConnectionArgs{First: ptr(2)})
+	require.NoError(t, err)
+
+	_, err = order.NewOrderManager[*ordertest.Item]().Top(items, "item-4")
+	require.NoError(t, err)
+
+	conn, err = ordergql.Paginate(items, ordergql.ConnectionArgs{First: ptr(2), After: conn.PageInfo.EndCursor})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"item-3"}, nodeIDs(conn))
+}
+
+func TestPaginateErrors(t *testing.T) {
+	items := ordertest.Items(2)
+	_, err := ordergql.Paginate(items, ordergql.ConnectionArgs{After: ptr(ordergql.Cursor("missing"))})
+	assert.ErrorIs(t, err, ordergql.ErrInvalidCursor)
+	_, err = ordergql.Paginate(items, ordergql.ConnectionArgs{First: ptr(-1)})
+	assert.ErrorIs(t, err, order.ErrInvalidPosition)
+}
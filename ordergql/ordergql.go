@@ -0,0 +1,141 @@
+// Package ordergql provides helpers for exposing package order through a
+// GraphQL API, typically one generated with gqlgen.
+//
+// MoveItemInput and MoveItemPayload mirror the types in Schema and can be
+// bound to them in gqlgen.yml. Resolver implements the moveItem mutation on
+// top of an order.PersistentManager, and Paginate builds Relay-style
+// connections whose cursors stay valid while items are reordered.
+package ordergql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/yacobolo/order"
+)
+
+// Schema declares the GraphQL types provided by this package. Include it in
+// the server schema and add the moveItem field to the Mutation type:
+//
+//	extend type Mutation {
+//	    moveItem(input: MoveItemInput!): MoveItemPayload!
+//	}
+const Schema = `
+input MoveItemInput {
+  listId: ID!
+  itemId: ID!
+  "Place the item directly above this item."
+  before: ID
+  "Place the item directly below this item."
+  after: ID
+  "Move the item to this 1-based position."
+  position: Int
+}
+
+type PositionChange {
+  itemId: ID!
+  oldPosition: Int!
+  newPosition: Int!
+}
+
+type MoveItemPayload {
+  itemId: ID!
+  position: Int!
+  changed: Boolean!
+  version: Int!
+  changes: [PositionChange!]!
+}
+
+type PageInfo {
+  hasNextPage: Boolean!
+  hasPreviousPage: Boolean!
+  startCursor: String
+  endCursor: String
+}
+`
+
+// ErrInvalidInput is returned for a MoveItemInput that does not set exactly one
+// of Before, After and Position.
+var ErrInvalidInput = errors.New("exactly one of before, after and position must be set")
+
+// MoveItemInput is the input of the moveItem mutation.
+type MoveItemInput struct {
+	ListID   string  `json:"listId"`
+	ItemID   string  `json:"itemId"`
+	Before   *string `json:"before,omitempty"`
+	After    *string `json:"after,omitempty"`
+	Position *int    `json:"position,omitempty"`
+}
+
+// Move converts the input to an order.Move: Before becomes MoveAbove, After
+// becomes MoveBelow and Position becomes MoveTo.
+func (in MoveItemInput) Move() (order.Move[string], error) {
+	set := 0
+	for _, ok := range []bool{in.Before != nil, in.After != nil, in.Position != nil} {
+		if ok {
+			set++
+		}
+	}
+	if set != 1 {
+		return order.Move[string]{}, ErrInvalidInput
+	}
+	switch {
+	case in.Before != nil:
+		return order.Move[string]{Kind: order.MoveAbove, ItemID: in.ItemID, TargetID: *in.Before}, nil
+	case in.After != nil:
+		return order.Move[string]{Kind: order.MoveBelow, ItemID: in.ItemID, TargetID: *in.After}, nil
+	default:
+		return order.Move[string]{Kind: order.MoveTo, ItemID: in.ItemID, Position: *in.Position}, nil
+	}
+}
+
+// PositionChange is the GraphQL form of order.PositionChange.
+type PositionChange struct {
+	ItemID      string `json:"itemId"`
+	OldPosition int    `json:"oldPosition"`
+	NewPosition int    `json:"newPosition"`
+}
+
+// MoveItemPayload is the result of the moveItem mutation.
+type MoveItemPayload struct {
+	ItemID   string           `json:"itemId"`
+	Position int              `json:"position"`
+	Changed  bool             `json:"changed"`
+	Version  int64            `json:"version"`
+	Changes  []PositionChange `json:"changes"`
+}
+
+// Resolver resolves reorder mutations against lists kept in an order.Store.
+type Resolver[T order.Orderable] struct {
+	manager *order.PersistentManager[T]
+}
+
+// NewResolver creates a Resolver that moves items with manager.
+func NewResolver[T order.Orderable](manager *order.PersistentManager[T]) *Resolver[T] {
+	return &Resolver[T]{manager: manager}
+}
+
+// MoveItem resolves the moveItem mutation. A gqlgen mutation resolver can
+// delegate to it directly.
+func (r *Resolver[T]) MoveItem(ctx context.Context, input MoveItemInput) (*MoveItemPayload, error) {
+	m, err := input.Move()
+	if err != nil {
+		return nil, fmt.Errorf("moveItem: %w", err)
+	}
+	result, err := r.manager.Apply(ctx, input.ListID, m)
+	if err != nil {
+		return nil, err
+	}
+	payload := &MoveItemPayload{
+		ItemID:   input.ItemID,
+		Position: result.NewPosition,
+		Changed:  result.Changed,
+		Version:  result.Version,
+		Changes:  make([]PositionChange, len(result.Changes)),
+	}
+	for i, change := range result.Changes {
+		payload.Changes[i] = PositionChange(change)
+	}
+	return payload, nil
+}
@@ -0,0 +1,61 @@
+package ordergql_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordergql"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestMoveItemInputMove(t *testing.T) {
+	m, err := ordergql.MoveItemInput{ItemID: "a", Before: ptr("b")}.Move()
+	require.NoError(t, err)
+	assert.Equal(t, order.Move[string]{Kind: order.MoveAbove, ItemID: "a", TargetID: "b"}, m)
+
+	m, err = ordergql.MoveItemInput{ItemID: "a", After: ptr("b")}.Move()
+	require.NoError(t, err)
+	assert.Equal(t, order.Move[string]{Kind: order.MoveBelow, ItemID: "a", TargetID: "b"}, m)
+
+	m, err = ordergql.MoveItemInput{ItemID: "a", Position: ptr(2)}.Move()
+	require.NoError(t, err)
+	assert.Equal(t, order.Move[string]{Kind: order.MoveTo, ItemID: "a", Position: 2}, m)
+
+	_, err = ordergql.MoveItemInput{ItemID: "a"}.Move()
+	assert.ErrorIs(t, err, ordergql.ErrInvalidInput)
+	_, err = ordergql.MoveItemInput{ItemID: "a", Before: ptr("b"), Position: ptr(1)}.Move()
+	assert.ErrorIs(t, err, ordergql.ErrInvalidInput)
+}
+
+func TestResolverMoveItem(t *testing.T) {
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	resolver := ordergql.NewResolver(order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]()))
+
+	payload, err := resolver.MoveItem(context.Background(), ordergql.MoveItemInput{ListID: "list", ItemID: "item-3", Before: ptr("item-1")})
+	require.NoError(t, err)
+	assert.Equal(t, &ordergql.MoveItemPayload{
+		ItemID:   "item-3",
+		Position: 1,
+		Changed:  true,
+		Version:  2,
+		Changes: []ordergql.PositionChange{
+			{ItemID: "item-3", OldPosition: 3, NewPosition: 1},
+			{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+			{ItemID: "item-2", OldPosition: 2, NewPosition: 3},
+		},
+	}, payload)
+
+	_, err = resolver.MoveItem(context.Background(), ordergql.MoveItemInput{ListID: "list", ItemID: "item-3"})
+	assert.ErrorIs(t, err, ordergql.ErrInvalidInput)
+	_, err = resolver.MoveItem(context.Background(), ordergql.MoveItemInput{ListID: "list", ItemID: "missing", Position: ptr(1)})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
@@ -0,0 +1,251 @@
+package orderhttp
+
+import (
+	"net/http"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Document is an OpenAPI 3 document. Only the parts used by this package are
+// modelled.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info is the info object of a Document.
+type Info struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+}
+
+// PathItem maps HTTP methods, in lower case, to operations.
+type PathItem map[string]*Operation
+
+// Operation describes one endpoint.
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter describes a path or header parameter.
+type Parameter struct {
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *Schema `json:"schema"`
+}
+
+// RequestBody describes a JSON request body.
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response describes a JSON response.
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body.
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components holds the named schemas referenced from the paths.
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Schema is a JSON schema as used by OpenAPI 3.0.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Enum                 []string           `json:"enum,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+}
+
+// OpenAPI returns the document describing the endpoints of a Handler[T]. The
+// schemas are derived with reflection from T, MoveRequest, MoveResponse,
+// ItemsResponse, HealthResponse and ErrorResponse, following their encoding/json tags.
+func OpenAPI[T any]() *Document {
+	g := &generator{schemas: make(map[string]*Schema)}
+	listID := []Parameter{{Name: "listID", In: "path", Required: true, Schema: &Schema{Type: "string"}}}
+	errorResponses := func(responses map[string]Response, statuses ...int) map[string]Response {
+		ref := g.schema(reflect.TypeFor[ErrorResponse]())
+		for _, status := range statuses {
+			responses[strconv.Itoa(status)] = Response{Description: http.StatusText(status), Content: jsonContent(ref)}
+		}
+		return responses
+	}
+
+	return &Document{
+		OpenAPI: "3.0.3",
+		Info:    Info{Title: "order", Version: "1"},
+		Paths: map[string]PathItem{
+			"/lists/{listID}/items": {
+				"get": {
+					OperationID: "listItems",
+					Summary:     "Return the items of a list sorted by position.",
+					Parameters:  listID,
+					Responses: errorResponses(map[string]Response{
+						"200": {Description: "The items of the list.", Content: jsonContent(g.named("ItemsResponse", reflect.TypeFor[ItemsResponse[T]]()))},
+						"304": {Description: "The order has not changed since the ETag given in If-None-Match."},
+					}, http.StatusNotFound, http.StatusInternalServerError),
+				},
+			},
+			"/lists/{listID}/moves": {
+				"post": {
+					OperationID: "moveItem",
+					Summary:     "Move an item within a list.",
+					Parameters:  append(slices.Clip(listID), Parameter{Name: "Idempotency-Key", In: "header", Schema: &Schema{Type: "string"}}),
+					RequestBody: &RequestBody{Required: true, Content: jsonContent(g.schema(reflect.TypeFor[MoveRequest]()))},
+					Responses: errorResponses(map[string]Response{
+						"200": {Description: "The result of the move.", Content: jsonContent(g.schema(reflect.TypeFor[MoveResponse]()))},
+					}, http.StatusBadRequest, http.StatusNotFound, http.StatusConflict, http.StatusPreconditionFailed, http.StatusUnprocessableEntity, http.StatusInternalServerError),
+				},
+			},
+			"/healthz": {
+				"get": {
+					OperationID: "health",
+					Summary:     "Report whether the service and its store are healthy.",
+					Responses: errorResponses(map[string]Response{
+						"200": {Description: "The service is healthy.", Content: jsonContent(g.schema(reflect.TypeFor[HealthResponse]()))},
+					}, http.StatusServiceUnavailable),
+				},
+			},
+		},
+		Components: Components{Schemas: g.schemas},
+	}
+}
+
+func jsonContent(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
+
+type generator struct {
+	schemas map[string]*Schema
+}
+
+// schema returns the schema of t. Named struct types are added to the
+// components and referenced.
+func (g *generator) schema(t reflect.Type) *Schema {
+	return g.named(t.Name(), t)
+}
+
+func (g *generator) named(name string, t reflect.Type) *Schema {
+	switch {
+	case t == reflect.TypeFor[Kind]():
+		enum := make([]string, len(kinds))
+		for i, k := range kinds {
+			enum[i] = string(k.name)
+		}
+		return &Schema{Type: "string", Enum: enum}
+	case t == reflect.TypeFor[time.Time]():
+		return &Schema{Type: "string", Format: "date-time"}
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := g.schema(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	case reflect.String:
+		return &Schema{Type: "string"}
+	case reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return &Schema{Type: "integer", Format: "int32"}
+	case reflect.Int64, reflect.Uint64:
+		return &Schema{Type: "integer", Format: "int64"}
+	case reflect.Float32:
+		return &Schema{Type: "number", Format: "float"}
+	case reflect.Float64:
+		return &Schema{Type: "number", Format: "double"}
+	case reflect.Slice, reflect.Array:
+		return &Schema{Type: "array", Items: g.schema(t.Elem())}
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: g.schema(t.Elem())}
+	case reflect.Struct:
+		if name == "" {
+			return g.object(t)
+		}
+		if _, ok := g.schemas[name]; !ok {
+			// Reserve the name first so recursive types terminate.
+			g.schemas[name] = &Schema{}
+			*g.schemas[name] = *g.object(t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+func (g *generator) object(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: make(map[string]*Schema)}
+	for field := range fields(t) {
+		name, omitempty := field.Name, false
+		if tag, ok := field.Tag.Lookup("json"); ok {
+			tagName, opts, _ := strings.Cut(tag, ",")
+			if tagName != "" {
+				name = tagName
+			}
+			omitempty = strings.Contains(","+opts+",", ",omitempty,")
+		}
+		s.Properties[name] = g.schema(field.Type)
+		if !omitempty && field.Type.Kind() != reflect.Pointer {
+			s.Required = append(s.Required, name)
+		}
+	}
+	return s
+}
+
+// fields yields the exported fields of t that encoding/json encodes, with the
+// fields of untagged embedded structs promoted.
+func fields(t reflect.Type) func(func(reflect.StructField) bool) {
+	return func(yield func(reflect.StructField) bool) {
+		for i := range t.NumField() {
+			field := t.Field(i)
+			tag := field.Tag.Get("json")
+			if tag == "-" {
+				continue
+			}
+			if field.Anonymous && tag == "" {
+				ft := field.Type
+				if ft.Kind() == reflect.Pointer {
+					ft = ft.Elem()
+				}
+				if ft.Kind() == reflect.Struct {
+					for f := range fields(ft) {
+						if !yield(f) {
+							return
+						}
+					}
+					continue
+				}
+			}
+			if !field.IsExported() {
+				continue
+			}
+			if !yield(field) {
+				return
+			}
+		}
+	}
+}
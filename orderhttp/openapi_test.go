@@ -0,0 +1,43 @@
+package orderhttp_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/yacobolo/order/orderhttp"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAPI(t *testing.T) {
+	doc := orderhttp.OpenAPI[*ordertest.Item]()
+	assert.Equal(t, "3.0.3", doc.OpenAPI)
+
+	move := doc.Paths["/lists/{listID}/moves"]["post"]
+	require.NotNil(t, move)
+	assert.Equal(t, "#/components/schemas/MoveRequest", move.RequestBody.Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/MoveResponse", move.Responses["200"].Content["application/json"].Schema.Ref)
+	assert.Equal(t, "#/components/schemas/ErrorResponse", move.Responses["409"].Content["application/json"].Schema.Ref)
+
+	schemas := doc.Components.Schemas
+	request := schemas["MoveRequest"]
+	assert.Equal(t, []string{"kind", "itemId"}, request.Required)
+	assert.Equal(t, []string{"up", "down", "to", "top", "bottom", "above", "below"}, request.Properties["kind"].Enum)
+	assert.Equal(t, "integer", request.Properties["position"].Type)
+
+	items := schemas["ItemsResponse"]
+	assert.Equal(t, "int64", items.Properties["version"].Format)
+	assert.Equal(t, "#/components/schemas/Item", items.Properties["items"].Items.Ref)
+	assert.Contains(t, schemas["Item"].Properties, "Position")
+}
+
+func TestHandlerServesOpenAPI(t *testing.T) {
+	server := newServer(t)
+	resp, err := http.Get(server.URL + "/openapi.json")
+	require.NoError(t, err)
+	doc := decode[orderhttp.Document](t, resp)
+	assert.Contains(t, doc.Paths, "/lists/{listID}/items")
+	assert.Contains(t, doc.Components.Schemas, "MoveResponse")
+}
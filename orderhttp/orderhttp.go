@@ -0,0 +1,246 @@
+// Package orderhttp serves lists kept in an order.Store over HTTP and
+// describes the endpoints with an OpenAPI 3 document generated from the same
+// Go types that the handlers encode and decode.
+//
+// The handler serves:
+//
+//	GET  /lists/{listID}/items  the items of a list, sorted by position, with
+//	                            an ETag computed by order.Hash
+//	POST /lists/{listID}/moves  apply a MoveRequest and return a MoveResponse;
+//	                            with an Idempotency-Key header, a retried
+//	                            request returns the first response
+//	GET  /healthz               a HealthResponse if the manager and its store
+//	                            are healthy, see order.PersistentManager.Health
+//	GET  /openapi.json          the OpenAPI document
+//
+// Errors are returned as an ErrorResponse with a status code derived from the
+// sentinel error of package order.
+package orderhttp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/yacobolo/order"
+)
+
+// Kind is the name of a move verb in a MoveRequest.
+type Kind string
+
+const (
+	KindUp     Kind = "up"
+	KindDown   Kind = "down"
+	KindTo     Kind = "to"
+	KindTop    Kind = "top"
+	KindBottom Kind = "bottom"
+	KindAbove  Kind = "above"
+	KindBelow  Kind = "below"
+)
+
+var kinds = []struct {
+	name Kind
+	kind order.MoveKind
+}{
+	{KindUp, order.MoveUp},
+	{KindDown, order.MoveDown},
+	{KindTo, order.MoveTo},
+	{KindTop, order.MoveTop},
+	{KindBottom, order.MoveBottom},
+	{KindAbove, order.MoveAbove},
+	{KindBelow, order.MoveBelow},
+}
+
+// MoveRequest is the body of POST /lists/{listID}/moves. Position is required
+// by "to", TargetID by "above" and "below". The Expected fields are an
+// optional order.Precondition: the neighbors or position of the item as the
+// client rendered it. An expectedPosition of 0 is checked; leave it out to
+// not check the position.
+type MoveRequest struct {
+	Kind             Kind   `json:"kind"`
+	ItemID           string `json:"itemId"`
+	Position         int    `json:"position,omitempty"`
+	TargetID         string `json:"targetId,omitempty"`
+	ExpectedPrevID   string `json:"expectedPrevId,omitempty"`
+	ExpectedNextID   string `json:"expectedNextId,omitempty"`
+	ExpectedPosition *int   `json:"expectedPosition,omitempty"`
+}
+
+// Move converts the request to an order.Move.
+func (r MoveRequest) Move() (order.Move[string], error) {
+	for _, k := range kinds {
+		if k.name == r.Kind {
+			return order.Move[string]{
+				Kind:     k.kind,
+				ItemID:   r.ItemID,
+				Position: r.Position,
+				TargetID: r.TargetID,
+				Expect:   order.Precondition[string]{PrevID: r.ExpectedPrevID, NextID: r.ExpectedNextID, Position: r.ExpectedPosition},
+			}, nil
+		}
+	}
+	return order.Move[string]{}, fmt.Errorf("unknown move kind %q", r.Kind)
+}
+
+// Change is the position of an item before and after a move.
+type Change struct {
+	ItemID      string `json:"itemId"`
+	OldPosition int    `json:"oldPosition"`
+	NewPosition int    `json:"newPosition"`
+}
+
+// MoveResponse is the result of a move.
+type MoveResponse struct {
+	// OperationID identifies the move, see order.PersistedResult.
+	OperationID string   `json:"operationId"`
+	ItemID      string   `json:"itemId"`
+	Position    int      `json:"position"`
+	Changed     bool     `json:"changed"`
+	Version     int64    `json:"version"`
+	Changes     []Change `json:"changes"`
+}
+
+// ItemsResponse is the body of GET /lists/{listID}/items.
+type ItemsResponse[T any] struct {
+	Version int64 `json:"version"`
+	Items   []T   `json:"items"`
+}
+
+// HealthResponse is the body of GET /healthz when the service is healthy.
+type HealthResponse struct {
+	Status string `json:"status"`
+}
+
+// ErrorResponse is the body of every error response.
+type ErrorResponse struct {
+	// Code is a stable, machine-readable error code such as "item_not_found".
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// Error codes and the status codes they are returned with.
+var errorCodes = []struct {
+	err    error
+	code   string
+	status int
+}{
+	{order.ErrListNotFound, "list_not_found", http.StatusNotFound},
+	{order.ErrItemNotFound, "item_not_found", http.StatusNotFound},
+	{order.ErrInvalidPosition, "invalid_position", http.StatusUnprocessableEntity},
+	{order.ErrSameItem, "same_item", http.StatusUnprocessableEntity},
+	{order.ErrAlreadyAtTop, "already_at_top", http.StatusUnprocessableEntity},
+	{order.ErrAlreadyAtBottom, "already_at_bottom", http.StatusUnprocessableEntity},
+	{order.ErrItemLocked, "item_locked", http.StatusConflict},
+	{order.ErrVersionConflict, "version_conflict", http.StatusConflict},
+	{order.ErrInvalidOrder, "invalid_order", http.StatusConflict},
+	{order.ErrDuplicateID, "duplicate_id", http.StatusConflict},
+	{order.ErrUnhealthy, "unhealthy", http.StatusServiceUnavailable},
+	{order.ErrIdempotencyKeyReused, "idempotency_key_reused", http.StatusUnprocessableEntity},
+	{order.ErrPreconditionFailed, "precondition_failed", http.StatusPreconditionFailed},
+	{order.ErrNotPermitted, "not_permitted", http.StatusForbidden},
+}
+
+// Handler serves the lists of an order.PersistentManager.
+type Handler[T order.Orderable] struct {
+	manager *order.PersistentManager[T]
+	mux     *http.ServeMux
+	doc     []byte
+}
+
+// NewHandler creates a Handler for the lists managed by manager. The OpenAPI
+// document is generated once, from T and the request and response types.
+func NewHandler[T order.Orderable](manager *order.PersistentManager[T]) *Handler[T] {
+	h := &Handler[T]{manager: manager, mux: http.NewServeMux()}
+	doc, err := json.Marshal(OpenAPI[T]())
+	if err != nil {
+		panic(fmt.Sprintf("orderhttp: encoding OpenAPI document: %v", err))
+	}
+	h.doc = doc
+	h.mux.HandleFunc("GET /lists/{listID}/items", h.items)
+	h.mux.HandleFunc("POST /lists/{listID}/moves", h.move)
+	h.mux.HandleFunc("GET /healthz", h.health)
+	h.mux.HandleFunc("GET /openapi.json", h.openAPI)
+	return h
+}
+
+func (h *Handler[T]) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mux.ServeHTTP(w, r)
+}
+
+func (h *Handler[T]) items(w http.ResponseWriter, r *http.Request) {
+	items, version, err := h.manager.Load(r.Context(), r.PathValue("listID"))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	etag := `"` + order.Hash(items) + `"`
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	writeJSON(w, http.StatusOK, ItemsResponse[T]{Version: version, Items: items})
+}
+
+func (h *Handler[T]) move(w http.ResponseWriter, r *http.Request) {
+	var req MoveRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Code: "invalid_request", Message: err.Error()})
+		return
+	}
+	m, err := req.Move()
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Code: "invalid_request", Message: err.Error()})
+		return
+	}
+	result, err := h.manager.ApplyIdempotent(r.Context(), r.PathValue("listID"), r.Header.Get("Idempotency-Key"), m)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	if result.Replayed {
+		w.Header().Set("Idempotent-Replayed", "true")
+	}
+	resp := MoveResponse{
+		OperationID: result.OperationID,
+		ItemID:      req.ItemID,
+		Position:    result.NewPosition,
+		Changed:     result.Changed,
+		Version:     result.Version,
+		Changes:     make([]Change, len(result.Changes)),
+	}
+	for i, change := range result.Changes {
+		resp.Changes[i] = Change(change)
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler[T]) health(w http.ResponseWriter, r *http.Request) {
+	if err := h.manager.Health(r.Context()); err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, HealthResponse{Status: "ok"})
+}
+
+func (h *Handler[T]) openAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(h.doc)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	for _, c := range errorCodes {
+		if errors.Is(err, c.err) {
+			writeJSON(w, c.status, ErrorResponse{Code: c.code, Message: err.Error()})
+			return
+		}
+	}
+	writeJSON(w, http.StatusInternalServerError, ErrorResponse{Code: "internal", Message: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,155 @@
+package orderhttp_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/mockstore"
+	"github.com/yacobolo/order/orderhttp"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newServer(t *testing.T) *httptest.Server {
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	operations := 0
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithOperationIDs(func() string {
+		operations++
+		return fmt.Sprintf("op-%d", operations)
+	}))
+	server := httptest.NewServer(orderhttp.NewHandler(pm))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func decode[T any](t *testing.T, resp *http.Response) T {
+	t.Helper()
+	defer resp.Body.Close()
+	var v T
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&v))
+	return v
+}
+
+func TestHandlerMove(t *testing.T) {
+	server := newServer(t)
+
+	resp, err := http.Post(server.URL+"/lists/list/moves", "application/json", strings.NewReader(`{"kind":"above","itemId":"item-3","targetId":"item-1"}`))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, orderhttp.MoveResponse{
+		OperationID: "op-1",
+		ItemID:      "item-3",
+		Position:    1,
+		Changed:     true,
+		Version:     2,
+		Changes: []orderhttp.Change{
+			{ItemID: "item-3", OldPosition: 3, NewPosition: 1},
+			{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+			{ItemID: "item-2", OldPosition: 2, NewPosition: 3},
+		},
+	}, decode[orderhttp.MoveResponse](t, resp))
+
+	resp, err = http.Get(server.URL + "/lists/list/items")
+	require.NoError(t, err)
+	items := decode[orderhttp.ItemsResponse[*ordertest.Item]](t, resp)
+	assert.Equal(t, int64(2), items.Version)
+	ordertest.AssertOrder(t, items.Items, "item-3", "item-1", "item-2")
+	assert.Equal(t, `"`+order.Hash(items.Items)+`"`, resp.Header.Get("ETag"))
+
+	req, err := http.NewRequest(http.MethodGet, server.URL+"/lists/list/items", nil)
+	require.NoError(t, err)
+	req.Header.Set("If-None-Match", resp.Header.Get("ETag"))
+	resp, err = http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotModified, resp.StatusCode)
+}
+
+func TestHandlerMoveIdempotencyKey(t *testing.T) {
+	server := newServer(t)
+	post := func(body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, server.URL+"/lists/list/moves", strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Idempotency-Key", "retry-me")
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		return resp
+	}
+
+	resp := post(`{"kind":"down","itemId":"item-1"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Empty(t, resp.Header.Get("Idempotent-Replayed"))
+	first := decode[orderhttp.MoveResponse](t, resp)
+
+	resp = post(`{"kind":"down","itemId":"item-1"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "true", resp.Header.Get("Idempotent-Replayed"))
+	assert.Equal(t, first, decode[orderhttp.MoveResponse](t, resp))
+
+	resp = post(`{"kind":"top","itemId":"item-3"}`)
+	assert.Equal(t, http.StatusUnprocessableEntity, resp.StatusCode)
+	assert.Equal(t, "idempotency_key_reused", decode[orderhttp.ErrorResponse](t, resp).Code)
+
+	resp, err := http.Get(server.URL + "/lists/list/items")
+	require.NoError(t, err)
+	items := decode[orderhttp.ItemsResponse[*ordertest.Item]](t, resp)
+	assert.Equal(t, int64(2), items.Version)
+	ordertest.AssertOrder(t, items.Items, "item-2", "item-1", "item-3")
+}
+
+func TestHandlerErrors(t *testing.T) {
+	server := newServer(t)
+	tests := []struct {
+		name   string
+		path   string
+		body   string
+		status int
+		code   string
+	}{
+		{"malformed body", "/lists/list/moves", `{`, http.StatusBadRequest, "invalid_request"},
+		{"unknown kind", "/lists/list/moves", `{"kind":"sideways","itemId":"item-1"}`, http.StatusBadRequest, "invalid_request"},
+		{"unknown list", "/lists/missing/moves", `{"kind":"top","itemId":"item-1"}`, http.StatusNotFound, "list_not_found"},
+		{"unknown item", "/lists/list/moves", `{"kind":"top","itemId":"missing"}`, http.StatusNotFound, "item_not_found"},
+		{"invalid position", "/lists/list/moves", `{"kind":"to","itemId":"item-1","position":9}`, http.StatusUnprocessableEntity, "invalid_position"},
+		{"precondition failed", "/lists/list/moves", `{"kind":"top","itemId":"item-2","expectedPrevId":"item-3"}`, http.StatusPreconditionFailed, "precondition_failed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := http.Post(server.URL+tt.path, "application/json", strings.NewReader(tt.body))
+			require.NoError(t, err)
+			assert.Equal(t, tt.status, resp.StatusCode)
+			assert.Equal(t, tt.code, decode[orderhttp.ErrorResponse](t, resp).Code)
+		})
+	}
+}
+
+func TestHandlerHealth(t *testing.T) {
+	store := mockstore.New[*ordertest.Item](nil)
+	operations := 0
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithOperationIDs(func() string {
+		operations++
+		return fmt.Sprintf("op-%d", operations)
+	}))
+	server := httptest.NewServer(orderhttp.NewHandler(pm))
+	t.Cleanup(server.Close)
+
+	resp, err := http.Get(server.URL + "/healthz")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, orderhttp.HealthResponse{Status: "ok"}, decode[orderhttp.HealthResponse](t, resp))
+
+	store.FailNext(mockstore.Health, errors.New("connection refused"))
+	resp, err = http.Get(server.URL + "/healthz")
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+	assert.Equal(t, "unhealthy", decode[orderhttp.ErrorResponse](t, resp).Code)
+}
@@ -0,0 +1,155 @@
+// Package orderpb defines protobuf messages for order state and operations,
+// with conversions to and from the order package types, so services exchanging
+// orderings over gRPC or a message broker share one schema. Server implements
+// the OrderService, whose WatchList streams the changes to a list.
+package orderpb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative order.proto
+
+import (
+	"fmt"
+
+	"github.com/yacobolo/order"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// FromItems returns references to items in order.
+func FromItems[T order.Orderable](items []T) []*ItemRef {
+	refs := make([]*ItemRef, len(items))
+	for i, item := range items {
+		refs[i] = &ItemRef{Id: item.GetID(), Position: int64(item.GetPosition())}
+	}
+	return refs
+}
+
+// FromSnapshot converts a snapshot to its message.
+func FromSnapshot(snap order.OrderSnapshot) *OrderSnapshot {
+	msg := &OrderSnapshot{Ids: snap.IDs}
+	if !snap.TakenAt.IsZero() {
+		msg.TakenAt = timestamppb.New(snap.TakenAt)
+	}
+	return msg
+}
+
+// ToSnapshot converts a message to a snapshot.
+func ToSnapshot(msg *OrderSnapshot) order.OrderSnapshot {
+	snap := order.OrderSnapshot{IDs: msg.GetIds()}
+	if msg.GetTakenAt() != nil {
+		snap.TakenAt = msg.GetTakenAt().AsTime()
+	}
+	return snap
+}
+
+var (
+	toKind = map[order.MoveKind]MoveKind{
+		order.MoveUp:     MoveKind_MOVE_KIND_UP,
+		order.MoveDown:   MoveKind_MOVE_KIND_DOWN,
+		order.MoveTo:     MoveKind_MOVE_KIND_TO,
+		order.MoveTop:    MoveKind_MOVE_KIND_TOP,
+		order.MoveBottom: MoveKind_MOVE_KIND_BOTTOM,
+		order.MoveAbove:  MoveKind_MOVE_KIND_ABOVE,
+		order.MoveBelow:  MoveKind_MOVE_KIND_BELOW,
+	}
+	fromKind = make(map[MoveKind]order.MoveKind, len(toKind))
+)
+
+func init() {
+	for kind, msg := range toKind {
+		fromKind[msg] = kind
+	}
+}
+
+// FromMove converts a move to its command message. An unknown kind becomes
+// MOVE_KIND_UNSPECIFIED.
+func FromMove(m order.Move[string]) *MoveCommand {
+	return &MoveCommand{
+		Kind:     toKind[m.Kind],
+		ItemId:   m.ItemID,
+		Position: int64(m.Position),
+		TargetId: m.TargetID,
+	}
+}
+
+// ToMove converts a command message to a move. It fails if the kind is
+// unspecified or unknown.
+func ToMove(msg *MoveCommand) (order.Move[string], error) {
+	kind, ok := fromKind[msg.GetKind()]
+	if !ok {
+		return order.Move[string]{}, fmt.Errorf("orderpb: unknown move kind %v", msg.GetKind())
+	}
+	return order.Move[string]{
+		Kind:     kind,
+		ItemID:   msg.GetItemId(),
+		Position: int(msg.GetPosition()),
+		TargetID: msg.GetTargetId(),
+	}, nil
+}
+
+// FromChangeSet converts a change set to its message.
+func FromChangeSet(changes order.ChangeSet) *ChangeSet {
+	msg := &ChangeSet{Changes: make([]*PositionChange, len(changes))}
+	for i, change := range changes {
+		msg.Changes[i] = &PositionChange{
+			ItemId:      change.ItemID,
+			OldPosition: int64(change.OldPosition),
+			NewPosition: int64(change.NewPosition),
+		}
+	}
+	return msg
+}
+
+// ToChangeSet converts a message to a change set.
+func ToChangeSet(msg *ChangeSet) order.ChangeSet {
+	if len(msg.GetChanges()) == 0 {
+		return nil
+	}
+	changes := make(order.ChangeSet, len(msg.GetChanges()))
+	for i, change := range msg.GetChanges() {
+		changes[i] = order.PositionChange{
+			ItemID:      change.GetItemId(),
+			OldPosition: int(change.GetOldPosition()),
+			NewPosition: int(change.GetNewPosition()),
+		}
+	}
+	return changes
+}
+
+// FromEvent converts an order event to its message.
+func FromEvent(event order.OrderChangedEvent) *OrderChangedEvent {
+	msg := &OrderChangedEvent{
+		ListId:      event.ListID,
+		OperationId: event.OperationID,
+		Changes:     FromChangeSet(event.Changes),
+		Version:     event.Version,
+		Time:        timestamppb.New(event.Time),
+		Actor:       event.Actor,
+		TenantId:    event.TenantID,
+	}
+	if event.Move.Kind != 0 {
+		msg.Move = FromMove(event.Move)
+	}
+	return msg
+}
+
+// ToEvent converts a message to an order event. It fails if the message
+// carries a move of an unspecified or unknown kind.
+func ToEvent(msg *OrderChangedEvent) (order.OrderChangedEvent, error) {
+	event := order.OrderChangedEvent{
+		ListID:      msg.GetListId(),
+		OperationID: msg.GetOperationId(),
+		Changes:     ToChangeSet(msg.GetChanges()),
+		Version:     msg.GetVersion(),
+		Time:        msg.GetTime().AsTime(),
+		Actor:       msg.GetActor(),
+		TenantID:    msg.GetTenantId(),
+	}
+	if msg.GetMove() != nil {
+		m, err := ToMove(msg.GetMove())
+		if err != nil {
+			return order.OrderChangedEvent{}, err
+		}
+		event.Move = m
+	}
+	return event, nil
+}
@@ -0,0 +1,80 @@
+package orderpb_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/orderpb"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	snap := order.OrderSnapshot{IDs: []string{"b", "a"}, TakenAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)}
+
+	data, err := proto.Marshal(orderpb.FromSnapshot(snap))
+	require.NoError(t, err)
+	var msg orderpb.OrderSnapshot
+	require.NoError(t, proto.Unmarshal(data, &msg))
+
+	got := orderpb.ToSnapshot(&msg)
+	assert.Equal(t, snap.IDs, got.IDs)
+	assert.True(t, snap.TakenAt.Equal(got.TakenAt))
+}
+
+func TestMoveRoundTrip(t *testing.T) {
+	move := order.Move[string]{Kind: order.MoveBelow, ItemID: "a", TargetID: "b"}
+	msg := orderpb.FromMove(move)
+	assert.Equal(t, orderpb.MoveKind_MOVE_KIND_BELOW, msg.GetKind())
+
+	got, err := orderpb.ToMove(msg)
+	require.NoError(t, err)
+	assert.Equal(t, move, got)
+
+	_, err = orderpb.ToMove(&orderpb.MoveCommand{ItemId: "a"})
+	assert.Error(t, err)
+}
+
+func TestChangeSetRoundTrip(t *testing.T) {
+	changes := order.ChangeSet{{ItemID: "a", OldPosition: 1, NewPosition: 2}, {ItemID: "b", OldPosition: 2, NewPosition: 1}}
+	assert.Equal(t, changes, orderpb.ToChangeSet(orderpb.FromChangeSet(changes)))
+	assert.Nil(t, orderpb.ToChangeSet(&orderpb.ChangeSet{}))
+}
+
+func TestFromItems(t *testing.T) {
+	refs := orderpb.FromItems(ordertest.Items(2))
+	require.Len(t, refs, 2)
+	assert.Equal(t, "item-2", refs[1].GetId())
+	assert.Equal(t, int64(2), refs[1].GetPosition())
+}
+
+func TestEventRoundTrip(t *testing.T) {
+	event := order.OrderChangedEvent{
+		ListID:      "list",
+		OperationID: "op-1",
+		Move:        order.Move[string]{Kind: order.MoveTop, ItemID: "b"},
+		Changes:     order.ChangeSet{{ItemID: "b", OldPosition: 2, NewPosition: 1}},
+		Version:     3,
+		Time:        time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Actor:       "alice",
+		TenantID:    "acme",
+	}
+	data, err := proto.Marshal(orderpb.FromEvent(event))
+	require.NoError(t, err)
+	var msg orderpb.OrderChangedEvent
+	require.NoError(t, proto.Unmarshal(data, &msg))
+	got, err := orderpb.ToEvent(&msg)
+	require.NoError(t, err)
+	assert.Equal(t, event, got)
+
+	// Events of ApplyOrder carry no move.
+	event.Move = order.Move[string]{}
+	assert.Nil(t, orderpb.FromEvent(event).GetMove())
+	got, err = orderpb.ToEvent(orderpb.FromEvent(event))
+	require.NoError(t, err)
+	assert.Equal(t, event, got)
+}
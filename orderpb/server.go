@@ -0,0 +1,51 @@
+package orderpb
+
+import (
+	"github.com/yacobolo/order"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// DefaultWatchBuffer is the number of events a WatchList stream may fall
+// behind before it is ended.
+const DefaultWatchBuffer = 256
+
+// Server implements OrderServiceServer for the lists of an
+// order.PersistentManager:
+//
+//	s := grpc.NewServer()
+//	orderpb.RegisterOrderServiceServer(s, orderpb.NewServer(pm))
+type Server[T order.Orderable] struct {
+	UnimplementedOrderServiceServer
+	manager *order.PersistentManager[T]
+	buffer  int
+}
+
+// NewServer creates a Server for the lists managed by manager, watching them
+// with DefaultWatchBuffer.
+func NewServer[T order.Orderable](manager *order.PersistentManager[T]) *Server[T] {
+	return &Server[T]{manager: manager, buffer: DefaultWatchBuffer}
+}
+
+// WatchList implements OrderServiceServer with order.PersistentManager.Watch.
+// It sends the response headers as soon as the watch is in place, so a client
+// that waits for them knows it will see every later change.
+func (s *Server[T]) WatchList(req *WatchListRequest, stream grpc.ServerStreamingServer[OrderChangedEvent]) error {
+	ctx := stream.Context()
+	events := s.manager.Watch(ctx, req.GetListId(), s.buffer)
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+	for event := range events {
+		if err := stream.Send(FromEvent(event)); err != nil {
+			return err
+		}
+	}
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.ResourceExhausted, "orderpb: watcher fell behind")
+}
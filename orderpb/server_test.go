@@ -0,0 +1,62 @@
+package orderpb_test
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/orderpb"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func TestServerWatchList(t *testing.T) {
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	store.Put("other", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	listener := bufconn.Listen(1 << 20)
+	server := grpc.NewServer()
+	orderpb.RegisterOrderServiceServer(server, orderpb.NewServer(pm))
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := orderpb.NewOrderServiceClient(conn).WatchList(ctx, &orderpb.WatchListRequest{ListId: "list"})
+	require.NoError(t, err)
+	// The server sends the headers once the watch is in place.
+	_, err = stream.Header()
+	require.NoError(t, err)
+	_, err = pm.Apply(ctx, "other", order.Move[string]{Kind: order.MoveTop, ItemID: "item-2"})
+	require.NoError(t, err)
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+
+	msg, err := stream.Recv()
+	require.NoError(t, err)
+	event, err := orderpb.ToEvent(msg)
+	require.NoError(t, err)
+	assert.Equal(t, "list", event.ListID)
+	assert.Equal(t, order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"}, event.Move)
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "item-3", OldPosition: 3, NewPosition: 1},
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+		{ItemID: "item-2", OldPosition: 2, NewPosition: 3},
+	}, event.Changes)
+	assert.NotEmpty(t, event.OperationID)
+}
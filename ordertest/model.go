@@ -0,0 +1,129 @@
+package ordertest
+
+import (
+	"fmt"
+
+	"github.com/yacobolo/order"
+)
+
+// Model is a deliberately naive reference implementation of the move verbs over
+// a list of IDs. It rebuilds the list on every operation, which makes it easy
+// to trust and suitable as the oracle in differential tests of OrderManager
+// wrappers and alternative backends.
+type Model struct {
+	ids []string
+}
+
+// NewModel returns a model holding ids in order.
+func NewModel(ids ...string) *Model {
+	return &Model{ids: append([]string(nil), ids...)}
+}
+
+// IDs returns a copy of the current order.
+func (m *Model) IDs() []string {
+	return append([]string(nil), m.ids...)
+}
+
+// Up moves id up by one; it is a no-op for the first item.
+func (m *Model) Up(id string) error {
+	i, err := m.index(id)
+	if err != nil {
+		return err
+	}
+	if i > 0 {
+		m.ids[i-1], m.ids[i] = m.ids[i], m.ids[i-1]
+	}
+	return nil
+}
+
+// Down moves id down by one; it is a no-op for the last item.
+func (m *Model) Down(id string) error {
+	i, err := m.index(id)
+	if err != nil {
+		return err
+	}
+	if i < len(m.ids)-1 {
+		m.ids[i+1], m.ids[i] = m.ids[i], m.ids[i+1]
+	}
+	return nil
+}
+
+// To moves id to the 1-based position.
+func (m *Model) To(id string, position int) error {
+	if position < 1 || position > len(m.ids) {
+		return fmt.Errorf("To: %w", order.ErrInvalidPosition)
+	}
+	if _, err := m.index(id); err != nil {
+		return err
+	}
+	rest := m.without(id)
+	var ids []string
+	ids = append(ids, rest[:position-1]...)
+	ids = append(ids, id)
+	ids = append(ids, rest[position-1:]...)
+	m.ids = ids
+	return nil
+}
+
+// Top moves id to the first position.
+func (m *Model) Top(id string) error {
+	return m.To(id, 1)
+}
+
+// Bottom moves id to the last position.
+func (m *Model) Bottom(id string) error {
+	return m.To(id, len(m.ids))
+}
+
+// Above places id directly before targetID; it is a no-op if both are the same.
+func (m *Model) Above(id, targetID string) error {
+	return m.place(id, targetID, 0)
+}
+
+// Below places id directly after targetID; it is a no-op if both are the same.
+func (m *Model) Below(id, targetID string) error {
+	return m.place(id, targetID, 1)
+}
+
+func (m *Model) place(id, targetID string, offset int) error {
+	if _, err := m.index(id); err != nil {
+		return err
+	}
+	if _, err := m.index(targetID); err != nil {
+		return err
+	}
+	if id == targetID {
+		return nil
+	}
+	var ids []string
+	for _, other := range m.without(id) {
+		if other == targetID && offset == 0 {
+			ids = append(ids, id)
+		}
+		ids = append(ids, other)
+		if other == targetID && offset == 1 {
+			ids = append(ids, id)
+		}
+	}
+	m.ids = ids
+	return nil
+}
+
+func (m *Model) index(id string) (int, error) {
+	for i, other := range m.ids {
+		if other == id {
+			return i, nil
+		}
+	}
+	return -1, fmt.Errorf("%s: %w", id, order.ErrItemNotFound)
+}
+
+func (m *Model) without(id string) []string {
+	var rest []string
+	for _, other := range m.ids {
+		if other != id {
+			rest = append(rest, other)
+		}
+	}
+	return rest
+}
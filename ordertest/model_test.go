@@ -0,0 +1,65 @@
+package ordertest_test
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/require"
+)
+
+// TestOrderManagerMatchesModel runs random operations against OrderManager and
+// the reference model and checks that both agree after every step.
+func TestOrderManagerMatchesModel(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	om := order.NewOrderManager[*ordertest.Item]()
+
+	for run := 0; run < 50; run++ {
+		n := 1 + r.Intn(8)
+		items := ordertest.Items(n)
+		model := ordertest.NewModel(ordertest.IDs(items)...)
+
+		for step := 0; step < 30; step++ {
+			// Occasionally pick an unknown ID or an out-of-range position.
+			id := fmt.Sprintf("item-%d", 1+r.Intn(n+1))
+			target := fmt.Sprintf("item-%d", 1+r.Intn(n+1))
+			position := r.Intn(n + 2)
+
+			var got, want error
+			switch r.Intn(7) {
+			case 0:
+				_, got = om.Up(items, id)
+				want = model.Up(id)
+			case 1:
+				_, got = om.Down(items, id)
+				want = model.Down(id)
+			case 2:
+				_, got = om.To(items, id, position)
+				want = model.To(id, position)
+			case 3:
+				_, got = om.Top(items, id)
+				want = model.Top(id)
+			case 4:
+				_, got = om.Bottom(items, id)
+				want = model.Bottom(id)
+			case 5:
+				_, got = om.Above(items, id, target)
+				want = model.Above(id, target)
+			case 6:
+				_, got = om.Below(items, id, target)
+				want = model.Below(id, target)
+			}
+
+			require.Equal(t, want == nil, got == nil, "run %d step %d: got %v, want %v", run, step, got, want)
+			for _, sentinel := range []error{order.ErrItemNotFound, order.ErrInvalidPosition} {
+				require.Equal(t, errors.Is(want, sentinel), errors.Is(got, sentinel), "run %d step %d", run, step)
+			}
+			require.NoError(t, order.Invariants(items))
+			require.Equal(t, model.IDs(), ordertest.IDs(items), "run %d step %d", run, step)
+		}
+	}
+}
@@ -0,0 +1,118 @@
+// Package ordertest provides fixtures, assertions and a fake Store for testing
+// code built on package order.
+package ordertest
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"github.com/yacobolo/order"
+)
+
+// Item is a minimal order.Orderable fixture.
+type Item struct {
+	ID       string
+	Position int
+	Name     string
+}
+
+func (i *Item) GetID() string {
+	return i.ID
+}
+
+func (i *Item) GetPosition() int {
+	return i.Position
+}
+
+func (i *Item) SetPosition(position int) {
+	i.Position = position
+}
+
+// Label implements order.Labeler so Dump output shows the item name.
+func (i *Item) Label() string {
+	return i.Name
+}
+
+// Items returns n normalized items with IDs "item-1" through "item-n".
+func Items(n int) []*Item {
+	items := make([]*Item, n)
+	for i := range items {
+		items[i] = &Item{
+			ID:       fmt.Sprintf("item-%d", i+1),
+			Position: i + 1,
+			Name:     fmt.Sprintf("Item %d", i+1),
+		}
+	}
+	return items
+}
+
+// ItemsWithIDs returns normalized items with the given IDs, in order.
+func ItemsWithIDs(ids ...string) []*Item {
+	items := make([]*Item, len(ids))
+	for i, id := range ids {
+		items[i] = &Item{ID: id, Position: i + 1, Name: id}
+	}
+	return items
+}
+
+// Shuffled returns n items in a pseudo-random order determined by seed, with
+// positions still matching the original order, so the slice is not sorted by
+// position. It is useful for exercising repair and validation paths.
+func Shuffled(n int, seed int64) []*Item {
+	items := Items(n)
+	r := rand.New(rand.NewSource(seed))
+	r.Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+	return items
+}
+
+// IDs returns the IDs of items in slice order.
+func IDs[T order.Orderable](items []T) []string {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.GetID()
+	}
+	return ids
+}
+
+// AssertOrder fails t unless items have exactly the given IDs in that order.
+func AssertOrder[T order.Orderable](t testing.TB, items []T, ids ...string) bool {
+	t.Helper()
+	got := IDs(items)
+	if len(got) != len(ids) {
+		t.Errorf("order mismatch: got %d items %v, want %d items %v", len(got), got, len(ids), ids)
+		return false
+	}
+	for i := range ids {
+		if got[i] != ids[i] {
+			t.Errorf("order mismatch at index %d: got %s, want %s\ngot:  %v\nwant: %v", i, got[i], ids[i], got, ids)
+			return false
+		}
+	}
+	return true
+}
+
+// AssertNormalized fails t unless the positions of items are 1..n in slice order.
+func AssertNormalized[T order.Orderable](t testing.TB, items []T) bool {
+	t.Helper()
+	if order.IsNormalized(items) {
+		return true
+	}
+	t.Errorf("items are not normalized:\n%s", order.Dump(items))
+	return false
+}
+
+// AssertValid fails t if order.Validate reports any issue.
+func AssertValid[T order.Orderable](t testing.TB, items []T) bool {
+	t.Helper()
+	issues := order.Validate(items)
+	if len(issues) == 0 {
+		return true
+	}
+	for _, issue := range issues {
+		t.Errorf("%s: %s", issue.Kind, issue.Message)
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package ordertest_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recorder captures failures instead of failing the enclosing test.
+type recorder struct {
+	testing.TB
+	failed bool
+}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failed = true
+}
+
+func TestItems(t *testing.T) {
+	items := ordertest.Items(3)
+
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3")
+	ordertest.AssertNormalized(t, items)
+	ordertest.AssertValid(t, items)
+}
+
+func TestShuffled(t *testing.T) {
+	a := ordertest.Shuffled(20, 7)
+	b := ordertest.Shuffled(20, 7)
+
+	assert.Equal(t, ordertest.IDs(a), ordertest.IDs(b))
+	assert.False(t, order.IsSortedByPosition(a))
+}
+
+func TestAssertionsReportFailures(t *testing.T) {
+	items := ordertest.ItemsWithIDs("a", "b", "c")
+
+	r := &recorder{TB: t}
+	assert.False(t, ordertest.AssertOrder(r, items, "a", "c", "b"))
+	assert.True(t, r.failed)
+
+	r = &recorder{TB: t}
+	assert.False(t, ordertest.AssertOrder(r, items, "a", "b"))
+	assert.True(t, r.failed)
+
+	items[1].SetPosition(5)
+	r = &recorder{TB: t}
+	assert.False(t, ordertest.AssertNormalized(r, items))
+	assert.True(t, r.failed)
+
+	r = &recorder{TB: t}
+	assert.False(t, ordertest.AssertValid(r, items))
+	assert.True(t, r.failed)
+}
+
+func TestStore(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.ItemsWithIDs("a", "b", "c"))
+
+	items, version, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "a", "b", "c")
+
+	changes := order.ChangeSet{
+		{ItemID: "a", OldPosition: 1, NewPosition: 3},
+		{ItemID: "b", OldPosition: 2, NewPosition: 1},
+		{ItemID: "c", OldPosition: 3, NewPosition: 2},
+	}
+	newVersion, err := store.SavePositions(ctx, "list", version, changes)
+	require.NoError(t, err)
+	assert.Equal(t, version+1, newVersion)
+	assert.Equal(t, 1, store.Saves())
+
+	items, _, err = store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "b", "c", "a")
+
+	_, err = store.SavePositions(ctx, "list", version, changes)
+	assert.True(t, errors.Is(err, order.ErrVersionConflict))
+
+	_, _, err = store.Load(ctx, "missing")
+	assert.True(t, errors.Is(err, order.ErrListNotFound))
+}
+
+func TestStoreScanPage(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.ItemsWithIDs("a", "b", "c"))
+
+	page, cursor, err := store.ScanPage(ctx, "list", "", 2)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, page, "a", "b")
+
+	// Writes do not disturb a running scan.
+	require.NoError(t, store.WritePositions(ctx, "list", order.ChangeSet{{ItemID: "c", OldPosition: 3, NewPosition: 0}}))
+	page, cursor, err = store.ScanPage(ctx, "list", cursor, 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"c"}, ordertest.IDs(page))
+	assert.Empty(t, cursor)
+	assert.Equal(t, int64(2), store.Version("list"))
+
+	_, _, err = store.ScanPage(ctx, "list", "bogus", 2)
+	assert.Error(t, err)
+	_, _, err = store.ScanPage(ctx, "missing", "", 2)
+	assert.True(t, errors.Is(err, order.ErrListNotFound))
+}
+
+func TestStoreScanPageRejectsBadArguments(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.ItemsWithIDs("a", "b", "c"))
+
+	_, _, err := store.ScanPage(ctx, "list", "", 0)
+	assert.Error(t, err)
+	_, cursor, err := store.ScanPage(ctx, "list", "", 1)
+	require.NoError(t, err)
+	scan, _, _ := strings.Cut(cursor, ":")
+	_, _, err = store.ScanPage(ctx, "list", scan+":4", 1)
+	assert.Error(t, err, "offset past the end")
+	_, _, err = store.ScanPage(ctx, "list", scan+":-1", 1)
+	assert.Error(t, err, "negative offset")
+}
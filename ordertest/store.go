@@ -0,0 +1,172 @@
+package ordertest
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/yacobolo/order"
+)
+
+// Store is an in-memory order.Store fake. Loaded slices are copies, but the
+// items themselves are shared with the store, so tests should persist changes
+// through SavePositions as production code would.
+type Store[T order.Orderable] struct {
+	mu       sync.Mutex
+	lists    map[string][]T
+	versions map[string]int64
+	saves    int
+	scans    map[int][]T
+	nextScan int
+}
+
+var _ order.Store[*Item] = (*Store[*Item])(nil)
+var _ order.ListScanner[*Item] = (*Store[*Item])(nil)
+var _ order.ImportStore[*Item] = (*Store[*Item])(nil)
+
+// NewStore returns an empty fake store.
+func NewStore[T order.Orderable]() *Store[T] {
+	return &Store[T]{
+		lists:    make(map[string][]T),
+		versions: make(map[string]int64),
+		scans:    make(map[int][]T),
+	}
+}
+
+// Put replaces the contents of a list and bumps its version.
+func (s *Store[T]) Put(listID string, items []T) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists[listID] = append([]T(nil), items...)
+	s.versions[listID]++
+}
+
+// Load implements order.Store.
+func (s *Store[T]) Load(ctx context.Context, listID string) ([]T, int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	items, ok := s.lists[listID]
+	if !ok {
+		return nil, 0, fmt.Errorf("Load %s: %w", listID, order.ErrListNotFound)
+	}
+	return append([]T(nil), items...), s.versions[listID], nil
+}
+
+// SavePositions implements order.Store.
+func (s *Store[T]) SavePositions(ctx context.Context, listID string, expectedVersion int64, changes order.ChangeSet) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.lists[listID]
+	if !ok {
+		return 0, fmt.Errorf("SavePositions %s: %w", listID, order.ErrListNotFound)
+	}
+	if s.versions[listID] != expectedVersion {
+		return 0, fmt.Errorf("SavePositions %s: version %d, expected %d: %w", listID, s.versions[listID], expectedVersion, order.ErrVersionConflict)
+	}
+
+	s.apply(listID, changes)
+	s.saves++
+	return s.versions[listID], nil
+}
+
+// apply sets the new positions of changes, re-sorts the list and bumps its
+// version. The caller holds s.mu.
+func (s *Store[T]) apply(listID string, changes order.ChangeSet) {
+	items := s.lists[listID]
+	positions := make(map[string]int, len(changes))
+	for _, change := range changes {
+		positions[change.ItemID] = change.NewPosition
+	}
+	for _, item := range items {
+		if position, ok := positions[item.GetID()]; ok {
+			item.SetPosition(position)
+		}
+	}
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].GetPosition() < items[j].GetPosition()
+	})
+	s.versions[listID]++
+}
+
+// Version returns the current version of a list, or 0 if it does not exist.
+func (s *Store[T]) Version(listID string) int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.versions[listID]
+}
+
+// Saves returns the number of successful SavePositions calls.
+func (s *Store[T]) Saves() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.saves
+}
+
+// ScanPage implements order.ListScanner. A scan pages over a copy of the list
+// taken when its first page is read, which is dropped after the last page.
+// It fails for a limit that is not positive.
+func (s *Store[T]) ScanPage(ctx context.Context, listID, cursor string, limit int) ([]T, string, error) {
+	if limit <= 0 {
+		return nil, "", fmt.Errorf("ScanPage %s: invalid limit %d", listID, limit)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	scan, offset := 0, 0
+	if cursor == "" {
+		items, ok := s.lists[listID]
+		if !ok {
+			return nil, "", fmt.Errorf("ScanPage %s: %w", listID, order.ErrListNotFound)
+		}
+		s.nextScan++
+		scan = s.nextScan
+		s.scans[scan] = append([]T(nil), items...)
+	} else {
+		var err error
+		scan, offset, err = parseScanCursor(cursor)
+		if snapshot, ok := s.scans[scan]; err != nil || !ok || offset < 0 || offset > len(snapshot) {
+			return nil, "", fmt.Errorf("ScanPage %s: invalid cursor %q", listID, cursor)
+		}
+	}
+
+	snapshot := s.scans[scan]
+	end := min(offset+limit, len(snapshot))
+	page := append([]T(nil), snapshot[offset:end]...)
+	if end == len(snapshot) {
+		delete(s.scans, scan)
+		return page, "", nil
+	}
+	return page, strconv.Itoa(scan) + ":" + strconv.Itoa(end), nil
+}
+
+func parseScanCursor(cursor string) (int, int, error) {
+	scan, offset, _ := strings.Cut(cursor, ":")
+	id, err := strconv.Atoi(scan)
+	if err != nil {
+		return 0, 0, err
+	}
+	n, err := strconv.Atoi(offset)
+	return id, n, err
+}
+
+// WritePositions implements order.ListScanner.
+func (s *Store[T]) WritePositions(ctx context.Context, listID string, changes order.ChangeSet) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.lists[listID]; !ok {
+		return fmt.Errorf("WritePositions %s: %w", listID, order.ErrListNotFound)
+	}
+	s.apply(listID, changes)
+	return nil
+}
+
+// InsertItems implements order.ItemInserter.
+func (s *Store[T]) InsertItems(ctx context.Context, listID string, items []T) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lists[listID] = append(s.lists[listID], items...)
+	s.apply(listID, nil)
+	return nil
+}
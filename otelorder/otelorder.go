@@ -0,0 +1,187 @@
+// Package otelorder adds OpenTelemetry tracing to package order.
+//
+// Operations on an order.OrderManager do not take a context, so Manager wraps
+// one with context-aware variants of the move verbs that record a span per
+// operation. NewStore wraps an order.Store so that Load and SavePositions get
+// their own spans; database spans created by the wrapped adapter from the
+// passed context become their children.
+package otelorder
+
+import (
+	"context"
+
+	"github.com/yacobolo/order"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ScopeName is the instrumentation scope name used for the default tracer.
+const ScopeName = "github.com/yacobolo/order/otelorder"
+
+// Attribute keys recorded on spans.
+const (
+	ListIDKey       = attribute.Key("order.list_id")
+	OperationKey    = attribute.Key("order.operation")
+	ItemIDKey       = attribute.Key("order.item_id")
+	TargetIDKey     = attribute.Key("order.target_id")
+	ListSizeKey     = attribute.Key("order.list_size")
+	ItemsChangedKey = attribute.Key("order.items_changed")
+)
+
+// Option configures a Manager or Store.
+type Option func(*config)
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// WithTracerProvider sets the provider used to create the tracer. The global
+// provider is used by default.
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+func newTracer(opts []Option) trace.Tracer {
+	c := config{provider: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c.provider.Tracer(ScopeName)
+}
+
+// Manager traces the operations of an order.OrderManager.
+type Manager[T order.Orderable] struct {
+	manager *order.OrderManager[T]
+	tracer  trace.Tracer
+}
+
+// NewManager returns a Manager tracing the operations of manager.
+func NewManager[T order.Orderable](manager *order.OrderManager[T], opts ...Option) *Manager[T] {
+	return &Manager[T]{manager: manager, tracer: newTracer(opts)}
+}
+
+// Up traces order.OrderManager.Up.
+func (m *Manager[T]) Up(ctx context.Context, listID string, items []T, itemID string) (order.Result[T], error) {
+	return traceMove(ctx, m.tracer, "Up", listID, items, itemID, "", func() (order.Result[T], error) {
+		return m.manager.Up(items, itemID)
+	})
+}
+
+// Down traces order.OrderManager.Down.
+func (m *Manager[T]) Down(ctx context.Context, listID string, items []T, itemID string) (order.Result[T], error) {
+	return traceMove(ctx, m.tracer, "Down", listID, items, itemID, "", func() (order.Result[T], error) {
+		return m.manager.Down(items, itemID)
+	})
+}
+
+// To traces order.OrderManager.To.
+func (m *Manager[T]) To(ctx context.Context, listID string, items []T, itemID string, newPosition int) (order.Result[T], error) {
+	return traceMove(ctx, m.tracer, "To", listID, items, itemID, "", func() (order.Result[T], error) {
+		return m.manager.To(items, itemID, newPosition)
+	})
+}
+
+// Top traces order.OrderManager.Top.
+func (m *Manager[T]) Top(ctx context.Context, listID string, items []T, itemID string) (order.Result[T], error) {
+	return traceMove(ctx, m.tracer, "Top", listID, items, itemID, "", func() (order.Result[T], error) {
+		return m.manager.Top(items, itemID)
+	})
+}
+
+// Bottom traces order.OrderManager.Bottom.
+func (m *Manager[T]) Bottom(ctx context.Context, listID string, items []T, itemID string) (order.Result[T], error) {
+	return traceMove(ctx, m.tracer, "Bottom", listID, items, itemID, "", func() (order.Result[T], error) {
+		return m.manager.Bottom(items, itemID)
+	})
+}
+
+// Above traces order.OrderManager.Above.
+func (m *Manager[T]) Above(ctx context.Context, listID string, items []T, itemID, targetID string) (order.Result[T], error) {
+	return traceMove(ctx, m.tracer, "Above", listID, items, itemID, targetID, func() (order.Result[T], error) {
+		return m.manager.Above(items, itemID, targetID)
+	})
+}
+
+// Below traces order.OrderManager.Below.
+func (m *Manager[T]) Below(ctx context.Context, listID string, items []T, itemID, targetID string) (order.Result[T], error) {
+	return traceMove(ctx, m.tracer, "Below", listID, items, itemID, targetID, func() (order.Result[T], error) {
+		return m.manager.Below(items, itemID, targetID)
+	})
+}
+
+func traceMove[T order.Orderable](ctx context.Context, tracer trace.Tracer, op, listID string, items []T, itemID, targetID string, fn func() (order.Result[T], error)) (order.Result[T], error) {
+	attrs := []attribute.KeyValue{
+		ListIDKey.String(listID),
+		OperationKey.String(op),
+		ItemIDKey.String(itemID),
+		ListSizeKey.Int(len(items)),
+	}
+	if targetID != "" {
+		attrs = append(attrs, TargetIDKey.String(targetID))
+	}
+	_, span := tracer.Start(ctx, "order."+op, trace.WithAttributes(attrs...))
+	defer span.End()
+
+	result, err := fn()
+	span.SetAttributes(ItemsChangedKey.Int(len(result.Affected)))
+	recordError(span, err)
+	return result, err
+}
+
+// Store traces the calls to an order.Store.
+type Store[T order.Orderable] struct {
+	store  order.Store[T]
+	tracer trace.Tracer
+}
+
+var (
+	_ order.Store[order.Orderable]   = (*Store[order.Orderable])(nil)
+	_ order.Wrapper[order.Orderable] = (*Store[order.Orderable])(nil)
+)
+
+// NewStore returns a Store tracing the calls to store.
+func NewStore[T order.Orderable](store order.Store[T], opts ...Option) *Store[T] {
+	return &Store[T]{store: store, tracer: newTracer(opts)}
+}
+
+// Unwrap implements order.Wrapper, so that the optional interfaces of the
+// traced store, such as order.HealthChecker, are still found.
+func (s *Store[T]) Unwrap() order.Store[T] {
+	return s.store
+}
+
+// Load implements order.Store.
+func (s *Store[T]) Load(ctx context.Context, listID string) ([]T, int64, error) {
+	ctx, span := s.tracer.Start(ctx, "order.Store.Load", trace.WithAttributes(ListIDKey.String(listID)))
+	defer span.End()
+
+	items, version, err := s.store.Load(ctx, listID)
+	span.SetAttributes(ListSizeKey.Int(len(items)))
+	recordError(span, err)
+	return items, version, err
+}
+
+// SavePositions implements order.Store.
+func (s *Store[T]) SavePositions(ctx context.Context, listID string, expectedVersion int64, changes order.ChangeSet) (int64, error) {
+	ctx, span := s.tracer.Start(ctx, "order.Store.SavePositions", trace.WithAttributes(
+		ListIDKey.String(listID),
+		ItemsChangedKey.Int(len(changes)),
+	))
+	defer span.End()
+
+	version, err := s.store.SavePositions(ctx, listID, expectedVersion, changes)
+	recordError(span, err)
+	return version, err
+}
+
+func recordError(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,113 @@
+package otelorder_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+	"github.com/yacobolo/order/otelorder"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func newProvider() (*sdktrace.TracerProvider, *tracetest.SpanRecorder) {
+	recorder := tracetest.NewSpanRecorder()
+	return sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)), recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	attrs := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		attrs[kv.Key] = kv.Value
+	}
+	return attrs
+}
+
+func TestManagerSpans(t *testing.T) {
+	provider, recorder := newProvider()
+	m := otelorder.NewManager(order.NewOrderManager[*ordertest.Item](), otelorder.WithTracerProvider(provider))
+	items := ordertest.ItemsWithIDs("a", "b", "c")
+
+	_, err := m.Above(context.Background(), "board", items, "c", "a")
+	require.NoError(t, err)
+	_, err = m.To(context.Background(), "board", items, "a", 9)
+	require.Error(t, err)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	assert.Equal(t, "order.Above", spans[0].Name())
+	attrs := attributes(spans[0])
+	assert.Equal(t, "board", attrs[otelorder.ListIDKey].AsString())
+	assert.Equal(t, "c", attrs[otelorder.ItemIDKey].AsString())
+	assert.Equal(t, "a", attrs[otelorder.TargetIDKey].AsString())
+	assert.Equal(t, int64(3), attrs[otelorder.ItemsChangedKey].AsInt64())
+
+	assert.Equal(t, "order.To", spans[1].Name())
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+}
+
+func TestStoreSpansAreParents(t *testing.T) {
+	provider, recorder := newProvider()
+	inner := ordertest.NewStore[*ordertest.Item]()
+	inner.Put("board", ordertest.Items(3))
+
+	// adapter stands in for a database adapter that starts its own spans.
+	tracer := provider.Tracer("db")
+	adapter := &spanningStore{Store: inner, start: func(ctx context.Context) {
+		_, span := tracer.Start(ctx, "db.query")
+		span.End()
+	}}
+	store := otelorder.NewStore[*ordertest.Item](adapter, otelorder.WithTracerProvider(provider))
+
+	_, version, err := store.Load(context.Background(), "board")
+	require.NoError(t, err)
+	_, err = store.SavePositions(context.Background(), "board", version+1, nil)
+	assert.True(t, errors.Is(err, order.ErrVersionConflict))
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 4)
+	assert.Equal(t, "db.query", spans[0].Name())
+	assert.Equal(t, "order.Store.Load", spans[1].Name())
+	assert.Equal(t, spans[1].SpanContext().SpanID(), spans[0].Parent().SpanID())
+	assert.Equal(t, "order.Store.SavePositions", spans[3].Name())
+	assert.Equal(t, codes.Error, spans[3].Status().Code)
+}
+
+type spanningStore struct {
+	*ordertest.Store[*ordertest.Item]
+	start func(context.Context)
+}
+
+func (s *spanningStore) Load(ctx context.Context, listID string) ([]*ordertest.Item, int64, error) {
+	s.start(ctx)
+	return s.Store.Load(ctx, listID)
+}
+
+func (s *spanningStore) SavePositions(ctx context.Context, listID string, expectedVersion int64, changes order.ChangeSet) (int64, error) {
+	s.start(ctx)
+	return s.Store.SavePositions(ctx, listID, expectedVersion, changes)
+}
+
+// unhealthyStore is a Store whose health check fails.
+type unhealthyStore struct {
+	*ordertest.Store[*ordertest.Item]
+}
+
+func (unhealthyStore) Health(context.Context) error {
+	return errors.New("connection refused")
+}
+
+func TestStoreKeepsHealthCheck(t *testing.T) {
+	provider, _ := newProvider()
+	store := otelorder.NewStore[*ordertest.Item](unhealthyStore{ordertest.NewStore[*ordertest.Item]()}, otelorder.WithTracerProvider(provider))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	assert.ErrorIs(t, pm.Health(context.Background()), order.ErrUnhealthy)
+}
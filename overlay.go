@@ -0,0 +1,109 @@
+package order
+
+import "slices"
+
+// Overlay is a personal ordering layered over a shared base order: it records
+// only the items a user moved, each relative to an anchor, so that the user's
+// view follows changes to the base for everything else. Resolve combines a
+// base with an overlay, and MoveInOverlay records a move of the user. The
+// zero value is an empty overlay, and an Overlay marshals to JSON as is.
+type Overlay struct {
+	// Placements are the user's moves in the order they were made. Every
+	// item occurs at most once, with its latest placement.
+	Placements []RelativePlacement[string]
+}
+
+// Place records that the user put itemID directly above anchorID, or below
+// it if below is set, replacing an earlier placement of the item.
+func (o *Overlay) Place(itemID, anchorID string, below bool) {
+	o.Reset(itemID)
+	o.Placements = append(o.Placements, RelativePlacement[string]{ItemID: itemID, AnchorID: anchorID, Below: below})
+}
+
+// Reset drops the placement of itemID, so that it is back at its base
+// position in the user's view.
+func (o *Overlay) Reset(itemID string) {
+	o.Placements = slices.DeleteFunc(o.Placements, func(p RelativePlacement[string]) bool { return p.ItemID == itemID })
+}
+
+// Resolve returns the user's view of base: the items of base, replaying the
+// placements of overlay in order. Placements whose item or anchor is no longer
+// in base are skipped, and items added to base show up at their base
+// position. Neither base nor the positions of its items are changed.
+func Resolve[T Orderable](base []T, overlay Overlay) []T {
+	view := overlaySlots(base)
+	replayOverlay(overlayManager[T](), view, overlay)
+	return overlayItems(view)
+}
+
+// MoveInOverlay performs m on the user's view of base and records the result
+// in overlay, anchored to the item now directly above the moved item, or
+// below it if it moved to the top. It returns the new view. Like Resolve, it
+// changes neither base nor the positions of its items.
+func MoveInOverlay[T Orderable](base []T, overlay *Overlay, m Move[string]) ([]T, error) {
+	view := overlaySlots(Resolve(base, *overlay))
+	fm := overlayManager[T]()
+	result, err := fm.Apply(view, m)
+	if err != nil {
+		return nil, err
+	}
+	if result.Changed {
+		i, _ := fm.GetItemIndexByID(view, m.ItemID)
+		overlay.record(m.ItemID, i, len(view), func(j int) string { return view[j].item.GetID() })
+	}
+	return overlayItems(view), nil
+}
+
+// record places itemID, now at index i of a view of n items, below the item
+// above it, or above the item below it if it is at the top.
+func (o *Overlay) record(itemID string, i, n int, idAt func(int) string) {
+	switch {
+	case i > 0:
+		o.Place(itemID, idAt(i-1), true)
+	case n > 1:
+		o.Place(itemID, idAt(1), false)
+	}
+}
+
+// replayOverlay applies the placements of overlay to items with fm, skipping
+// the ones that fail.
+func replayOverlay[T any](fm *FuncManager[T], items []T, overlay Overlay) {
+	for _, p := range overlay.Placements {
+		m := Move[string]{Kind: MoveAbove, ItemID: p.ItemID, TargetID: p.AnchorID}
+		if p.Below {
+			m.Kind = MoveBelow
+		}
+		_, _ = fm.Apply(items, m)
+	}
+}
+
+// overlaySlot holds an item together with its position in a view, so that
+// views can be reordered without touching the shared items.
+type overlaySlot[T Orderable] struct {
+	item     T
+	position int
+}
+
+func overlayManager[T Orderable]() *FuncManager[overlaySlot[T]] {
+	return NewFuncManager(
+		func(s overlaySlot[T]) string { return s.item.GetID() },
+		func(s overlaySlot[T]) int { return s.position },
+		func(s *overlaySlot[T], position int) { s.position = position },
+	)
+}
+
+func overlaySlots[T Orderable](items []T) []overlaySlot[T] {
+	slots := make([]overlaySlot[T], len(items))
+	for i, item := range items {
+		slots[i] = overlaySlot[T]{item: item, position: i + 1}
+	}
+	return slots
+}
+
+func overlayItems[T Orderable](slots []overlaySlot[T]) []T {
+	items := make([]T, len(slots))
+	for i, slot := range slots {
+		items[i] = slot.item
+	}
+	return items
+}
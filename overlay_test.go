@@ -0,0 +1,59 @@
+package order_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOverlay(t *testing.T) {
+	base := ordertest.ItemsWithIDs("a", "b", "c", "d")
+	var overlay order.Overlay
+
+	view, err := order.MoveInOverlay(base, &overlay, order.Move[string]{Kind: order.MoveTop, ItemID: "c"})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, view, "c", "a", "b", "d")
+	view, err = order.MoveInOverlay(base, &overlay, order.Move[string]{Kind: order.MoveBelow, ItemID: "a", TargetID: "d"})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, view, "c", "b", "d", "a")
+	assert.Equal(t, []order.RelativePlacement[string]{
+		{ItemID: "c", AnchorID: "a"},
+		{ItemID: "a", AnchorID: "d", Below: true},
+	}, overlay.Placements)
+
+	// The shared base is untouched.
+	ordertest.AssertOrder(t, base, "a", "b", "c", "d")
+	ordertest.AssertNormalized(t, base)
+	ordertest.AssertOrder(t, order.Resolve(base, overlay), "c", "b", "d", "a")
+
+	// A teammate adds e at the top and removes d from the base.
+	base = ordertest.ItemsWithIDs("e", "a", "b", "c")
+	ordertest.AssertOrder(t, order.Resolve(base, overlay), "e", "c", "a", "b")
+
+	overlay.Reset("c")
+	// a was anchored to d, which is gone, so nothing is left to replay.
+	ordertest.AssertOrder(t, order.Resolve(base, overlay), "e", "a", "b", "c")
+
+	_, err = order.MoveInOverlay(base, &overlay, order.Move[string]{Kind: order.MoveTop, ItemID: "gone"})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
+
+func TestOverlayJSON(t *testing.T) {
+	var overlay order.Overlay
+	overlay.Place("b", "a", false)
+	overlay.Place("b", "c", true)
+
+	data, err := json.Marshal(overlay)
+	require.NoError(t, err)
+	var decoded order.Overlay
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, overlay, decoded)
+	require.Len(t, decoded.Placements, 1)
+
+	ordertest.AssertOrder(t, order.Resolve(ordertest.ItemsWithIDs("a", "b", "c"), decoded), "a", "c", "b")
+}
@@ -0,0 +1,89 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"slices"
+)
+
+// Page returns page pageNum, counting from 1, of the collection split into
+// pages of pageSize items. A page past the end is empty. It fails with
+// ErrInvalidPosition if pageSize or pageNum is below 1.
+func (c *OrderedCollection[T]) Page(pageSize, pageNum int) ([]T, error) {
+	return pageOf(c.items, pageSize, pageNum)
+}
+
+// AfterID returns up to limit items following the item with id, or from the
+// first item for an empty id. Passing the ID of the last item returned as the
+// next id pages through the collection by order, like a keyset cursor. It
+// fails with a *NotFoundError if id is not in the collection.
+func (c *OrderedCollection[T]) AfterID(id string, limit int) ([]T, error) {
+	return itemsAfter(c.items, id, limit)
+}
+
+// PageLoader is implemented by stores that can load a page of a list without
+// loading all of it, for example with LIMIT/OFFSET or keyset queries on the
+// position column. LoadPage and LoadAfter use it when available. Pages must
+// hold the items in position order, so that paging agrees with Load whether
+// positions are dense, gapped or string ranks.
+type PageLoader[T Orderable] interface {
+	// LoadOffset returns up to limit items of a list, skipping the first
+	// offset items.
+	LoadOffset(ctx context.Context, listID string, offset, limit int) ([]T, error)
+	// LoadAfter returns up to limit items of a list following the item with
+	// afterID, or from the first item for an empty afterID.
+	LoadAfter(ctx context.Context, listID, afterID string, limit int) ([]T, error)
+}
+
+// LoadPage returns page pageNum of a stored list, like OrderedCollection.Page.
+// If store does not implement PageLoader, the whole list is loaded.
+func LoadPage[T Orderable](ctx context.Context, store Store[T], listID string, pageSize, pageNum int) ([]T, error) {
+	if pageSize < 1 || pageNum < 1 {
+		return pageOf[T](nil, pageSize, pageNum)
+	}
+	if pl, ok := lookup[PageLoader[T]](store); ok {
+		return pl.LoadOffset(ctx, listID, (pageNum-1)*pageSize, pageSize)
+	}
+	items, _, err := store.Load(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	return pageOf(items, pageSize, pageNum)
+}
+
+// LoadAfter returns up to limit items of a stored list following the item
+// with afterID, like OrderedCollection.AfterID. If store does not implement
+// PageLoader, the whole list is loaded.
+func LoadAfter[T Orderable](ctx context.Context, store Store[T], listID, afterID string, limit int) ([]T, error) {
+	if pl, ok := lookup[PageLoader[T]](store); ok {
+		return pl.LoadAfter(ctx, listID, afterID, max(limit, 0))
+	}
+	items, _, err := store.Load(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	return itemsAfter(items, afterID, limit)
+}
+
+func pageOf[T any](items []T, pageSize, pageNum int) ([]T, error) {
+	if pageSize < 1 {
+		return nil, fmt.Errorf("Page: page size %d: %w", pageSize, ErrInvalidPosition)
+	}
+	if pageNum < 1 {
+		return nil, fmt.Errorf("Page: page %d: %w", pageNum, ErrInvalidPosition)
+	}
+	start := min((pageNum-1)*pageSize, len(items))
+	return slices.Clone(items[start:min(start+pageSize, len(items))]), nil
+}
+
+func itemsAfter[T Orderable](items []T, id string, limit int) ([]T, error) {
+	start := 0
+	if id != "" {
+		i := slices.IndexFunc(items, func(item T) bool { return item.GetID() == id })
+		if i < 0 {
+			return nil, &NotFoundError{Op: "AfterID", ItemID: id}
+		}
+		start = i + 1
+	}
+	return slices.Clone(items[start:min(start+max(limit, 0), len(items))]), nil
+}
@@ -0,0 +1,102 @@
+package order_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectionPage(t *testing.T) {
+	col := order.NewOrderedCollection(ordertest.Items(5))
+
+	page, err := col.Page(2, 1)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, page, "item-1", "item-2")
+	page, err = col.Page(2, 3)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, page, "item-5")
+	page, err = col.Page(2, 4)
+	require.NoError(t, err)
+	assert.Empty(t, page)
+
+	_, err = col.Page(0, 1)
+	assert.ErrorIs(t, err, order.ErrInvalidPosition)
+	_, err = col.Page(2, 0)
+	assert.ErrorIs(t, err, order.ErrInvalidPosition)
+}
+
+func TestCollectionAfterID(t *testing.T) {
+	col := order.NewOrderedCollection(ordertest.Items(5))
+
+	var seen []string
+	for cursor := ""; ; {
+		page, err := col.AfterID(cursor, 2)
+		require.NoError(t, err)
+		if len(page) == 0 {
+			break
+		}
+		seen = append(seen, ordertest.IDs(page)...)
+		cursor = page[len(page)-1].GetID()
+	}
+	assert.Equal(t, ordertest.IDs(col.Items()), seen)
+
+	_, err := col.AfterID("missing", 2)
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
+
+func TestLoadPage(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c", "d")
+	for i, item := range items {
+		item.Position = (i + 1) * 100 // gapped
+	}
+	store.Put("list", items)
+
+	page, err := order.LoadPage[*ordertest.Item](ctx, store, "list", 3, 2)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, page, "d")
+	page, err = order.LoadAfter[*ordertest.Item](ctx, store, "list", "a", 2)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, page, "b", "c")
+
+	_, err = order.LoadPage[*ordertest.Item](ctx, store, "missing", 3, 1)
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+	_, err = order.LoadPage[*ordertest.Item](ctx, store, "list", 3, 0)
+	assert.ErrorIs(t, err, order.ErrInvalidPosition)
+}
+
+// pagingStore records the pages requested from it.
+type pagingStore struct {
+	*ordertest.Store[*ordertest.Item]
+	calls []string
+}
+
+func (s *pagingStore) LoadOffset(ctx context.Context, listID string, offset, limit int) ([]*ordertest.Item, error) {
+	s.calls = append(s.calls, "offset")
+	items, _, err := s.Load(ctx, listID)
+	return items[min(offset, len(items)):min(offset+limit, len(items))], err
+}
+
+func (s *pagingStore) LoadAfter(ctx context.Context, listID, afterID string, limit int) ([]*ordertest.Item, error) {
+	s.calls = append(s.calls, "after "+afterID)
+	return nil, nil
+}
+
+func TestLoadPageUsesPageLoader(t *testing.T) {
+	ctx := context.Background()
+	store := &pagingStore{Store: ordertest.NewStore[*ordertest.Item]()}
+	store.Put("list", ordertest.Items(5))
+
+	page, err := order.LoadPage[*ordertest.Item](ctx, store, "list", 2, 2)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, page, "item-3", "item-4")
+	_, err = order.LoadAfter[*ordertest.Item](ctx, store, "list", "item-4", 2)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"offset", "after item-4"}, store.calls)
+}
@@ -0,0 +1,78 @@
+package order
+
+import "context"
+
+// ApplyPermitted performs m for a user who may only reorder the items for
+// which canMove reports true, such as their own tasks in a shared list. The
+// move works on the permitted items as if the others were hidden: the
+// permitted items are rearranged in their slots, while every other item keeps
+// its slot, so its position only changes if the list had to be renumbered.
+// Position in a MoveTo counts among the permitted items.
+//
+// If the item of m, or its target for MoveAbove and MoveBelow, is in items but
+// not permitted, ApplyPermitted fails with a *PermissionError (matching
+// ErrNotPermitted). m.Expect is checked against the whole list.
+func (os *KeyedManager[T, ID]) ApplyPermitted(items []T, canMove func(T) bool, m Move[ID]) (Result[T], error) {
+	if err := os.check(items, m); err != nil {
+		return Result[T]{}, err
+	}
+	ids := []ID{m.ItemID}
+	if m.Kind == MoveAbove || m.Kind == MoveBelow {
+		ids = append(ids, m.TargetID)
+	}
+	for _, id := range ids {
+		if index, err := os.GetItemIndexByID(items, id); err == nil && !canMove(items[index]) {
+			return Result[T]{}, &PermissionError{Op: m.Kind.String(), ItemID: formatID(id)}
+		}
+	}
+
+	var slots []int
+	var permitted []T
+	before := make(map[ID]int, len(items))
+	for i, item := range items {
+		before[os.getID(item)] = os.getPos(item)
+		if canMove(item) {
+			slots = append(slots, i)
+			permitted = append(permitted, item)
+		}
+	}
+	m.Expect = Precondition[ID]{}
+	if _, err := os.Apply(permitted, m); err != nil {
+		return Result[T]{}, err
+	}
+
+	for i, slot := range slots {
+		items[slot] = permitted[i]
+	}
+	var result Result[T]
+	for i := range items {
+		id := os.getID(items[i])
+		position := os.opts.position(i)
+		if os.getPos(items[i]) != position {
+			os.setPos(&items[i], position)
+		}
+		if before[id] != position {
+			result.Affected = append(result.Affected, items[i])
+		}
+		if id == m.ItemID {
+			result.OldPosition, result.NewPosition = before[id], position
+		}
+	}
+	result.Changed = len(result.Affected) > 0
+	return result, nil
+}
+
+// ApplyPermitted is Apply for a user who may only reorder the items for which
+// canMove reports true, see KeyedManager.ApplyPermitted. Version conflicts
+// are retried as set with WithRetry, but not handed to the resolver set with
+// WithConflictResolver.
+func (pm *PersistentManager[T]) ApplyPermitted(ctx context.Context, listID string, canMove func(T) bool, m Move[string]) (PersistedResult[T], error) {
+	result, err := pm.applyRetried(ctx, listID, pm.checked(m, func(items []T) (Result[T], error) {
+		return pm.manager.ApplyPermitted(items, canMove, m)
+	}))
+	if err != nil {
+		return result, err
+	}
+	err = pm.applied(ctx, listID, m, &result)
+	return result, err
+}
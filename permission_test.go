@@ -0,0 +1,89 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mine(item *ordertest.Item) bool {
+	return strings.HasPrefix(item.ID, "mine")
+}
+
+func TestApplyPermitted(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("mine-1", "theirs-1", "mine-2", "theirs-2", "mine-3")
+
+	result, err := om.ApplyPermitted(items, mine, order.Move[string]{Kind: order.MoveTop, ItemID: "mine-3"})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "mine-3", "theirs-1", "mine-1", "theirs-2", "mine-2")
+	ordertest.AssertNormalized(t, items)
+	assert.Equal(t, 5, result.OldPosition)
+	assert.Equal(t, 1, result.NewPosition)
+	assert.Len(t, result.Affected, 3)
+
+	// Position counts among the permitted items.
+	_, err = om.ApplyPermitted(items, mine, order.Move[string]{Kind: order.MoveTo, ItemID: "mine-3", Position: 2})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "mine-1", "theirs-1", "mine-3", "theirs-2", "mine-2")
+}
+
+func TestApplyPermittedRejects(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("mine-1", "theirs-1", "mine-2")
+
+	for _, m := range []order.Move[string]{
+		{Kind: order.MoveTop, ItemID: "theirs-1"},
+		{Kind: order.MoveAbove, ItemID: "mine-2", TargetID: "theirs-1"},
+	} {
+		_, err := om.ApplyPermitted(items, mine, m)
+		var pe *order.PermissionError
+		require.True(t, errors.As(err, &pe))
+		assert.Equal(t, "theirs-1", pe.ItemID)
+		assert.ErrorIs(t, err, order.ErrNotPermitted)
+	}
+	_, err := om.ApplyPermitted(items, mine, order.Move[string]{Kind: order.MoveTop, ItemID: "missing"})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	ordertest.AssertOrder(t, items, "mine-1", "theirs-1", "mine-2")
+}
+
+func TestPersistentManagerApplyPermitted(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.ItemsWithIDs("theirs-1", "mine-1", "theirs-2", "mine-2"))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	result, err := pm.ApplyPermitted(ctx, "list", mine, order.Move[string]{Kind: order.MoveUp, ItemID: "mine-2"})
+	require.NoError(t, err)
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "mine-2", OldPosition: 4, NewPosition: 2},
+		{ItemID: "mine-1", OldPosition: 2, NewPosition: 4},
+	}, result.Changes)
+	assert.NotEmpty(t, result.OperationID)
+
+	items, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "theirs-1", "mine-2", "theirs-2", "mine-1")
+}
+
+func TestReconcileProtectedItems(t *testing.T) {
+	policy := order.ReconcilePolicy[*ordertest.Item]{CanMove: mine}
+
+	items := ordertest.ItemsWithIDs("mine-1", "theirs-1", "mine-2", "theirs-2")
+	_, err := order.Reconcile(items, []string{"theirs-1", "mine-2", "theirs-2", "mine-1"}, policy)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "theirs-1", "mine-2", "theirs-2", "mine-1")
+
+	_, err = order.Reconcile(items, []string{"theirs-2", "mine-2", "theirs-1", "mine-1"}, policy)
+	var pe *order.PermissionError
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, "theirs-1", pe.ItemID)
+	ordertest.AssertOrder(t, items, "theirs-1", "mine-2", "theirs-2", "mine-1")
+}
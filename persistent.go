@@ -0,0 +1,544 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PersistentManager applies moves to lists kept in a Store: it loads the list,
+// performs the move and saves the changed positions against the loaded version.
+//
+// With WithWriteBehind, lists are kept in memory after the first Load or Apply
+// and moves are applied there immediately, while the changed positions are
+// saved to the Store in the background. Call Close before shutting down so
+// that pending changes are saved.
+type PersistentManager[T Orderable] struct {
+	store   Store[T]
+	manager *OrderManager[T]
+	opts    persistentOptions
+
+	mu        sync.Mutex
+	lists     map[string]*cachedList[T]
+	stop      chan struct{}
+	closed    sync.WaitGroup
+	closeOnce sync.Once
+
+	// keys holds the idempotency keys of running ApplyIdempotent calls.
+	keysMu sync.Mutex
+	keys   map[string]chan struct{}
+
+	watchers watchers
+
+	// tenantID and check are set for the managers of a TenantManager.
+	tenantID string
+	check    func(items []T, m Move[string]) error
+}
+
+// cachedList is a list held in memory in write-behind mode.
+type cachedList[T Orderable] struct {
+	// flushing serializes the saves of the list, which run without holding
+	// mu so that moves do not wait for the Store.
+	flushing sync.Mutex
+	mu       sync.Mutex
+	items    []T
+	version  int64
+	// dirty holds the unsaved change of every item moved since the last save,
+	// in the order the items were first moved.
+	dirty   ChangeSet
+	dirtyAt map[string]int
+	// dead is set when a save conflicted and the list was dropped; callers
+	// holding it must load the list again.
+	dead bool
+}
+
+// PersistentOption configures a PersistentManager.
+type PersistentOption func(*persistentOptions)
+
+type persistentOptions struct {
+	flushInterval time.Duration
+	flushSize     int
+	flushErrors   func(listID string, err error)
+	resolver      ConflictResolver
+	healthLatency time.Duration
+	operationIDs  func() string
+	idempotency   IdempotencyStore
+	retry         RetryPolicy
+	observers     []func(context.Context, OrderChangedEvent)
+	audit         AuditStore
+}
+
+// WithWriteBehind keeps lists in memory and saves changed positions to the
+// Store every interval, or as soon as a list has maxPending unsaved changes.
+// A maxPending of 0 means no size limit, and interval must be positive.
+// Changes to the same item between two saves are merged into one.
+func WithWriteBehind(interval time.Duration, maxPending int) PersistentOption {
+	return func(o *persistentOptions) {
+		o.flushInterval = interval
+		o.flushSize = maxPending
+	}
+}
+
+// WithFlushErrorHandler reports errors of background saves in write-behind
+// mode. A list whose save fails with ErrVersionConflict was modified by
+// someone else; its unsaved changes are dropped and it is loaded again on
+// next use.
+func WithFlushErrorHandler(handle func(listID string, err error)) PersistentOption {
+	return func(o *persistentOptions) {
+		o.flushErrors = handle
+	}
+}
+
+// WithConflictResolver lets resolver decide what happens to moves that fail
+// because the list changed since it was loaded, instead of failing them; see
+// ConflictResolver.
+func WithConflictResolver(resolver ConflictResolver) PersistentOption {
+	return func(o *persistentOptions) {
+		o.resolver = resolver
+	}
+}
+
+// WithHealthLatency makes Health fail if the health check of the Store takes
+// longer than limit, so that a store that responds slowly is reported before it
+// starts failing requests.
+func WithHealthLatency(limit time.Duration) PersistentOption {
+	return func(o *persistentOptions) {
+		o.healthLatency = limit
+	}
+}
+
+// WithOperationIDs sets the function that generates the OperationID of every
+// applied move. IDs must be unique across the lists of the manager; the
+// default is a random UUID.
+func WithOperationIDs(next func() string) PersistentOption {
+	return func(o *persistentOptions) {
+		o.operationIDs = next
+	}
+}
+
+// WithIdempotencyStore sets where ApplyIdempotent remembers idempotency keys.
+// By default it uses the Store if it implements IdempotencyStore, and a
+// MemoryIdempotencyStore with DefaultIdempotencyTTL otherwise.
+func WithIdempotencyStore(store IdempotencyStore) PersistentOption {
+	return func(o *persistentOptions) {
+		o.idempotency = store
+	}
+}
+
+// PersistedResult is the Result of a move saved by a PersistentManager.
+type PersistedResult[T Orderable] struct {
+	Result[T]
+	// Changes holds the position updates made by the move.
+	Changes ChangeSet
+	// Version is the version of the list after the move. It is the loaded
+	// version if nothing changed. In write-behind mode it is the version of
+	// the last save, since the move has not been saved yet.
+	Version int64
+	// OperationID identifies the move, so that a client that applied it
+	// optimistically can match the acknowledgement to its pending move. It is
+	// empty if a conflict resolver dropped the move.
+	OperationID string
+	// Replayed is set when ApplyIdempotent returned the recorded result of an
+	// earlier call with the same key. Affected is empty then.
+	Replayed bool
+}
+
+// NewPersistentManager creates a PersistentManager that loads and saves lists
+// in store and moves items with manager.
+func NewPersistentManager[T Orderable](store Store[T], manager *OrderManager[T], opts ...PersistentOption) *PersistentManager[T] {
+	pm := &PersistentManager[T]{store: store, manager: manager, lists: make(map[string]*cachedList[T]), keys: make(map[string]chan struct{})}
+	pm.opts.operationIDs = uuid.NewString
+	for _, opt := range opts {
+		opt(&pm.opts)
+	}
+	if pm.opts.idempotency == nil {
+		if s, ok := lookup[IdempotencyStore](store); ok {
+			pm.opts.idempotency = s
+		} else {
+			pm.opts.idempotency = NewMemoryIdempotencyStore(DefaultIdempotencyTTL)
+		}
+	}
+	if pm.opts.audit == nil {
+		if s, ok := lookup[AuditStore](store); ok {
+			pm.opts.audit = s
+		}
+	}
+	if pm.opts.flushInterval > 0 {
+		pm.stop = make(chan struct{})
+		pm.closed.Add(1)
+		go pm.flushEvery(pm.opts.flushInterval)
+	}
+	return pm
+}
+
+// Load returns the items and version of a list. In write-behind mode it
+// returns a copy of the items held in memory, including unsaved moves.
+func (pm *PersistentManager[T]) Load(ctx context.Context, listID string) ([]T, int64, error) {
+	if pm.stop == nil {
+		return pm.store.Load(ctx, listID)
+	}
+	list, err := pm.locked(ctx, listID)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer list.mu.Unlock()
+	return append([]T(nil), list.items...), list.version, nil
+}
+
+// Apply loads a list, performs m and saves the changes. Moves that change
+// nothing are not saved. If the list was modified since it was loaded, Apply
+// fails with ErrVersionConflict and the caller may retry, unless a resolver
+// set with WithConflictResolver settles the conflict. In write-behind mode the
+// changes are saved later.
+func (pm *PersistentManager[T]) Apply(ctx context.Context, listID string, m Move[string]) (PersistedResult[T], error) {
+	return pm.applyWith(ctx, listID, m, pm.opts.retry, pm.opts.resolver)
+}
+
+// applyWith is Apply retrying with retry and resolving conflicts with
+// resolver, which may be nil, in place of the options of the manager.
+func (pm *PersistentManager[T]) applyWith(ctx context.Context, listID string, m Move[string], retry RetryPolicy, resolver ConflictResolver) (PersistedResult[T], error) {
+	// applied is the move that succeeded, which a resolver may have replaced.
+	applied := m
+	result, err := Retry(ctx, retry, func(ctx context.Context) (PersistedResult[T], error) {
+		return pm.applyOnce(ctx, listID, pm.mover(m))
+	})
+	if err != nil && resolver != nil {
+		var dropped bool
+		result, dropped, err = resolveConflicts(ctx, resolver, listID, m, err, func(m Move[string]) (PersistedResult[T], error) {
+			applied = m
+			return pm.applyOnce(ctx, listID, pm.mover(m))
+		})
+		if dropped {
+			_, result.Version, err = pm.Load(ctx, listID)
+			return result, err
+		}
+	}
+	if err != nil {
+		return result, err
+	}
+	err = pm.applied(ctx, listID, applied, &result)
+	return result, err
+}
+
+// mover returns a function that performs m on a list with the manager.
+func (pm *PersistentManager[T]) mover(m Move[string]) func([]T) (Result[T], error) {
+	return pm.checked(m, func(items []T) (Result[T], error) {
+		return pm.manager.Apply(items, m)
+	})
+}
+
+// checked runs the tenant check of m, if the manager has one, before move.
+func (pm *PersistentManager[T]) checked(m Move[string], move func([]T) (Result[T], error)) func([]T) (Result[T], error) {
+	if pm.check == nil {
+		return move
+	}
+	return func(items []T) (Result[T], error) {
+		if err := pm.check(items, m); err != nil {
+			return Result[T]{}, err
+		}
+		return move(items)
+	}
+}
+
+// applyRetried is applyOnce, retried on version conflicts as set with WithRetry.
+func (pm *PersistentManager[T]) applyRetried(ctx context.Context, listID string, move func([]T) (Result[T], error)) (PersistedResult[T], error) {
+	return Retry(ctx, pm.opts.retry, func(ctx context.Context) (PersistedResult[T], error) {
+		return pm.applyOnce(ctx, listID, move)
+	})
+}
+
+func (pm *PersistentManager[T]) applyOnce(ctx context.Context, listID string, move func([]T) (Result[T], error)) (PersistedResult[T], error) {
+	if pm.stop != nil {
+		return pm.applyBehind(ctx, listID, move)
+	}
+	return pm.apply(ctx, listID, move)
+}
+
+// apply performs move on a freshly loaded list.
+func (pm *PersistentManager[T]) apply(ctx context.Context, listID string, move func([]T) (Result[T], error)) (PersistedResult[T], error) {
+	items, version, err := pm.store.Load(ctx, listID)
+	if err != nil {
+		return PersistedResult[T]{}, err
+	}
+	before := positionsByID(items)
+	result, err := move(items)
+	if err != nil {
+		return PersistedResult[T]{}, err
+	}
+	persisted := PersistedResult[T]{Result: result, Version: version}
+	if !result.Changed {
+		return persisted, nil
+	}
+	persisted.Changes = changesOf(result.Affected, before)
+	persisted.Version, err = pm.store.SavePositions(ctx, listID, version, persisted.Changes)
+	if err != nil {
+		return PersistedResult[T]{}, fmt.Errorf("Apply %s: %w", listID, err)
+	}
+	return persisted, nil
+}
+
+func (pm *PersistentManager[T]) applyBehind(ctx context.Context, listID string, move func([]T) (Result[T], error)) (PersistedResult[T], error) {
+	list, err := pm.locked(ctx, listID)
+	if err != nil {
+		return PersistedResult[T]{}, err
+	}
+	before := positionsByID(list.items)
+	result, err := move(list.items)
+	if err != nil {
+		list.mu.Unlock()
+		return PersistedResult[T]{}, err
+	}
+	persisted := PersistedResult[T]{Result: result, Changes: changesOf(result.Affected, before), Version: list.version}
+	list.record(persisted.Changes)
+	full := pm.opts.flushSize > 0 && len(list.dirty) >= pm.opts.flushSize
+	list.mu.Unlock()
+
+	if full {
+		if err := pm.flushList(ctx, listID, list); err != nil {
+			pm.reportFlushError(listID, err)
+		}
+	}
+	return persisted, nil
+}
+
+// record merges changes into the unsaved changes of the list.
+func (l *cachedList[T]) record(changes ChangeSet) {
+	for _, change := range changes {
+		if i, ok := l.dirtyAt[change.ItemID]; ok {
+			l.dirty[i].NewPosition = change.NewPosition
+			continue
+		}
+		l.dirtyAt[change.ItemID] = len(l.dirty)
+		l.dirty = append(l.dirty, change)
+	}
+}
+
+// restore puts back changes that failed to save in front of the changes
+// recorded since, keeping the newer position of an item moved again.
+func (l *cachedList[T]) restore(changes ChangeSet) {
+	for _, change := range changes {
+		if i, ok := l.dirtyAt[change.ItemID]; ok {
+			l.dirty[i].OldPosition = change.OldPosition
+			continue
+		}
+		l.dirtyAt[change.ItemID] = len(l.dirty)
+		l.dirty = append(l.dirty, change)
+	}
+}
+
+// cached returns the in-memory copy of a list, loading it on first use. The
+// list is loaded without holding pm.mu; if another call loaded it meanwhile,
+// that copy is kept.
+func (pm *PersistentManager[T]) cached(ctx context.Context, listID string) (*cachedList[T], error) {
+	pm.mu.Lock()
+	list, ok := pm.lists[listID]
+	pm.mu.Unlock()
+	if ok {
+		return list, nil
+	}
+	items, version, err := pm.store.Load(ctx, listID)
+	if err != nil {
+		return nil, err
+	}
+	pm.mu.Lock()
+	defer pm.mu.Unlock()
+	if list, ok := pm.lists[listID]; ok {
+		return list, nil
+	}
+	list = &cachedList[T]{items: items, version: version, dirtyAt: make(map[string]int)}
+	pm.lists[listID] = list
+	return list, nil
+}
+
+// locked returns the in-memory copy of a list with its mu held. A copy dropped
+// after a conflicting save is replaced by a fresh load.
+func (pm *PersistentManager[T]) locked(ctx context.Context, listID string) (*cachedList[T], error) {
+	for {
+		list, err := pm.cached(ctx, listID)
+		if err != nil {
+			return nil, err
+		}
+		list.mu.Lock()
+		if !list.dead {
+			return list, nil
+		}
+		list.mu.Unlock()
+	}
+}
+
+// Flush saves the unsaved changes of every list in write-behind mode. It
+// returns the errors of all lists that could not be saved, joined.
+func (pm *PersistentManager[T]) Flush(ctx context.Context) error {
+	pm.mu.Lock()
+	lists := make(map[string]*cachedList[T], len(pm.lists))
+	for listID, list := range pm.lists {
+		lists[listID] = list
+	}
+	pm.mu.Unlock()
+
+	var errs []error
+	for listID, list := range lists {
+		if err := pm.flushList(ctx, listID, list); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// flushList saves the unsaved changes of list. It takes them under list.mu
+// but saves them without it, so moves made during the save are recorded for
+// the next one.
+func (pm *PersistentManager[T]) flushList(ctx context.Context, listID string, list *cachedList[T]) error {
+	list.flushing.Lock()
+	defer list.flushing.Unlock()
+
+	list.mu.Lock()
+	if list.dead {
+		list.mu.Unlock()
+		return nil
+	}
+	var changes ChangeSet
+	for _, change := range list.dirty {
+		if change.OldPosition != change.NewPosition {
+			changes = append(changes, change)
+		}
+	}
+	list.dirty, list.dirtyAt = nil, make(map[string]int)
+	version := list.version
+	list.mu.Unlock()
+	if len(changes) == 0 {
+		return nil
+	}
+
+	version, err := pm.store.SavePositions(ctx, listID, version, changes)
+	if err != nil {
+		if errors.Is(err, ErrVersionConflict) {
+			// The list is marked dead and dropped together, under list.mu,
+			// so that no move is recorded in it once it is dropped.
+			list.mu.Lock()
+			list.dead = true
+			pm.mu.Lock()
+			if pm.lists[listID] == list {
+				delete(pm.lists, listID)
+			}
+			pm.mu.Unlock()
+			list.mu.Unlock()
+		} else {
+			list.mu.Lock()
+			list.restore(changes)
+			list.mu.Unlock()
+		}
+		return fmt.Errorf("Flush %s: %w", listID, err)
+	}
+	list.mu.Lock()
+	list.version = version
+	list.mu.Unlock()
+	return nil
+}
+
+func (pm *PersistentManager[T]) flushEvery(interval time.Duration) {
+	defer pm.closed.Done()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			pm.flushBackground()
+		case <-pm.stop:
+			return
+		}
+	}
+}
+
+func (pm *PersistentManager[T]) flushBackground() {
+	pm.mu.Lock()
+	listIDs := make([]string, 0, len(pm.lists))
+	for listID := range pm.lists {
+		listIDs = append(listIDs, listID)
+	}
+	pm.mu.Unlock()
+	for _, listID := range listIDs {
+		pm.mu.Lock()
+		list, ok := pm.lists[listID]
+		pm.mu.Unlock()
+		if !ok {
+			continue
+		}
+		if err := pm.flushList(context.Background(), listID, list); err != nil {
+			pm.reportFlushError(listID, err)
+		}
+	}
+}
+
+func (pm *PersistentManager[T]) reportFlushError(listID string, err error) {
+	if pm.opts.flushErrors != nil {
+		pm.opts.flushErrors(listID, err)
+	}
+}
+
+// Close stops background saving and saves all pending changes. Without
+// WithWriteBehind it does nothing. The manager must not be used afterwards,
+// but calling Close again is safe and only saves what is still pending.
+func (pm *PersistentManager[T]) Close(ctx context.Context) error {
+	if pm.stop == nil {
+		return nil
+	}
+	pm.closeOnce.Do(func() { close(pm.stop) })
+	pm.closed.Wait()
+	return pm.Flush(ctx)
+}
+
+// Health reports whether the manager can serve requests, for health and
+// readiness endpoints. It fails with ErrUnhealthy after Close in write-behind
+// mode, if the Store, or a store it wraps (see Wrapper), implements
+// HealthChecker and its check fails, or if the check takes longer than set by
+// WithHealthLatency. Stores without a health check are assumed to be healthy.
+func (pm *PersistentManager[T]) Health(ctx context.Context) error {
+	if pm.stop != nil {
+		select {
+		case <-pm.stop:
+			return fmt.Errorf("Health: %w: manager closed", ErrUnhealthy)
+		default:
+		}
+	}
+	checker, ok := lookup[HealthChecker](pm.store)
+	if !ok {
+		return nil
+	}
+	if pm.opts.healthLatency > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, pm.opts.healthLatency)
+		defer cancel()
+	}
+	start := time.Now()
+	if err := checker.Health(ctx); err != nil {
+		return fmt.Errorf("Health: %w: %w", ErrUnhealthy, err)
+	}
+	if elapsed := time.Since(start); pm.opts.healthLatency > 0 && elapsed > pm.opts.healthLatency {
+		return fmt.Errorf("Health: %w: check took %v, more than %v", ErrUnhealthy, elapsed, pm.opts.healthLatency)
+	}
+	return nil
+}
+
+// positionsByID records the positions of items before an operation.
+func positionsByID[T Orderable](items []T) map[string]int {
+	positions := make(map[string]int, len(items))
+	for _, item := range items {
+		positions[item.GetID()] = item.GetPosition()
+	}
+	return positions
+}
+
+// changesOf turns the Affected items of a Result into a ChangeSet, using the
+// positions recorded before the operation.
+func changesOf[T Orderable](affected []T, before map[string]int) ChangeSet {
+	changes := make(ChangeSet, len(affected))
+	for i, item := range affected {
+		changes[i] = PositionChange{ItemID: item.GetID(), OldPosition: before[item.GetID()], NewPosition: item.GetPosition()}
+	}
+	return changes
+}
@@ -0,0 +1,319 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPersistentManagerApply(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	result, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), result.Version)
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "item-3", OldPosition: 3, NewPosition: 1},
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+		{ItemID: "item-2", OldPosition: 2, NewPosition: 3},
+	}, result.Changes)
+
+	items, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-3", "item-1", "item-2")
+
+	result, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.False(t, result.Changed)
+	assert.Equal(t, 1, store.Saves())
+
+	_, err = pm.Apply(ctx, "missing", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+}
+
+func TestPersistentManagerOperationIDs(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	first, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	second, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, first.OperationID)
+	assert.NotEmpty(t, second.OperationID)
+	assert.NotEqual(t, first.OperationID, second.OperationID)
+
+	failed, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "missing"})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	assert.Empty(t, failed.OperationID)
+
+	pm = order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithOperationIDs(func() string { return "op" }))
+	result, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveDown, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.Equal(t, "op", result.OperationID)
+}
+
+func TestPersistentManagerWriteBehind(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithWriteBehind(time.Hour, 0))
+
+	for _, m := range []order.Move[string]{
+		{Kind: order.MoveTop, ItemID: "item-3"},
+		{Kind: order.MoveBottom, ItemID: "item-1"},
+		{Kind: order.MoveTop, ItemID: "item-2"},
+	} {
+		result, err := pm.Apply(ctx, "list", m)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Version)
+	}
+	assert.Equal(t, 0, store.Saves())
+
+	items, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-2", "item-3", "item-1")
+
+	require.NoError(t, pm.Close(ctx))
+	assert.Equal(t, 1, store.Saves())
+	assert.Equal(t, int64(2), store.Version("list"))
+
+	stored, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, stored, "item-2", "item-3", "item-1")
+}
+
+func TestPersistentManagerWriteBehindFlushesFullBatches(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(4))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithWriteBehind(time.Hour, 2))
+	defer pm.Close(ctx)
+
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.Saves())
+
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, store.Saves())
+}
+
+func TestPersistentManagerWriteBehindInterval(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithWriteBehind(5*time.Millisecond, 0))
+	defer pm.Close(ctx)
+
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool { return store.Saves() == 1 }, time.Second, time.Millisecond)
+}
+
+func TestPersistentManagerWriteBehindConflict(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	var flushErr error
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](),
+		order.WithWriteBehind(time.Hour, 1),
+		order.WithFlushErrorHandler(func(listID string, err error) { flushErr = err }))
+	defer pm.Close(ctx)
+
+	_, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	_, err = store.SavePositions(ctx, "list", 1, nil)
+	require.NoError(t, err)
+
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.ErrorIs(t, flushErr, order.ErrVersionConflict)
+
+	_, version, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+}
+
+func TestPersistentManagerWriteBehindConflictDuringMoves(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(5))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](),
+		order.WithWriteBehind(time.Hour, 1),
+		order.WithFlushErrorHandler(func(string, error) {}))
+	defer pm.Close(ctx)
+
+	var wg sync.WaitGroup
+	for i := range 4 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for range 50 {
+				_, _ = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: fmt.Sprintf("item-%d", i+1)})
+			}
+		}()
+	}
+	for range 20 {
+		_, version, err := store.Load(ctx, "list")
+		require.NoError(t, err)
+		_, _ = store.SavePositions(ctx, "list", version, nil)
+	}
+	wg.Wait()
+
+	require.NoError(t, pm.Flush(ctx))
+	_, version, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	_, saved, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, saved, version)
+}
+
+// slowSaveStore holds SavePositions until release is closed.
+type slowSaveStore struct {
+	*ordertest.Store[*ordertest.Item]
+	saving  chan struct{}
+	release chan struct{}
+}
+
+func (s *slowSaveStore) SavePositions(ctx context.Context, listID string, version int64, changes order.ChangeSet) (int64, error) {
+	s.saving <- struct{}{}
+	<-s.release
+	return s.Store.SavePositions(ctx, listID, version, changes)
+}
+
+func TestPersistentManagerWriteBehindMovesDuringFlush(t *testing.T) {
+	ctx := context.Background()
+	backend := ordertest.NewStore[*ordertest.Item]()
+	backend.Put("list", ordertest.Items(3))
+	store := &slowSaveStore{Store: backend, saving: make(chan struct{}, 1), release: make(chan struct{})}
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithWriteBehind(time.Hour, 0))
+
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	flushed := make(chan error)
+	go func() { flushed <- pm.Flush(ctx) }()
+	<-store.saving
+
+	// The move does not wait for the save and is kept for the next one.
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveBottom, ItemID: "item-3"})
+	require.NoError(t, err)
+	close(store.release)
+	require.NoError(t, <-flushed)
+	require.NoError(t, pm.Close(ctx))
+
+	stored, version, err := backend.Load(ctx, "list")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), version)
+	ordertest.AssertOrder(t, stored, "item-1", "item-2", "item-3")
+}
+
+// healthStore is a Store with a programmable health check.
+type healthStore struct {
+	*ordertest.Store[*ordertest.Item]
+	check func(ctx context.Context) error
+}
+
+func (s *healthStore) Health(ctx context.Context) error {
+	return s.check(ctx)
+}
+
+func TestPersistentManagerHealth(t *testing.T) {
+	ctx := context.Background()
+	om := order.NewOrderManager[*ordertest.Item]()
+
+	// Stores without a health check count as healthy.
+	assert.NoError(t, order.NewPersistentManager(ordertest.NewStore[*ordertest.Item](), om).Health(ctx))
+
+	down := errors.New("connection refused")
+	store := &healthStore{Store: ordertest.NewStore[*ordertest.Item](), check: func(context.Context) error { return nil }}
+	pm := order.NewPersistentManager[*ordertest.Item](store, om)
+	assert.NoError(t, pm.Health(ctx))
+	store.check = func(context.Context) error { return down }
+	err := pm.Health(ctx)
+	assert.ErrorIs(t, err, order.ErrUnhealthy)
+	assert.ErrorIs(t, err, down)
+
+	store.check = func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	pm = order.NewPersistentManager[*ordertest.Item](store, om, order.WithHealthLatency(time.Millisecond))
+	err = pm.Health(ctx)
+	assert.ErrorIs(t, err, order.ErrUnhealthy)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	store.check = func(context.Context) error {
+		time.Sleep(5 * time.Millisecond)
+		return nil
+	}
+	err = pm.Health(ctx)
+	assert.ErrorIs(t, err, order.ErrUnhealthy)
+}
+
+func TestPersistentManagerHealthAfterClose(t *testing.T) {
+	ctx := context.Background()
+	pm := order.NewPersistentManager(ordertest.NewStore[*ordertest.Item](), order.NewOrderManager[*ordertest.Item](), order.WithWriteBehind(time.Hour, 0))
+	assert.NoError(t, pm.Health(ctx))
+	require.NoError(t, pm.Close(ctx))
+	assert.ErrorIs(t, pm.Health(ctx), order.ErrUnhealthy)
+}
+
+func TestPersistentManagerCloseTwice(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithWriteBehind(time.Hour, 0))
+
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	require.NoError(t, pm.Close(ctx))
+	require.NoError(t, pm.Close(ctx))
+	assert.Equal(t, 1, store.Saves())
+}
+
+func TestPersistentManagerHealthThroughWrappers(t *testing.T) {
+	ctx := context.Background()
+	om := order.NewOrderManager[*ordertest.Item]()
+	down := errors.New("connection refused")
+	store := &healthStore{Store: ordertest.NewStore[*ordertest.Item](), check: func(context.Context) error { return down }}
+
+	cached := order.NewCachingStore[*ordertest.Item](store, order.NewMemoryCache[*ordertest.Item](), 0)
+	assert.ErrorIs(t, order.NewPersistentManager[*ordertest.Item](cached, om).Health(ctx), down)
+	tenant := order.ForTenant(order.Namespaced[*ordertest.Item](cached), "acme")
+	assert.ErrorIs(t, order.NewPersistentManager(tenant, om).Health(ctx), down)
+}
+
+// auditingStore is a Store that keeps its own audit trail.
+type auditingStore struct {
+	*ordertest.Store[*ordertest.Item]
+	*order.MemoryAuditStore
+}
+
+func TestPersistentManagerFindsAuditStoreThroughWrappers(t *testing.T) {
+	ctx := context.Background()
+	store := auditingStore{ordertest.NewStore[*ordertest.Item](), order.NewMemoryAuditStore()}
+	store.Put("list", ordertest.Items(3))
+	cached := order.NewCachingStore[*ordertest.Item](store, order.NewMemoryCache[*ordertest.Item](), 0)
+	pm := order.NewPersistentManager[*ordertest.Item](cached, order.NewOrderManager[*ordertest.Item]())
+
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	events, err := store.Query(ctx, order.AuditQuery{})
+	require.NoError(t, err)
+	assert.Len(t, events, 1)
+}
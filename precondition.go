@@ -0,0 +1,85 @@
+package order
+
+import "strconv"
+
+// Precondition describes what a client expected the list to look like around
+// the moved item when it rendered it. If the list changed since, the move
+// fails with a *PreconditionError (matching ErrPreconditionFailed) instead of
+// being applied to an order the user has not seen. Unlike a version check it
+// only fails when the neighborhood of the item changed, not on every change
+// to the list.
+//
+// Zero IDs and a nil Position are not checked. Position is a pointer so that
+// any position can be expected, including 0 under WithIndexBase(0). To expect
+// the item at the top, where it has no previous item, set Position.
+// Soft-deleted items are skipped when looking for neighbors.
+type Precondition[ID comparable] struct {
+	// PrevID is the ID of the item expected directly above the item.
+	PrevID ID
+	// NextID is the ID of the item expected directly below the item.
+	NextID ID
+	// Position is the expected current position of the item.
+	Position *int
+}
+
+// IsZero reports whether p checks nothing.
+func (p Precondition[ID]) IsZero() bool {
+	return p == Precondition[ID]{}
+}
+
+// Equal reports whether p and q expect the same, comparing Position by value.
+func (p Precondition[ID]) Equal(q Precondition[ID]) bool {
+	if p.PrevID != q.PrevID || p.NextID != q.NextID || (p.Position == nil) != (q.Position == nil) {
+		return false
+	}
+	return p.Position == nil || *p.Position == *q.Position
+}
+
+// check returns a *PreconditionError if m.Expect does not hold in items. An
+// item that is not found is left to the move to report.
+func (os *KeyedManager[T, ID]) check(items []T, m Move[ID]) error {
+	p := m.Expect
+	if p.IsZero() {
+		return nil
+	}
+	index, err := os.GetItemIndexByID(items, m.ItemID)
+	if err != nil {
+		return nil
+	}
+	op := m.Kind.String()
+	var zero ID
+	if p.Position != nil && os.getPos(items[index]) != *p.Position {
+		return &PreconditionError{Op: op, ItemID: formatID(m.ItemID), Field: "Position", Expected: strconv.Itoa(*p.Position), Actual: strconv.Itoa(os.getPos(items[index]))}
+	}
+	if p.PrevID != zero {
+		if prev := os.neighbor(items, index, -1); prev != p.PrevID {
+			return &PreconditionError{Op: op, ItemID: formatID(m.ItemID), Field: "PrevID", Expected: formatID(p.PrevID), Actual: os.formatNeighbor(prev)}
+		}
+	}
+	if p.NextID != zero {
+		if next := os.neighbor(items, index, 1); next != p.NextID {
+			return &PreconditionError{Op: op, ItemID: formatID(m.ItemID), Field: "NextID", Expected: formatID(p.NextID), Actual: os.formatNeighbor(next)}
+		}
+	}
+	return nil
+}
+
+// neighbor returns the ID of the first item that is not soft-deleted from
+// index in direction step, or the zero ID if there is none.
+func (os *KeyedManager[T, ID]) neighbor(items []T, index, step int) ID {
+	for i := index + step; i >= 0 && i < len(items); i += step {
+		if !isDeleted(items[i]) {
+			return os.getID(items[i])
+		}
+	}
+	var zero ID
+	return zero
+}
+
+func (os *KeyedManager[T, ID]) formatNeighbor(id ID) string {
+	var zero ID
+	if id == zero {
+		return ""
+	}
+	return formatID(id)
+}
@@ -0,0 +1,91 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestApplyPrecondition(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c")
+
+	tests := []struct {
+		name   string
+		expect order.Precondition[string]
+		field  string
+	}{
+		{"neighbors", order.Precondition[string]{PrevID: "a", NextID: "c"}, ""},
+		{"position", order.Precondition[string]{Position: ptr(2)}, ""},
+		{"wrong previous", order.Precondition[string]{PrevID: "c"}, "PrevID"},
+		{"wrong next", order.Precondition[string]{NextID: "a"}, "NextID"},
+		{"wrong position", order.Precondition[string]{Position: ptr(1)}, "Position"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := ordertest.ItemsWithIDs("a", "b", "c")
+			_, err := om.Apply(items, order.Move[string]{Kind: order.MoveBottom, ItemID: "b", Expect: tt.expect})
+			if tt.field == "" {
+				require.NoError(t, err)
+				ordertest.AssertOrder(t, items, "a", "c", "b")
+				return
+			}
+			var pe *order.PreconditionError
+			require.True(t, errors.As(err, &pe))
+			assert.ErrorIs(t, err, order.ErrPreconditionFailed)
+			assert.Equal(t, tt.field, pe.Field)
+			ordertest.AssertOrder(t, items, "a", "b", "c")
+		})
+	}
+
+	// The first item has no previous item; Position expects it at the top.
+	_, err := om.Apply(items, order.Move[string]{Kind: order.MoveDown, ItemID: "a", Expect: order.Precondition[string]{PrevID: "b"}})
+	var pe *order.PreconditionError
+	require.True(t, errors.As(err, &pe))
+	assert.Equal(t, "", pe.Actual)
+	_, err = om.Apply(items, order.Move[string]{Kind: order.MoveDown, ItemID: "a", Expect: order.Precondition[string]{Position: ptr(1), NextID: "b"}})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "b", "a", "c")
+}
+
+func TestPersistentManagerPrecondition(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.ItemsWithIDs("a", "b", "c"))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	// The client rendered b below a, then someone else moved c to the top.
+	rendered := order.Precondition[string]{PrevID: "a", NextID: "c"}
+	_, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "c"})
+	require.NoError(t, err)
+
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "b", Expect: rendered})
+	assert.ErrorIs(t, err, order.ErrPreconditionFailed)
+	assert.Equal(t, 1, store.Saves())
+
+	// A change elsewhere in the list does not fail the move.
+	_, err = pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveBottom, ItemID: "b", Expect: order.Precondition[string]{PrevID: "a"}})
+	require.NoError(t, err)
+}
+
+func TestPreconditionPositionZero(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item](order.WithIndexBase(0))
+	items := ordertest.ItemsWithIDs("a", "b", "c")
+	om.NormalizePositions(items)
+
+	_, err := om.Apply(items, order.Move[string]{Kind: order.MoveBottom, ItemID: "b", Expect: order.Precondition[string]{Position: ptr(0)}})
+	assert.ErrorIs(t, err, order.ErrPreconditionFailed, "b is at 1, not 0")
+	_, err = om.Apply(items, order.Move[string]{Kind: order.MoveBottom, ItemID: "a", Expect: order.Precondition[string]{Position: ptr(0)}})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "b", "c", "a")
+}
@@ -0,0 +1,33 @@
+package order
+
+// Propose previews a move: it performs m on a copy of items and returns the
+// reordered copy together with the position changes, leaving items and the
+// items in it untouched. A UI can render the proposed order and commit the
+// move in a second step, for example with Apply or a PersistentManager.
+//
+// Items are copied with their Clone method if they implement Cloner. Pointers
+// are otherwise copied shallowly, that is the pointed-to values are copied but
+// not what they point to, so items keeping their position behind a further
+// pointer must implement Cloner. The preview is not reported to metrics or
+// the logger.
+func (os *KeyedManager[T, ID]) Propose(items []T, m Move[ID]) ([]T, ChangeSet, error) {
+	proposed := make([]T, len(items))
+	before := make(map[ID]int, len(items))
+	for i, item := range items {
+		proposed[i] = copyItem(item)
+		before[os.getID(item)] = os.getPos(item)
+	}
+
+	preview := *os
+	preview.opts.metrics, preview.opts.logger = nil, nil
+	result, err := preview.Apply(proposed, m)
+	if err != nil {
+		return nil, nil, err
+	}
+	changes := make(ChangeSet, len(result.Affected))
+	for i, item := range result.Affected {
+		id := os.getID(item)
+		changes[i] = PositionChange{ItemID: formatID(id), OldPosition: before[id], NewPosition: os.getPos(item)}
+	}
+	return proposed, changes, nil
+}
@@ -0,0 +1,52 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPropose(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.Items(4)
+
+	proposed, changes, err := om.Propose(items, order.Move[string]{Kind: order.MoveAbove, ItemID: "item-4", TargetID: "item-2"})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, proposed, "item-1", "item-4", "item-2", "item-3")
+	ordertest.AssertNormalized(t, proposed)
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "item-4", OldPosition: 4, NewPosition: 2},
+		{ItemID: "item-2", OldPosition: 2, NewPosition: 3},
+		{ItemID: "item-3", OldPosition: 3, NewPosition: 4},
+	}, changes)
+
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3", "item-4")
+	ordertest.AssertNormalized(t, items)
+	assert.NotSame(t, items[3], proposed[1])
+
+	_, _, err = om.Propose(items, order.Move[string]{Kind: order.MoveTop, ItemID: "gone"})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
+
+func TestProposeValuesAndTagged(t *testing.T) {
+	values := []plainItem{{"a", 1}, {"b", 2}, {"c", 3}}
+	proposed, changes, err := newPlainManager().Propose(values, order.Move[string]{Kind: order.MoveBottom, ItemID: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, []plainItem{{"b", 1}, {"c", 2}, {"a", 3}}, proposed)
+	assert.Len(t, changes, 3)
+	assert.Equal(t, []plainItem{{"a", 1}, {"b", 2}, {"c", 3}}, values)
+
+	models := []*GeneratedModel{{Key: uuid.New(), Rank: 1}, {Key: uuid.New(), Rank: 2}}
+	tagged, err := order.Tag(models)
+	require.NoError(t, err)
+	om := order.NewOrderManager[order.Tagged[GeneratedModel]]()
+	proposedTagged, _, err := om.Propose(tagged, order.Move[string]{Kind: order.MoveTop, ItemID: models[1].Key.String()})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), order.Untag(proposedTagged)[0].Rank)
+	assert.Equal(t, int32(2), models[1].Rank, "the wrapped struct is copied")
+}
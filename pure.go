@@ -0,0 +1,60 @@
+package order
+
+import "slices"
+
+// Moved returns a copy of items with m performed on it.
+//
+// Moved, Inserted, Removed and Normalized are pure counterparts of the verbs,
+// for code that treats lists as immutable values: they never modify the slice
+// or the items passed in and return a new slice of copies, made as by
+// Propose, with normalized positions. Since inputs are only read, they are
+// safe to call concurrently on the same list.
+func Moved[T Orderable](items []T, m Move[string]) ([]T, error) {
+	moved, _, err := NewOrderManager[T]().Propose(items, m)
+	return moved, err
+}
+
+// Inserted returns a copy of items with a copy of item added at the 1-based
+// position, shifting later items down. A position one past the last item
+// appends. It fails with a *PositionError for other positions and with a
+// *DuplicateIDError if the ID of item is already in items.
+func Inserted[T Orderable](items []T, item T, position int) ([]T, error) {
+	if position < 1 || position > len(items)+1 {
+		return nil, &PositionError{Op: "Inserted", Requested: position, Min: 1, Max: len(items) + 1}
+	}
+	if i := slices.IndexFunc(items, func(other T) bool { return other.GetID() == item.GetID() }); i >= 0 {
+		return nil, &DuplicateIDError{Op: "Inserted", ItemID: item.GetID(), FirstIndex: i, SecondIndex: position - 1}
+	}
+	inserted := copyItems(items, 1)
+	inserted = slices.Insert(inserted, position-1, copyItem(item))
+	renumber(inserted)
+	return inserted, nil
+}
+
+// Removed returns a copy of items without the item with itemID. It fails with
+// a *NotFoundError if there is no such item.
+func Removed[T Orderable](items []T, itemID string) ([]T, error) {
+	i := slices.IndexFunc(items, func(item T) bool { return item.GetID() == itemID })
+	if i < 0 {
+		return nil, &NotFoundError{Op: "Removed", ItemID: itemID}
+	}
+	removed := copyItems(slices.Delete(slices.Clone(items), i, i+1), 0)
+	renumber(removed)
+	return removed, nil
+}
+
+// Normalized returns a copy of items with positions 1..n in slice order.
+func Normalized[T Orderable](items []T) []T {
+	normalized := copyItems(items, 0)
+	renumber(normalized)
+	return normalized
+}
+
+// copyItems copies every item into a new slice with room for extra more.
+func copyItems[T any](items []T, extra int) []T {
+	copies := make([]T, len(items), len(items)+extra)
+	for i, item := range items {
+		copies[i] = copyItem(item)
+	}
+	return copies
+}
@@ -0,0 +1,66 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMoved(t *testing.T) {
+	items := ordertest.Items(3)
+
+	moved, err := order.Moved(items, order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, moved, "item-3", "item-1", "item-2")
+	ordertest.AssertNormalized(t, moved)
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3")
+	ordertest.AssertNormalized(t, items)
+	assert.NotSame(t, items[2], moved[0])
+
+	_, err = order.Moved(items, order.Move[string]{Kind: order.MoveTop, ItemID: "missing"})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+}
+
+func TestInserted(t *testing.T) {
+	items := ordertest.Items(2)
+	item := &ordertest.Item{ID: "new"}
+
+	inserted, err := order.Inserted(items, item, 1)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, inserted, "new", "item-1", "item-2")
+	ordertest.AssertNormalized(t, inserted)
+	assert.Zero(t, item.Position)
+	ordertest.AssertNormalized(t, items)
+
+	appended, err := order.Inserted(items, item, 3)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, appended, "item-1", "item-2", "new")
+
+	_, err = order.Inserted(items, item, 4)
+	assert.ErrorIs(t, err, order.ErrInvalidPosition)
+	_, err = order.Inserted(items, &ordertest.Item{ID: "item-1"}, 1)
+	assert.ErrorIs(t, err, order.ErrDuplicateID)
+}
+
+func TestRemovedAndNormalized(t *testing.T) {
+	items := ordertest.Items(3)
+
+	removed, err := order.Removed(items, "item-1")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, removed, "item-2", "item-3")
+	ordertest.AssertNormalized(t, removed)
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3")
+	ordertest.AssertNormalized(t, items)
+
+	_, err = order.Removed(items, "missing")
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+
+	items[1].Position = 10
+	normalized := order.Normalized(items)
+	ordertest.AssertNormalized(t, normalized)
+	assert.Equal(t, 10, items[1].Position)
+}
@@ -0,0 +1,135 @@
+package order
+
+import (
+	"context"
+	"sync"
+)
+
+// Queue applies moves submitted from many goroutines to lists in a Store. Moves
+// on the same list are applied one at a time in submission order, so they
+// never race on the list version; moves on different lists run concurrently.
+// Each list is drained by its own goroutine, which exits once the list has no
+// pending moves.
+type Queue[T Orderable] struct {
+	manager *PersistentManager[T]
+
+	mu      sync.Mutex
+	lists   map[string]*listQueue[T]
+	closed  bool
+	running sync.WaitGroup
+}
+
+type listQueue[T Orderable] struct {
+	pending []*queuedMove[T]
+}
+
+type queuedMove[T Orderable] struct {
+	ctx    context.Context
+	move   Move[string]
+	future *Future[T]
+}
+
+// Future is the pending result of a move submitted to a Queue.
+type Future[T Orderable] struct {
+	done   chan struct{}
+	result PersistedResult[T]
+	err    error
+}
+
+// Done returns a channel that is closed once the move has been applied or has
+// failed.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Wait blocks until the move has been applied or ctx is done. Giving up on
+// the wait does not cancel the move.
+func (f *Future[T]) Wait(ctx context.Context) (PersistedResult[T], error) {
+	select {
+	case <-f.done:
+		return f.result, f.err
+	case <-ctx.Done():
+		return PersistedResult[T]{}, ctx.Err()
+	}
+}
+
+func (f *Future[T]) resolve(result PersistedResult[T], err error) {
+	f.result, f.err = result, err
+	close(f.done)
+}
+
+// NewQueue creates a Queue that applies moves with manager.
+func NewQueue[T Orderable](manager *PersistentManager[T]) *Queue[T] {
+	return &Queue[T]{manager: manager, lists: make(map[string]*listQueue[T])}
+}
+
+// Submit queues m for the list and returns immediately. ctx is passed to the
+// Store when the move runs; a move whose ctx is done by then fails with the
+// context's error without being applied. After Close, Submit returns a Future
+// that fails with ErrQueueClosed.
+func (q *Queue[T]) Submit(ctx context.Context, listID string, m Move[string]) *Future[T] {
+	future := &Future[T]{done: make(chan struct{})}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		future.resolve(PersistedResult[T]{}, ErrQueueClosed)
+		return future
+	}
+	list, ok := q.lists[listID]
+	if !ok {
+		list = &listQueue[T]{}
+		q.lists[listID] = list
+		q.running.Add(1)
+		go q.drain(listID, list)
+	}
+	list.pending = append(list.pending, &queuedMove[T]{ctx: ctx, move: m, future: future})
+	return future
+}
+
+// Apply submits m and waits for its result.
+func (q *Queue[T]) Apply(ctx context.Context, listID string, m Move[string]) (PersistedResult[T], error) {
+	return q.Submit(ctx, listID, m).Wait(ctx)
+}
+
+// drain applies the pending moves of a list until there are none left.
+func (q *Queue[T]) drain(listID string, list *listQueue[T]) {
+	defer q.running.Done()
+	for {
+		q.mu.Lock()
+		if len(list.pending) == 0 {
+			delete(q.lists, listID)
+			q.mu.Unlock()
+			return
+		}
+		next := list.pending[0]
+		list.pending[0] = nil
+		list.pending = list.pending[1:]
+		q.mu.Unlock()
+
+		if err := next.ctx.Err(); err != nil {
+			next.future.resolve(PersistedResult[T]{}, err)
+			continue
+		}
+		next.future.resolve(q.manager.Apply(next.ctx, listID, next.move))
+	}
+}
+
+// Close stops accepting moves and waits until every queued move has been
+// applied, or until ctx is done.
+func (q *Queue[T]) Close(ctx context.Context) error {
+	q.mu.Lock()
+	q.closed = true
+	q.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		q.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,81 @@
+package order_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newQueue(t *testing.T, n int) (*order.Queue[*ordertest.Item], *ordertest.Store[*ordertest.Item]) {
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(n))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	q := order.NewQueue(pm)
+	t.Cleanup(func() { q.Close(context.Background()) })
+	return q, store
+}
+
+func TestQueueSerializesConcurrentMoves(t *testing.T) {
+	ctx := context.Background()
+	q, store := newQueue(t, 20)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := q.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: fmt.Sprintf("item-%d", i)})
+			errs <- err
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		assert.NoError(t, err)
+	}
+
+	items, version, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertNormalized(t, items)
+	assert.Equal(t, int64(1+store.Saves()), version)
+}
+
+func TestQueueAppliesInSubmissionOrder(t *testing.T) {
+	ctx := context.Background()
+	q, store := newQueue(t, 3)
+
+	first := q.Submit(ctx, "list", order.Move[string]{Kind: order.MoveBottom, ItemID: "item-1"})
+	second := q.Submit(ctx, "list", order.Move[string]{Kind: order.MoveBottom, ItemID: "item-2"})
+	_, err := second.Wait(ctx)
+	require.NoError(t, err)
+	<-first.Done()
+
+	items, _, err := store.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-3", "item-1", "item-2")
+}
+
+func TestQueueErrors(t *testing.T) {
+	ctx := context.Background()
+	q, _ := newQueue(t, 3)
+
+	_, err := q.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "missing"})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+
+	canceled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = q.Submit(canceled, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-2"}).Wait(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+
+	require.NoError(t, q.Close(ctx))
+	_, err = q.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-2"})
+	assert.ErrorIs(t, err, order.ErrQueueClosed)
+}
@@ -0,0 +1,185 @@
+package order
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReconcilePolicy decides which items a client may reorder with Reconcile.
+// The zero value lets the client reorder every item that is not locked.
+type ReconcilePolicy[T Orderable] struct {
+	// Scope selects the items the client was shown and orders. The client
+	// order must list exactly these; items outside the scope keep their
+	// slots. A nil Scope selects all items.
+	Scope func(T) bool
+	// CanMove selects the scoped items the client may reorder. The others
+	// are protected: the client order must keep them in their current
+	// relative order, although the permitted items may move between them. A
+	// nil CanMove permits all items.
+	CanMove func(T) bool
+}
+
+// Reconcile applies a complete ordering submitted by a client, such as the
+// result of a drag and drop UI that sends the whole list, to current. The
+// client order must be a permutation of the IDs of the items in the scope of
+// policy: an ID that is not in the scope fails with a *NotFoundError, an ID
+// listed twice with a *DuplicateIDError, and a missing ID with
+// ErrInvalidOrder. A scoped ID held by two items of current also fails with a
+// *DuplicateIDError. Protected items that changed their relative order fail
+// with a *PermissionError, and locked items (see Lockable) must keep their
+// place among the scoped items, or Reconcile fails with a *LockedError.
+//
+// On success the scoped items are rearranged in their slots to follow the
+// client order, positions are normalized from 1 and the changes are
+// returned; only items whose position changed are included. On error current
+// is left unchanged.
+func Reconcile[T Orderable](current []T, clientOrder []string, policy ReconcilePolicy[T]) (ChangeSet, error) {
+	return reconcile(current, clientOrder, policy, &options{})
+}
+
+// reconcile is Reconcile normalizing positions with the index base and
+// position step of opts.
+func reconcile[T Orderable](current []T, clientOrder []string, policy ReconcilePolicy[T], opts *options) (ChangeSet, error) {
+	var slots []int
+	scoped := make(map[string]T)
+	indices := make(map[string]int)
+	for i, item := range current {
+		if policy.Scope == nil || policy.Scope(item) {
+			id := item.GetID()
+			if first, ok := indices[id]; ok {
+				return nil, &DuplicateIDError{Op: "Reconcile", ItemID: id, FirstIndex: first, SecondIndex: i}
+			}
+			slots = append(slots, i)
+			scoped[id], indices[id] = item, i
+		}
+	}
+
+	seen := make(map[string]int, len(clientOrder))
+	for i, id := range clientOrder {
+		if _, ok := scoped[id]; !ok {
+			return nil, &NotFoundError{Op: "Reconcile", ItemID: id}
+		}
+		if first, ok := seen[id]; ok {
+			return nil, &DuplicateIDError{Op: "Reconcile", ItemID: id, FirstIndex: first, SecondIndex: i}
+		}
+		seen[id] = i
+	}
+	if len(clientOrder) != len(slots) {
+		for _, slot := range slots {
+			id := current[slot].GetID()
+			if _, ok := seen[id]; !ok {
+				return nil, fmt.Errorf("Reconcile: item %s is missing from the client order: %w", id, ErrInvalidOrder)
+			}
+		}
+	}
+	if policy.CanMove != nil {
+		var protected []string
+		for _, slot := range slots {
+			if item := current[slot]; !policy.CanMove(item) {
+				protected = append(protected, item.GetID())
+			}
+		}
+		next := 0
+		for _, id := range clientOrder {
+			if policy.CanMove(scoped[id]) {
+				continue
+			}
+			if id != protected[next] {
+				return nil, &PermissionError{Op: "Reconcile", ItemID: protected[next]}
+			}
+			next++
+		}
+	}
+	for i, slot := range slots {
+		if item := current[slot]; isLocked(item) && clientOrder[i] != item.GetID() {
+			return nil, &LockedError{Op: "Reconcile", ItemID: item.GetID()}
+		}
+	}
+
+	for i, slot := range slots {
+		current[slot] = scoped[clientOrder[i]]
+	}
+	return renumberWith(current, opts), nil
+}
+
+// ApplyOrder loads a list, applies a complete ordering submitted by a client
+// with Reconcile and saves the changes. Like Apply, it fails with
+// ErrVersionConflict if the list was modified since it was loaded, unless
+// WithRetry retries it, and saves nothing if the order did not change.
+// Positions are normalized with the index base and position step of the
+// manager. In write-behind mode the changes are saved later.
+func (pm *PersistentManager[T]) ApplyOrder(ctx context.Context, listID string, clientOrder []string, policy ReconcilePolicy[T]) (PersistedResult[T], error) {
+	var result PersistedResult[T]
+	var err error
+	if pm.stop != nil {
+		result, err = pm.applyOrderBehind(ctx, listID, clientOrder, policy)
+	} else {
+		result, err = Retry(ctx, pm.opts.retry, func(ctx context.Context) (PersistedResult[T], error) {
+			return pm.applyOrder(ctx, listID, clientOrder, policy)
+		})
+	}
+	if err != nil {
+		return result, err
+	}
+	err = pm.applied(ctx, listID, Move[string]{}, &result)
+	return result, err
+}
+
+// applyOrder is ApplyOrder on a freshly loaded list.
+func (pm *PersistentManager[T]) applyOrder(ctx context.Context, listID string, clientOrder []string, policy ReconcilePolicy[T]) (PersistedResult[T], error) {
+	items, version, err := pm.store.Load(ctx, listID)
+	if err != nil {
+		return PersistedResult[T]{}, err
+	}
+	changes, err := reconcile(items, clientOrder, policy, &pm.manager.opts)
+	if err != nil {
+		return PersistedResult[T]{}, err
+	}
+	if len(changes) > 0 {
+		version, err = pm.store.SavePositions(ctx, listID, version, changes)
+		if err != nil {
+			return PersistedResult[T]{}, fmt.Errorf("ApplyOrder %s: %w", listID, err)
+		}
+	}
+	return pm.ordered(items, changes, version), nil
+}
+
+func (pm *PersistentManager[T]) applyOrderBehind(ctx context.Context, listID string, clientOrder []string, policy ReconcilePolicy[T]) (PersistedResult[T], error) {
+	list, err := pm.locked(ctx, listID)
+	if err != nil {
+		return PersistedResult[T]{}, err
+	}
+	changes, err := reconcile(list.items, clientOrder, policy, &pm.manager.opts)
+	if err != nil {
+		list.mu.Unlock()
+		return PersistedResult[T]{}, err
+	}
+	list.record(changes)
+	result := pm.ordered(list.items, changes, list.version)
+	full := pm.opts.flushSize > 0 && len(list.dirty) >= pm.opts.flushSize
+	list.mu.Unlock()
+
+	if full {
+		if err := pm.flushList(ctx, listID, list); err != nil {
+			pm.reportFlushError(listID, err)
+		}
+	}
+	return result, nil
+}
+
+// ordered builds the PersistedResult of ApplyOrder, whose Affected items are
+// the ones in changes.
+func (pm *PersistentManager[T]) ordered(items []T, changes ChangeSet, version int64) PersistedResult[T] {
+	changed := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		changed[change.ItemID] = true
+	}
+	result := PersistedResult[T]{Changes: changes, Version: version}
+	for _, item := range items {
+		if changed[item.GetID()] {
+			result.Affected = append(result.Affected, item)
+		}
+	}
+	result.Changed = len(changes) > 0
+	return result
+}
@@ -0,0 +1,138 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReconcile(t *testing.T) {
+	items := ordertest.ItemsWithIDs("a", "b", "c", "d")
+	changes, err := order.Reconcile(items, []string{"a", "c", "b", "d"}, order.ReconcilePolicy[*ordertest.Item]{})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "a", "c", "b", "d")
+	ordertest.AssertNormalized(t, items)
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "c", OldPosition: 3, NewPosition: 2},
+		{ItemID: "b", OldPosition: 2, NewPosition: 3},
+	}, changes)
+
+	changes, err = order.Reconcile(items, []string{"a", "c", "b", "d"}, order.ReconcilePolicy[*ordertest.Item]{})
+	require.NoError(t, err)
+	assert.Empty(t, changes)
+}
+
+func TestReconcileScope(t *testing.T) {
+	// The client was shown only the items whose ID starts with "mine".
+	items := ordertest.ItemsWithIDs("mine-1", "other-1", "mine-2", "other-2", "mine-3")
+	policy := order.ReconcilePolicy[*ordertest.Item]{Scope: func(item *ordertest.Item) bool {
+		return strings.HasPrefix(item.ID, "mine")
+	}}
+
+	_, err := order.Reconcile(items, []string{"mine-3", "mine-1", "mine-2"}, policy)
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "mine-3", "other-1", "mine-1", "other-2", "mine-2")
+
+	_, err = order.Reconcile(items, []string{"mine-3", "other-1", "mine-1", "mine-2"}, policy)
+	var notFound *order.NotFoundError
+	require.True(t, errors.As(err, &notFound))
+	assert.Equal(t, "other-1", notFound.ItemID)
+}
+
+func TestReconcileRejects(t *testing.T) {
+	// The first item is locked; -1 stands for an ID that is not in the list.
+	tests := []struct {
+		name   string
+		client []int
+		target error
+	}{
+		{"injected item", []int{0, 1, 2, -1}, order.ErrItemNotFound},
+		{"dropped item", []int{0, 2}, order.ErrInvalidOrder},
+		{"duplicate item", []int{0, 1, 1, 2}, order.ErrDuplicateID},
+		{"moved locked item", []int{1, 0, 2}, order.ErrItemLocked},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []*LockableItem{
+				{TestItem: TestItem{ID: uuid.New(), Position: 1}, Locked: true},
+				{TestItem: TestItem{ID: uuid.New(), Position: 2}},
+				{TestItem: TestItem{ID: uuid.New(), Position: 3}},
+			}
+			before := slices.Clone(items)
+			client := make([]string, len(tt.client))
+			for i, index := range tt.client {
+				client[i] = "missing"
+				if index >= 0 {
+					client[i] = items[index].GetID()
+				}
+			}
+
+			_, err := order.Reconcile(items, client, order.ReconcilePolicy[*LockableItem]{})
+			assert.ErrorIs(t, err, tt.target)
+			assert.Equal(t, before, items)
+		})
+	}
+}
+
+func TestPersistentManagerApplyOrder(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.ItemsWithIDs("a", "b", "c"))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	result, err := pm.ApplyOrder(ctx, "list", []string{"c", "a", "b"}, order.ReconcilePolicy[*ordertest.Item]{})
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Len(t, result.Affected, 3)
+	assert.Equal(t, int64(2), result.Version)
+	assert.NotEmpty(t, result.OperationID)
+
+	items, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "c", "a", "b")
+
+	result, err = pm.ApplyOrder(ctx, "list", []string{"c", "a", "b"}, order.ReconcilePolicy[*ordertest.Item]{})
+	require.NoError(t, err)
+	assert.False(t, result.Changed)
+	assert.Equal(t, 1, store.Saves())
+
+	_, err = pm.ApplyOrder(ctx, "list", []string{"c", "a"}, order.ReconcilePolicy[*ordertest.Item]{})
+	assert.ErrorIs(t, err, order.ErrInvalidOrder)
+}
+
+func TestReconcileDuplicateInCurrent(t *testing.T) {
+	items := ordertest.ItemsWithIDs("a", "a", "b")
+	before := slices.Clone(items)
+	_, err := order.Reconcile(items, []string{"b", "a"}, order.ReconcilePolicy[*ordertest.Item]{})
+	var duplicate *order.DuplicateIDError
+	require.True(t, errors.As(err, &duplicate))
+	assert.Equal(t, 0, duplicate.FirstIndex)
+	assert.Equal(t, 1, duplicate.SecondIndex)
+	assert.Equal(t, before, items)
+}
+
+func TestPersistentManagerApplyOrderUsesManagerNumbering(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.ItemsWithIDs("a", "b", "c"))
+	om := order.NewOrderManager[*ordertest.Item](order.WithIndexBase(0), order.WithPositionStep(10))
+	pm := order.NewPersistentManager[*ordertest.Item](store, om)
+
+	_, err := pm.ApplyOrder(ctx, "list", []string{"c", "a", "b"}, order.ReconcilePolicy[*ordertest.Item]{})
+	require.NoError(t, err)
+	items, _, err := pm.Load(ctx, "list")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "c", "a", "b")
+	for i, item := range items {
+		assert.Equal(t, i*10, item.GetPosition())
+	}
+}
@@ -0,0 +1,313 @@
+package order
+
+import (
+	"container/list"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ListRegistry holds many named lists in memory and serializes the moves on
+// each list. It is safe for concurrent use.
+//
+// A registry created with NewListRegistryFromStore loads lists from a Store on
+// first use and saves every move back to it, so that it can serve as the
+// in-memory layer in front of the Store. WithMaxLists and WithListTTL bound
+// the lists held in memory.
+type ListRegistry[T Orderable] struct {
+	mu      sync.Mutex
+	lists   map[string]*registeredList[T]
+	recent  *list.List // of list IDs, most recently used first
+	store   Store[T]
+	manager *OrderManager[T]
+	opts    registryOptions
+}
+
+type registeredList[T Orderable] struct {
+	mu        sync.Mutex
+	items     []T
+	undo      []undoEntry
+	undoBytes int
+	// stored reports that the list is kept in the Store; once loaded, it
+	// holds the items at version.
+	stored   bool
+	loaded   bool
+	version  int64
+	expires  time.Time
+	position *list.Element
+}
+
+// undoEntry records how to take back a single move.
+type undoEntry struct {
+	actorID string
+	itemID  string
+	// oldIndex is the index of the item before the move.
+	oldIndex int
+}
+
+// size estimates the memory held by the entry for the undo memory cap.
+func (e undoEntry) size() int {
+	return len(e.actorID) + len(e.itemID) + 40
+}
+
+// RegistryOption configures a ListRegistry.
+type RegistryOption func(*registryOptions)
+
+type registryOptions struct {
+	undoDepth    int
+	undoMaxBytes int
+	maxLists     int
+	ttl          time.Duration
+}
+
+// WithUndoHistory keeps up to depth moves per list for UndoLast, dropping the
+// oldest once the list's history would exceed depth entries or roughly
+// maxBytes of memory. A maxBytes of 0 means no memory cap. Without this option
+// no history is kept.
+func WithUndoHistory(depth, maxBytes int) RegistryOption {
+	return func(o *registryOptions) {
+		o.undoDepth = depth
+		o.undoMaxBytes = maxBytes
+	}
+}
+
+// WithMaxLists keeps at most n lists in memory, evicting the least recently
+// used one when another is added. Evicted lists loaded from the Store are
+// loaded again on next use; lists added with Put are gone.
+func WithMaxLists(n int) RegistryOption {
+	return func(o *registryOptions) {
+		o.maxLists = n
+	}
+}
+
+// WithListTTL expires a list ttl after it was loaded or Put, so that lists
+// loaded from the Store pick up changes made by others: the next use of an
+// expired list drops it from memory and, like an evicted list, loads it again
+// from the Store.
+func WithListTTL(ttl time.Duration) RegistryOption {
+	return func(o *registryOptions) {
+		o.ttl = ttl
+	}
+}
+
+// NewListRegistry creates an empty registry whose lists are reordered with manager.
+func NewListRegistry[T Orderable](manager *OrderManager[T], opts ...RegistryOption) *ListRegistry[T] {
+	r := &ListRegistry[T]{lists: make(map[string]*registeredList[T]), recent: list.New(), manager: manager}
+	for _, opt := range opts {
+		opt(&r.opts)
+	}
+	return r
+}
+
+// NewListRegistryFromStore creates a registry that loads lists from store when
+// they are first used, and saves the changed positions of every move on such
+// a list to store against the version it loaded. A save that fails drops the
+// list from memory and fails the move; the list is loaded again on next use.
+// Loads and saves use the context of the method that needs them, and a list
+// is loaded without holding up the other lists.
+func NewListRegistryFromStore[T Orderable](store Store[T], manager *OrderManager[T], opts ...RegistryOption) *ListRegistry[T] {
+	r := NewListRegistry(manager, opts...)
+	r.store = store
+	return r
+}
+
+// Put replaces the items of a list, creating it if needed, and clears its undo
+// history. The registry takes ownership of items. A list added with Put is
+// only kept in memory, even if the registry has a Store.
+func (r *ListRegistry[T]) Put(listID string, items []T) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(listID, &registeredList[T]{items: items})
+}
+
+// Evict drops a list from memory. A list loaded from the Store is loaded again
+// on next use.
+func (r *ListRegistry[T]) Evict(listID string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if list, ok := r.lists[listID]; ok {
+		r.remove(listID, list)
+	}
+}
+
+// Len returns the number of lists held in memory.
+func (r *ListRegistry[T]) Len() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.lists)
+}
+
+// Items returns a copy of the items of a list in order. It fails with
+// ErrListNotFound if the list does not exist.
+func (r *ListRegistry[T]) Items(ctx context.Context, listID string) ([]T, error) {
+	list, err := r.list(ctx, "Items", listID)
+	if err != nil {
+		return nil, err
+	}
+	defer list.mu.Unlock()
+	return append([]T(nil), list.items...), nil
+}
+
+// Apply performs a move on a list on behalf of actorID and records it in the
+// undo history.
+func (r *ListRegistry[T]) Apply(ctx context.Context, listID, actorID string, m Move[string]) (Result[T], error) {
+	list, err := r.list(ctx, "Apply", listID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	defer list.mu.Unlock()
+
+	before := r.before(list)
+	oldIndex, _ := r.manager.GetItemIndexByID(list.items, m.ItemID)
+	result, err := r.manager.Apply(list.items, m)
+	if err != nil {
+		return result, err
+	}
+	if err := r.save(ctx, "Apply", listID, list, result, before); err != nil {
+		return Result[T]{}, err
+	}
+	if result.Changed && r.opts.undoDepth > 0 {
+		list.record(undoEntry{actorID: actorID, itemID: m.ItemID, oldIndex: oldIndex}, r.opts)
+	}
+	return result, nil
+}
+
+// UndoLast takes back the most recent move actorID made on a list by moving the
+// item back to its previous position, leaving the moves of other actors in the
+// history. If the list has become shorter, the item goes to the end. It fails
+// with ErrNothingToUndo if actorID has no move in the history.
+func (r *ListRegistry[T]) UndoLast(ctx context.Context, listID, actorID string) (Result[T], error) {
+	list, err := r.list(ctx, "UndoLast", listID)
+	if err != nil {
+		return Result[T]{}, err
+	}
+	defer list.mu.Unlock()
+
+	for i := len(list.undo) - 1; i >= 0; i-- {
+		entry := list.undo[i]
+		if entry.actorID != actorID {
+			continue
+		}
+		before := r.before(list)
+		result, err := r.manager.To(list.items, entry.itemID, r.manager.opts.base()+min(entry.oldIndex, len(list.items)-1))
+		if err == nil {
+			err = r.save(ctx, "UndoLast", listID, list, result, before)
+		}
+		if err != nil {
+			return Result[T]{}, err
+		}
+		list.undo = append(list.undo[:i], list.undo[i+1:]...)
+		list.undoBytes -= entry.size()
+		return result, nil
+	}
+	return Result[T]{}, fmt.Errorf("UndoLast %s: actor %s: %w", listID, actorID, ErrNothingToUndo)
+}
+
+// list returns a list held in memory with its lock held, loading it from the
+// Store if there is one, and marks it as most recently used. A list not in
+// memory is added before it is loaded, under its own lock rather than r.mu,
+// so that callers of the same list wait for the load and callers of other
+// lists do not.
+func (r *ListRegistry[T]) list(ctx context.Context, op, listID string) (*registeredList[T], error) {
+	r.mu.Lock()
+	list, ok := r.lists[listID]
+	if ok && !list.expires.IsZero() && !time.Now().Before(list.expires) {
+		r.remove(listID, list)
+		ok = false
+	}
+	switch {
+	case ok:
+		r.recent.MoveToFront(list.position)
+	case r.store == nil:
+		r.mu.Unlock()
+		return nil, fmt.Errorf("%s %s: %w", op, listID, ErrListNotFound)
+	default:
+		list = &registeredList[T]{stored: true}
+		r.add(listID, list)
+	}
+	r.mu.Unlock()
+
+	list.mu.Lock()
+	if !list.stored || list.loaded {
+		return list, nil
+	}
+	items, version, err := r.store.Load(ctx, listID)
+	if err != nil {
+		r.drop(listID, list)
+		list.mu.Unlock()
+		return nil, fmt.Errorf("%s %s: %w", op, listID, err)
+	}
+	list.items, list.version, list.loaded = items, version, true
+	return list, nil
+}
+
+// add puts list into memory as the most recently used one, evicting the least
+// recently used lists beyond WithMaxLists. The caller holds r.mu.
+func (r *ListRegistry[T]) add(listID string, list *registeredList[T]) {
+	if old, ok := r.lists[listID]; ok {
+		r.remove(listID, old)
+	}
+	if r.opts.ttl > 0 {
+		list.expires = time.Now().Add(r.opts.ttl)
+	}
+	list.position = r.recent.PushFront(listID)
+	r.lists[listID] = list
+	for r.opts.maxLists > 0 && len(r.lists) > r.opts.maxLists {
+		oldest := r.recent.Back().Value.(string)
+		r.remove(oldest, r.lists[oldest])
+	}
+}
+
+// drop removes list from memory, taking r.mu.
+func (r *ListRegistry[T]) drop(listID string, list *registeredList[T]) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.remove(listID, list)
+}
+
+// remove drops list from memory. The caller holds r.mu.
+func (r *ListRegistry[T]) remove(listID string, list *registeredList[T]) {
+	if r.lists[listID] != list {
+		return
+	}
+	r.recent.Remove(list.position)
+	delete(r.lists, listID)
+}
+
+// before records the positions of a list loaded from the Store before a move,
+// for save. The caller holds list.mu.
+func (r *ListRegistry[T]) before(list *registeredList[T]) map[string]int {
+	if !list.stored {
+		return nil
+	}
+	return positionsByID(list.items)
+}
+
+// save writes the changes of result to the Store if list was loaded from it.
+// If that fails, the list is dropped from memory and marked unloaded, so that
+// callers already waiting for it load it again instead of moving the unsaved
+// items. The caller holds list.mu.
+func (r *ListRegistry[T]) save(ctx context.Context, op, listID string, list *registeredList[T], result Result[T], before map[string]int) error {
+	if !list.stored || !result.Changed {
+		return nil
+	}
+	version, err := r.store.SavePositions(ctx, listID, list.version, changesOf(result.Affected, before))
+	if err != nil {
+		r.drop(listID, list)
+		list.loaded, list.items, list.undo, list.undoBytes = false, nil, nil, 0
+		return fmt.Errorf("%s %s: %w", op, listID, err)
+	}
+	list.version = version
+	return nil
+}
+
+// record appends entry to the undo history and trims it to the configured limits.
+func (l *registeredList[T]) record(entry undoEntry, opts registryOptions) {
+	l.undo = append(l.undo, entry)
+	l.undoBytes += entry.size()
+	for len(l.undo) > opts.undoDepth || (opts.undoMaxBytes > 0 && l.undoBytes > opts.undoMaxBytes && len(l.undo) > 0) {
+		l.undoBytes -= l.undo[0].size()
+		l.undo = l.undo[1:]
+	}
+}
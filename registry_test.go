@@ -0,0 +1,270 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/mockstore"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRegistry(items []*TestItem, opts ...order.RegistryOption) *order.ListRegistry[*TestItem] {
+	r := order.NewListRegistry(order.NewOrderManager[*TestItem](), opts...)
+	r.Put("board", items)
+	return r
+}
+
+func TestListRegistryApply(t *testing.T) {
+	ctx := context.Background()
+	items := createTestItems(3)
+	last := items[2]
+	r := newTestRegistry(items)
+
+	result, err := r.Apply(ctx, "board", "alice", order.Move[string]{Kind: order.MoveTop, ItemID: last.GetID()})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewPosition)
+
+	got, err := r.Items(ctx, "board")
+	require.NoError(t, err)
+	assert.Same(t, last, got[0])
+
+	_, err = r.Items(ctx, "missing")
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+	_, err = r.UndoLast(ctx, "board", "alice")
+	assert.ErrorIs(t, err, order.ErrNothingToUndo, "no history without WithUndoHistory")
+}
+
+func TestListRegistryUndoLastPerActor(t *testing.T) {
+	ctx := context.Background()
+	items := createTestItems(4)
+	all := append([]*TestItem(nil), items...)
+	r := newTestRegistry(items, order.WithUndoHistory(10, 0))
+
+	_, err := r.Apply(ctx, "board", "alice", order.Move[string]{Kind: order.MoveBottom, ItemID: all[0].GetID()})
+	require.NoError(t, err)
+	_, err = r.Apply(ctx, "board", "bob", order.Move[string]{Kind: order.MoveTop, ItemID: all[3].GetID()})
+	require.NoError(t, err)
+
+	// Alice's undo only takes back her own move.
+	result, err := r.UndoLast(ctx, "board", "alice")
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.NewPosition)
+	got, _ := r.Items(ctx, "board")
+	assert.Equal(t, []*TestItem{all[0], all[3], all[1], all[2]}, got)
+
+	_, err = r.UndoLast(ctx, "board", "alice")
+	assert.ErrorIs(t, err, order.ErrNothingToUndo)
+	// Bob's item goes back to position 3, where it was before his move.
+	_, err = r.UndoLast(ctx, "board", "bob")
+	require.NoError(t, err)
+	got, _ = r.Items(ctx, "board")
+	assert.Equal(t, []*TestItem{all[0], all[1], all[3], all[2]}, got)
+}
+
+func TestListRegistryUndoLimits(t *testing.T) {
+	ctx := context.Background()
+	items := createTestItems(3)
+	r := newTestRegistry(items, order.WithUndoHistory(1, 0))
+	for _, item := range []*TestItem{items[2], items[1]} {
+		_, err := r.Apply(ctx, "board", "alice", order.Move[string]{Kind: order.MoveTop, ItemID: item.GetID()})
+		require.NoError(t, err)
+	}
+	_, err := r.UndoLast(ctx, "board", "alice")
+	require.NoError(t, err)
+	_, err = r.UndoLast(ctx, "board", "alice")
+	assert.ErrorIs(t, err, order.ErrNothingToUndo, "depth 1 keeps only the last move")
+
+	r = newTestRegistry(createTestItems(3), order.WithUndoHistory(10, 1))
+	got, _ := r.Items(ctx, "board")
+	_, err = r.Apply(ctx, "board", "alice", order.Move[string]{Kind: order.MoveTop, ItemID: got[2].GetID()})
+	require.NoError(t, err)
+	_, err = r.UndoLast(ctx, "board", "alice")
+	assert.ErrorIs(t, err, order.ErrNothingToUndo, "entries larger than the memory cap are dropped")
+}
+
+func TestListRegistryEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	r := order.NewListRegistry(order.NewOrderManager[*TestItem](), order.WithMaxLists(2))
+	r.Put("a", createTestItems(1))
+	r.Put("b", createTestItems(1))
+	_, err := r.Items(ctx, "a")
+	require.NoError(t, err)
+	r.Put("c", createTestItems(1))
+
+	assert.Equal(t, 2, r.Len())
+	_, err = r.Items(ctx, "b")
+	assert.ErrorIs(t, err, order.ErrListNotFound, "b was used least recently")
+	_, err = r.Items(ctx, "a")
+	assert.NoError(t, err)
+
+	r.Evict("a")
+	assert.Equal(t, 1, r.Len())
+}
+
+func TestListRegistryTTL(t *testing.T) {
+	ctx := context.Background()
+	r := order.NewListRegistry(order.NewOrderManager[*TestItem](), order.WithListTTL(10*time.Millisecond))
+	r.Put("board", createTestItems(2))
+	_, err := r.Items(ctx, "board")
+	require.NoError(t, err)
+
+	time.Sleep(20 * time.Millisecond)
+	_, err = r.Items(ctx, "board")
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+	assert.Zero(t, r.Len())
+}
+
+func TestListRegistryFromStore(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("board", ordertest.Items(3))
+	store.Put("other", ordertest.Items(2))
+	r := order.NewListRegistryFromStore[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithMaxLists(1), order.WithUndoHistory(10, 0))
+
+	_, err := r.Apply(ctx, "board", "alice", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	_, err = r.Items(ctx, "board")
+	require.NoError(t, err)
+	assert.Equal(t, 1, store.CallCount(mockstore.Load), "loaded once")
+	saved, version, err := store.Load(ctx, "board")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), version)
+	ordertest.AssertOrder(t, saved, "item-3", "item-1", "item-2")
+
+	// Using another list evicts board, which is loaded again with its moves.
+	_, err = r.Items(ctx, "other")
+	require.NoError(t, err)
+	_, err = r.UndoLast(ctx, "board", "alice")
+	assert.ErrorIs(t, err, order.ErrNothingToUndo, "the history went with the evicted list")
+	items, err := r.Items(ctx, "board")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-3", "item-1", "item-2")
+
+	_, err = r.Items(ctx, "missing")
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+}
+
+func TestListRegistryFromStoreSaveFailure(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("board", ordertest.Items(3))
+	r := order.NewListRegistryFromStore[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	store.FailNext(mockstore.SavePositions, order.ErrVersionConflict)
+	_, err := r.Apply(ctx, "board", "alice", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	assert.ErrorIs(t, err, order.ErrVersionConflict)
+
+	items, err := r.Items(ctx, "board")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3")
+	assert.Equal(t, 2, store.CallCount(mockstore.Load), "the failed list is loaded again")
+}
+
+// waitingStore holds the Load of list until ctx is done.
+type waitingStore struct {
+	order.Store[*ordertest.Item]
+	list    string
+	loading chan struct{}
+}
+
+func (s *waitingStore) Load(ctx context.Context, listID string) ([]*ordertest.Item, int64, error) {
+	if listID != s.list {
+		return s.Store.Load(ctx, listID)
+	}
+	close(s.loading)
+	<-ctx.Done()
+	return nil, 0, ctx.Err()
+}
+
+func TestListRegistryLoadsWithoutBlockingOtherLists(t *testing.T) {
+	backend := ordertest.NewStore[*ordertest.Item]()
+	backend.Put("board", ordertest.Items(3))
+	store := &waitingStore{Store: backend, list: "slow", loading: make(chan struct{})}
+	r := order.NewListRegistryFromStore[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() {
+		_, err := r.Items(ctx, "slow")
+		done <- err
+	}()
+	<-store.loading
+
+	items, err := r.Items(context.Background(), "board")
+	require.NoError(t, err)
+	assert.Len(t, items, 3)
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled, "the load uses the caller's context")
+	assert.Equal(t, 1, r.Len(), "the failed list is dropped")
+}
+
+// failFirstSaveStore fails the first SavePositions once release is closed.
+type failFirstSaveStore struct {
+	*ordertest.Store[*ordertest.Item]
+	saving  chan struct{}
+	release chan struct{}
+	failed  bool
+}
+
+func (s *failFirstSaveStore) SavePositions(ctx context.Context, listID string, version int64, changes order.ChangeSet) (int64, error) {
+	if !s.failed {
+		s.failed = true
+		close(s.saving)
+		<-s.release
+		return 0, errors.New("connection reset")
+	}
+	return s.Store.SavePositions(ctx, listID, version, changes)
+}
+
+func TestListRegistryMoveWaitingOnFailedSave(t *testing.T) {
+	ctx := context.Background()
+	backend := ordertest.NewStore[*ordertest.Item]()
+	backend.Put("board", ordertest.Items(3))
+	store := &failFirstSaveStore{Store: backend, saving: make(chan struct{}), release: make(chan struct{})}
+	r := order.NewListRegistryFromStore[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+
+	first := make(chan error)
+	go func() {
+		_, err := r.Apply(ctx, "board", "alice", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+		first <- err
+	}()
+	<-store.saving
+	second := make(chan error)
+	go func() {
+		_, err := r.Apply(ctx, "board", "bob", order.Move[string]{Kind: order.MoveBottom, ItemID: "item-1"})
+		second <- err
+	}()
+	time.Sleep(10 * time.Millisecond) // let bob wait for the list
+	close(store.release)
+	assert.Error(t, <-first)
+	require.NoError(t, <-second)
+
+	// Bob's move went to the list as stored, without alice's unsaved move.
+	items, _, err := backend.Load(ctx, "board")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "item-2", "item-3", "item-1")
+	ordertest.AssertNormalized(t, items)
+}
+
+func TestListRegistryUndoWithPositionStep(t *testing.T) {
+	ctx := context.Background()
+	r := order.NewListRegistry(order.NewOrderManager[*TestItem](order.WithPositionStep(10)), order.WithUndoHistory(10, 0))
+	items := createTestItems(3)
+	r.Put("board", items)
+	_, err := r.Apply(ctx, "board", "alice", order.Move[string]{Kind: order.MoveTop, ItemID: items[2].GetID()})
+	require.NoError(t, err)
+
+	_, err = r.UndoLast(ctx, "board", "alice")
+	require.NoError(t, err)
+	got, err := r.Items(ctx, "board")
+	require.NoError(t, err)
+	assert.Same(t, items[2], got[2])
+	assert.Equal(t, 21, items[2].Position)
+}
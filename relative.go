@@ -0,0 +1,112 @@
+package order
+
+import "fmt"
+
+// RelativePlacement places an item directly above or below an anchor item,
+// for PlaceRelative.
+type RelativePlacement[ID comparable] struct {
+	ItemID   ID
+	AnchorID ID
+	// Below places the item below the anchor instead of above it.
+	Below bool
+}
+
+// PlaceRelative applies several Above and Below placements at once. Unlike a
+// sequence of Above and Below calls, the result does not depend on the order
+// of the placements: an anchor that is placed itself is placed first, and the
+// items placed next to it travel with it. Items placed on the same side of
+// the same anchor keep the order of placements, so placing b and then c below
+// a gives a, b, c.
+//
+// Every item may be placed once. Placements that refer to each other in a
+// cycle, such as a below b and b below a, fail with ErrInvalidOrder, and
+// placing an item relative to itself fails with ErrSameItem. The items are
+// left unchanged on error. The Result describes placements[0].
+func (os *KeyedManager[T, ID]) PlaceRelative(items []T, placements []RelativePlacement[ID]) (result Result[T], err error) {
+	var first ID
+	if len(placements) > 0 {
+		first = placements[0].ItemID
+	}
+	defer os.instrument("PlaceRelative", items, first)(&result, &err)
+	if err := os.validate(items); err != nil {
+		return Result[T]{}, fmt.Errorf("PlaceRelative: %w", err)
+	}
+	items, done := os.withoutDeleted(items, first)
+	defer done(&result, &err)
+
+	index := os.IndexMap(items)
+	placed := make(map[ID]bool, len(placements))
+	above := make(map[ID][]int)
+	below := make(map[ID][]int)
+	for _, p := range placements {
+		i, ok := index[p.ItemID]
+		if !ok {
+			return Result[T]{}, &NotFoundError{Op: "PlaceRelative", ItemID: formatID(p.ItemID)}
+		}
+		if _, ok := index[p.AnchorID]; !ok {
+			return Result[T]{}, &NotFoundError{Op: "PlaceRelative", ItemID: formatID(p.AnchorID)}
+		}
+		if p.ItemID == p.AnchorID {
+			return Result[T]{}, fmt.Errorf("PlaceRelative: %w: %s", ErrSameItem, formatID(p.ItemID))
+		}
+		if placed[p.ItemID] {
+			return Result[T]{}, fmt.Errorf("PlaceRelative: %s is placed more than once: %w", formatID(p.ItemID), ErrInvalidOrder)
+		}
+		if isLocked(items[i]) {
+			return Result[T]{}, &LockedError{Op: "PlaceRelative", ItemID: formatID(p.ItemID)}
+		}
+		placed[p.ItemID] = true
+		if p.Below {
+			below[p.AnchorID] = append(below[p.AnchorID], i)
+		} else {
+			above[p.AnchorID] = append(above[p.AnchorID], i)
+		}
+	}
+	if len(placements) == 0 {
+		return Result[T]{}, nil
+	}
+
+	// Lay out every item that stays, each surrounded by the items placed
+	// next to it, recursively. Items in a cycle are never reached.
+	ordered := make([]T, 0, len(items))
+	emitted := make([]bool, len(items))
+	var emit func(i int)
+	emit = func(i int) {
+		id := os.getID(items[i])
+		anchor := index[id] == i
+		if anchor {
+			for _, j := range above[id] {
+				emit(j)
+			}
+		}
+		ordered = append(ordered, items[i])
+		emitted[i] = true
+		if anchor {
+			for _, j := range below[id] {
+				emit(j)
+			}
+		}
+	}
+	for i, item := range items {
+		if id := os.getID(item); !placed[id] || index[id] != i {
+			emit(i)
+		}
+	}
+	if len(ordered) < len(items) {
+		for _, p := range placements {
+			if !emitted[index[p.ItemID]] {
+				return Result[T]{}, fmt.Errorf("PlaceRelative: placements of %s form a cycle: %w", formatID(p.ItemID), ErrInvalidOrder)
+			}
+		}
+	}
+
+	oldPosition := os.getPos(items[index[first]])
+	copy(items, ordered)
+	affected := os.normalize(items)
+	for i, item := range items {
+		if os.getID(item) == first {
+			return os.result(items, i, oldPosition, affected), nil
+		}
+	}
+	return Result[T]{}, nil
+}
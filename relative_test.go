@@ -0,0 +1,83 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlaceRelative(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c", "d", "e")
+
+	// d moves below a, and c travels with d although d was placed later.
+	result, err := om.PlaceRelative(items, []order.RelativePlacement[string]{
+		{ItemID: "c", AnchorID: "d", Below: true},
+		{ItemID: "d", AnchorID: "a", Below: true},
+		{ItemID: "e", AnchorID: "a"},
+	})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "e", "a", "d", "c", "b")
+	ordertest.AssertNormalized(t, items)
+	assert.Equal(t, 3, result.OldPosition)
+	assert.Equal(t, 4, result.NewPosition)
+	assert.Len(t, result.Affected, 5)
+
+	// Items on the same side of an anchor keep the order of the placements.
+	items = ordertest.ItemsWithIDs("a", "b", "c", "d")
+	_, err = om.PlaceRelative(items, []order.RelativePlacement[string]{
+		{ItemID: "d", AnchorID: "a", Below: true},
+		{ItemID: "b", AnchorID: "a", Below: true},
+		{ItemID: "c", AnchorID: "a"},
+	})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "c", "a", "d", "b")
+
+	result, err = om.PlaceRelative(items, nil)
+	require.NoError(t, err)
+	assert.False(t, result.Changed)
+}
+
+func TestPlaceRelativeErrors(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.ItemsWithIDs("a", "b", "c")
+
+	tests := []struct {
+		name       string
+		placements []order.RelativePlacement[string]
+		want       error
+	}{
+		{"missing item", []order.RelativePlacement[string]{{ItemID: "x", AnchorID: "a"}}, order.ErrItemNotFound},
+		{"missing anchor", []order.RelativePlacement[string]{{ItemID: "a", AnchorID: "x"}}, order.ErrItemNotFound},
+		{"same item", []order.RelativePlacement[string]{{ItemID: "a", AnchorID: "a"}}, order.ErrSameItem},
+		{"placed twice", []order.RelativePlacement[string]{{ItemID: "a", AnchorID: "b"}, {ItemID: "a", AnchorID: "c"}}, order.ErrInvalidOrder},
+		{"cycle", []order.RelativePlacement[string]{
+			{ItemID: "a", AnchorID: "b", Below: true},
+			{ItemID: "b", AnchorID: "c"},
+			{ItemID: "c", AnchorID: "a"},
+		}, order.ErrInvalidOrder},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := om.PlaceRelative(items, tt.placements)
+			assert.ErrorIs(t, err, tt.want)
+			ordertest.AssertOrder(t, items, "a", "b", "c")
+			ordertest.AssertNormalized(t, items)
+		})
+	}
+}
+
+func TestPlaceRelativeLocked(t *testing.T) {
+	om := order.NewOrderManager[*LockableItem]()
+	items := []*LockableItem{
+		{TestItem: TestItem{ID: uuid.New(), Position: 1}, Locked: true},
+		{TestItem: TestItem{ID: uuid.New(), Position: 2}},
+	}
+	_, err := om.PlaceRelative(items, []order.RelativePlacement[string]{{ItemID: items[0].GetID(), AnchorID: items[1].GetID(), Below: true}})
+	assert.ErrorIs(t, err, order.ErrItemLocked)
+}
@@ -0,0 +1,83 @@
+package order
+
+import "sort"
+
+// RepairOptions configures Repair. The zero value performs a full repair:
+// re-sort by position, drop duplicate IDs and renumber from 1.
+type RepairOptions[T Orderable] struct {
+	// KeepSliceOrder skips re-sorting by position, so the current slice order wins.
+	KeepSliceOrder bool
+	// TieBreak orders items that share a position. It returns a negative number
+	// if a sorts before b. When nil, items with equal positions keep their slice order.
+	TieBreak TieBreaker[T]
+	// KeepDuplicates keeps items whose ID was already seen instead of removing them.
+	KeepDuplicates bool
+}
+
+// RepairReport describes what Repair changed.
+type RepairReport[T Orderable] struct {
+	// Issues are the problems Validate found before the repair.
+	Issues []ValidationIssue
+	// Resorted reports whether the slice order was changed to match positions.
+	Resorted bool
+	// Removed holds the items dropped because their ID was already present.
+	Removed []T
+	// Changes lists the position updates applied to the remaining items.
+	Changes ChangeSet
+}
+
+// Repair returns a repaired copy of items: sorted by position (ties broken by
+// opts.TieBreak), with later items sharing an ID removed, and renumbered from 1
+// without gaps. The input slice is not reordered, but positions are updated on
+// the items themselves. Unlike NormalizePositions, which stamps slice order over
+// the positions, Repair treats the stored positions as the user's intent.
+func Repair[T Orderable](items []T, opts RepairOptions[T]) ([]T, RepairReport[T]) {
+	report := RepairReport[T]{Issues: Validate(items)}
+
+	indices := make([]int, len(items))
+	for i := range indices {
+		indices[i] = i
+	}
+	if !opts.KeepSliceOrder {
+		sort.SliceStable(indices, func(i, j int) bool {
+			a, b := items[indices[i]], items[indices[j]]
+			if a.GetPosition() != b.GetPosition() {
+				return a.GetPosition() < b.GetPosition()
+			}
+			return opts.TieBreak != nil && opts.TieBreak(a, b) < 0
+		})
+		for i, index := range indices {
+			if i != index {
+				report.Resorted = true
+				break
+			}
+		}
+	}
+
+	repaired := make([]T, 0, len(items))
+	oldPositions := make([]int, 0, len(items))
+	seen := make(map[string]struct{}, len(items))
+	for _, index := range indices {
+		item := items[index]
+		if _, ok := seen[item.GetID()]; ok && !opts.KeepDuplicates {
+			report.Removed = append(report.Removed, item)
+			continue
+		}
+		seen[item.GetID()] = struct{}{}
+		repaired = append(repaired, item)
+		oldPositions = append(oldPositions, item.GetPosition())
+	}
+
+	for i, item := range repaired {
+		if oldPositions[i] != i+1 {
+			report.Changes = append(report.Changes, PositionChange{
+				ItemID:      item.GetID(),
+				OldPosition: oldPositions[i],
+				NewPosition: i + 1,
+			})
+		}
+		item.SetPosition(i + 1)
+	}
+
+	return repaired, report
+}
@@ -0,0 +1,81 @@
+package order_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRepairSortsByPosition(t *testing.T) {
+	items := createTestItems(3)
+	items[0].SetPosition(30)
+	items[1].SetPosition(10)
+	items[2].SetPosition(20)
+
+	repaired, report := order.Repair(items, order.RepairOptions[*TestItem]{})
+
+	require.Len(t, repaired, 3)
+	assert.Equal(t, []*TestItem{items[1], items[2], items[0]}, repaired)
+	assert.Equal(t, 1, items[1].GetPosition())
+	assert.Equal(t, 3, items[0].GetPosition())
+	assert.True(t, report.Resorted)
+	assert.NotEmpty(t, report.Issues)
+	assert.Len(t, report.Changes, 3)
+}
+
+func TestRepairTieBreak(t *testing.T) {
+	items := createTestItems(3)
+	for _, item := range items {
+		item.SetPosition(1)
+	}
+	byID := func(a, b *TestItem) int { return strings.Compare(a.GetID(), b.GetID()) }
+
+	repaired, _ := order.Repair(items, order.RepairOptions[*TestItem]{TieBreak: byID})
+
+	for i := 1; i < len(repaired); i++ {
+		assert.Less(t, repaired[i-1].GetID(), repaired[i].GetID())
+	}
+}
+
+func TestRepairRemovesDuplicates(t *testing.T) {
+	items := createTestItems(3)
+	items[2].ID = items[0].ID
+
+	repaired, report := order.Repair(items, order.RepairOptions[*TestItem]{})
+	assert.Equal(t, []*TestItem{items[0], items[1]}, repaired)
+	assert.Equal(t, []*TestItem{items[2]}, report.Removed)
+
+	repaired, report = order.Repair(items, order.RepairOptions[*TestItem]{KeepDuplicates: true})
+	assert.Len(t, repaired, 3)
+	assert.Empty(t, report.Removed)
+}
+
+func TestRepairKeepSliceOrderClosesGaps(t *testing.T) {
+	items := createTestItems(3)
+	items[0].SetPosition(5)
+	items[1].SetPosition(2)
+	items[2].SetPosition(9)
+
+	repaired, report := order.Repair(items, order.RepairOptions[*TestItem]{KeepSliceOrder: true})
+
+	assert.Equal(t, items, repaired)
+	assert.False(t, report.Resorted)
+	for i, item := range repaired {
+		assert.Equal(t, i+1, item.GetPosition())
+	}
+}
+
+func TestRepairNoChanges(t *testing.T) {
+	items := createTestItems(4)
+
+	repaired, report := order.Repair(items, order.RepairOptions[*TestItem]{})
+
+	assert.Equal(t, items, repaired)
+	assert.Empty(t, report.Issues)
+	assert.Empty(t, report.Changes)
+	assert.False(t, report.Resorted)
+}
@@ -0,0 +1,72 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"time"
+)
+
+// RetryPolicy configures Retry. The zero value makes a single attempt.
+type RetryPolicy struct {
+	// Attempts is the maximum number of attempts, including the first.
+	// Values below 1 mean 1.
+	Attempts int
+	// Backoff is the delay before the second attempt. It doubles for every
+	// further attempt, up to MaxBackoff if that is set.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// Jitter spreads every delay randomly by up to this fraction of it in
+	// either direction, so that writers that conflicted once do not retry in
+	// lockstep. It is clamped to [0, 1].
+	Jitter float64
+}
+
+// DefaultRetryPolicy makes up to 5 attempts, backing off from 10ms with 20%
+// jitter.
+var DefaultRetryPolicy = RetryPolicy{Attempts: 5, Backoff: 10 * time.Millisecond, MaxBackoff: time.Second, Jitter: 0.2}
+
+// Retry calls op until it succeeds, fails with an error other than
+// ErrVersionConflict, or policy runs out of attempts, and returns its last
+// result. op must reload whatever it works on, as PersistentManager.Apply
+// does, since re-reading and re-applying is what settles most conflicts of
+// simple moves. Retry stops early with the error of ctx if ctx is done while
+// it waits.
+//
+//	result, err := order.Retry(ctx, order.DefaultRetryPolicy, func(ctx context.Context) (order.PersistedResult[*Task], error) {
+//		return pm.Apply(ctx, listID, m)
+//	})
+func Retry[R any](ctx context.Context, policy RetryPolicy, op func(context.Context) (R, error)) (R, error) {
+	delay := policy.Backoff
+	for attempt := 1; ; attempt++ {
+		result, err := op(ctx)
+		if err == nil || !errors.Is(err, ErrVersionConflict) || attempt >= policy.Attempts {
+			return result, err
+		}
+		timer := time.NewTimer(policy.jittered(delay))
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return result, ctx.Err()
+		}
+		delay *= 2
+		if policy.MaxBackoff > 0 {
+			delay = min(delay, policy.MaxBackoff)
+		}
+	}
+}
+
+func (p RetryPolicy) jittered(delay time.Duration) time.Duration {
+	jitter := min(max(p.Jitter, 0), 1)
+	return delay + time.Duration(float64(delay)*jitter*(2*rand.Float64()-1))
+}
+
+// WithRetry makes Apply, ApplyOrder and ApplyPermitted retry moves that fail
+// with ErrVersionConflict according to policy, before a resolver set with
+// WithConflictResolver sees the conflict.
+func WithRetry(policy RetryPolicy) PersistentOption {
+	return func(o *persistentOptions) {
+		o.retry = policy
+	}
+}
@@ -0,0 +1,81 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/mockstore"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRetry(t *testing.T) {
+	ctx := context.Background()
+	policy := order.RetryPolicy{Attempts: 3, Backoff: time.Millisecond, Jitter: 0.5}
+	conflict := fmt.Errorf("save: %w", order.ErrVersionConflict)
+
+	calls := 0
+	n, err := order.Retry(ctx, policy, func(context.Context) (int, error) {
+		calls++
+		if calls < 3 {
+			return 0, conflict
+		}
+		return calls, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, n)
+
+	calls = 0
+	_, err = order.Retry(ctx, policy, func(context.Context) (int, error) {
+		calls++
+		return 0, conflict
+	})
+	assert.ErrorIs(t, err, order.ErrVersionConflict)
+	assert.Equal(t, 3, calls)
+
+	// Other errors and the zero policy do not retry.
+	calls = 0
+	_, err = order.Retry(ctx, policy, func(context.Context) (int, error) {
+		calls++
+		return 0, order.ErrItemNotFound
+	})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	_, err = order.Retry(ctx, order.RetryPolicy{}, func(context.Context) (int, error) {
+		calls++
+		return 0, conflict
+	})
+	assert.ErrorIs(t, err, order.ErrVersionConflict)
+	assert.Equal(t, 2, calls)
+}
+
+func TestRetryStopsWhenContextIsDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	policy := order.RetryPolicy{Attempts: 10, Backoff: time.Hour}
+	_, err := order.Retry(ctx, policy, func(context.Context) (int, error) {
+		cancel()
+		return 0, order.ErrVersionConflict
+	})
+	assert.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestPersistentManagerWithRetry(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	store.FailNext(mockstore.SavePositions, order.ErrVersionConflict)
+	store.FailNext(mockstore.SavePositions, order.ErrVersionConflict)
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](),
+		order.WithRetry(order.RetryPolicy{Attempts: 3, Backoff: time.Millisecond}))
+
+	result, err := pm.Apply(ctx, "list", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+	assert.Equal(t, 3, store.CallCount(mockstore.Load))
+	assert.Equal(t, 3, store.CallCount(mockstore.SavePositions))
+}
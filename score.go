@@ -0,0 +1,60 @@
+package order
+
+import (
+	"cmp"
+	"slices"
+)
+
+// ScoreManager orders items by a computed score, such as the rank of a feed,
+// with editorial overrides on top: manual moves are recorded as pins in an
+// Overlay, each keeping an item directly above or below its anchor, and Sort
+// re-sorts by score and then puts the pinned items back next to their
+// anchors. Items without a pin follow their score; a pinned item travels with
+// its anchor. Drop a pin with Overlay.Reset to hand the item back to its
+// score.
+type ScoreManager[T Orderable] struct {
+	score   func(T) float64
+	manager *OrderManager[T]
+	replay  *FuncManager[T]
+}
+
+// NewScoreManager creates a ScoreManager that orders by score, highest first.
+// The options configure the manual moves.
+func NewScoreManager[T Orderable](score func(T) float64, opts ...Option) *ScoreManager[T] {
+	return &ScoreManager[T]{score: score, manager: NewOrderManager[T](opts...), replay: NewOrderManager[T]().FuncManager}
+}
+
+// Sort orders items by score, highest first, with ties keeping their current
+// order, applies the pins in order and renumbers the list. Pins whose item or
+// anchor is not in items, or that would move a locked item, are skipped. Like
+// SortBy, it returns the changed positions.
+func (sm *ScoreManager[T]) Sort(items []T, pins Overlay) ChangeSet {
+	old := make(map[string]int, len(items))
+	for _, item := range items {
+		old[item.GetID()] = item.GetPosition()
+	}
+	slices.SortStableFunc(items, func(a, b T) int { return cmp.Compare(sm.score(b), sm.score(a)) })
+	renumber(items)
+	replayOverlay(sm.replay, items, pins)
+
+	var changes ChangeSet
+	for _, item := range items {
+		if position := item.GetPosition(); old[item.GetID()] != position {
+			changes = append(changes, PositionChange{ItemID: item.GetID(), OldPosition: old[item.GetID()], NewPosition: position})
+		}
+	}
+	return changes
+}
+
+// Apply performs the manual move m on items and records it in pins, anchored
+// to the item now directly above the moved item, or below it if it moved to the
+// top, so that the next Sort keeps it there.
+func (sm *ScoreManager[T]) Apply(items []T, pins *Overlay, m Move[string]) (Result[T], error) {
+	result, err := sm.manager.Apply(items, m)
+	if err != nil || !result.Changed {
+		return result, err
+	}
+	i, _ := sm.manager.GetItemIndexByID(items, m.ItemID)
+	pins.record(m.ItemID, i, len(items), func(j int) string { return items[j].GetID() })
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScoreManager(t *testing.T) {
+	scores := map[string]float64{"a": 1, "b": 4, "c": 3, "d": 2}
+	sm := order.NewScoreManager(func(i *ordertest.Item) float64 { return scores[i.ID] })
+	items := ordertest.ItemsWithIDs("a", "b", "c", "d")
+	var pins order.Overlay
+
+	changes := sm.Sort(items, pins)
+	ordertest.AssertOrder(t, items, "b", "c", "d", "a")
+	ordertest.AssertNormalized(t, items)
+	assert.Equal(t, []string{"b", "c", "d", "a"}, changes.IDs())
+
+	// The editor pins a directly below b.
+	_, err := sm.Apply(items, &pins, order.Move[string]{Kind: order.MoveBelow, ItemID: "a", TargetID: "b"})
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, items, "b", "a", "c", "d")
+	assert.Equal(t, []order.RelativePlacement[string]{{ItemID: "a", AnchorID: "b", Below: true}}, pins.Placements)
+
+	// New scores re-sort the other items; a stays with b.
+	scores = map[string]float64{"a": 1, "b": 2, "c": 3, "d": 4}
+	changes = sm.Sort(items, pins)
+	ordertest.AssertOrder(t, items, "d", "c", "b", "a")
+	ordertest.AssertNormalized(t, items)
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "d", OldPosition: 4, NewPosition: 1},
+		{ItemID: "c", OldPosition: 3, NewPosition: 2},
+		{ItemID: "b", OldPosition: 1, NewPosition: 3},
+		{ItemID: "a", OldPosition: 2, NewPosition: 4},
+	}, changes)
+
+	// Unpinned, a falls back to its score.
+	pins.Reset("a")
+	scores["a"] = 5
+	sm.Sort(items, pins)
+	ordertest.AssertOrder(t, items, "a", "d", "c", "b")
+
+	// A pin to the top anchors above the next item.
+	_, err = sm.Apply(items, &pins, order.Move[string]{Kind: order.MoveTop, ItemID: "b"})
+	require.NoError(t, err)
+	assert.Equal(t, []order.RelativePlacement[string]{{ItemID: "b", AnchorID: "a"}}, pins.Placements)
+	assert.Empty(t, sm.Sort(items, pins))
+}
+
+func TestScoreManagerSkipsStalePins(t *testing.T) {
+	sm := order.NewScoreManager(func(i *ordertest.Item) float64 { return float64(len(i.ID)) })
+	items := ordertest.ItemsWithIDs("x", "yy", "zzz")
+	pins := order.Overlay{Placements: []order.RelativePlacement[string]{
+		{ItemID: "gone", AnchorID: "x"},
+		{ItemID: "x", AnchorID: "gone", Below: true},
+	}}
+	sm.Sort(items, pins)
+	ordertest.AssertOrder(t, items, "zzz", "yy", "x")
+
+	_, err := sm.Apply(items, &pins, order.Move[string]{Kind: order.MoveTop, ItemID: "missing"})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	assert.Len(t, pins.Placements, 2)
+}
@@ -0,0 +1,59 @@
+package order
+
+import "time"
+
+// OrderSnapshot records the order of a list compactly as its IDs, so it can be
+// stored and reapplied later with RestoreSnapshot.
+type OrderSnapshot struct {
+	IDs     []string  `json:"ids"`
+	TakenAt time.Time `json:"taken_at"`
+}
+
+// Snapshot returns the current order of items.
+func Snapshot[T Orderable](items []T) OrderSnapshot {
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.GetID()
+	}
+	return OrderSnapshot{IDs: ids, TakenAt: time.Now()}
+}
+
+// RestoreSnapshot reorders items back to snap and renumbers them from 1,
+// returning the position changes. Items that were added since the snapshot stay
+// directly below the item they currently follow, or first if nothing known
+// precedes them; IDs of items removed since are ignored.
+func RestoreSnapshot[T Orderable](items []T, snap OrderSnapshot) ChangeSet {
+	rank := make(map[string]int, len(snap.IDs))
+	for i, id := range snap.IDs {
+		if _, ok := rank[id]; !ok {
+			rank[id] = i
+		}
+	}
+
+	// Group every item with the new items that follow it.
+	var leading []T
+	groups := make(map[int][]T, len(items))
+	last := -1
+	for _, item := range items {
+		r, known := rank[item.GetID()]
+		if known {
+			if _, seen := groups[r]; !seen {
+				last = r
+				groups[r] = []T{item}
+				continue
+			}
+		}
+		if last < 0 {
+			leading = append(leading, item)
+		} else {
+			groups[last] = append(groups[last], item)
+		}
+	}
+
+	restored := append(items[:0:0], leading...)
+	for r := range snap.IDs {
+		restored = append(restored, groups[r]...)
+	}
+	copy(items, restored)
+	return renumber(items)
+}
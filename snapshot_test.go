@@ -0,0 +1,43 @@
+package order_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	items := createTestItems(4)
+	want := append([]*TestItem(nil), items...)
+	snap := order.Snapshot(items)
+
+	order.Shuffle(items, 3)
+	changes := order.RestoreSnapshot(items, snap)
+	assert.Equal(t, want, items)
+	assert.NotEmpty(t, changes)
+	assert.True(t, order.IsNormalized(items))
+
+	data, err := json.Marshal(snap)
+	require.NoError(t, err)
+	var decoded order.OrderSnapshot
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, snap.IDs, decoded.IDs)
+}
+
+func TestRestoreSnapshotToleratesChanges(t *testing.T) {
+	items := createTestItems(4)
+	a, b, c, d := items[0], items[1], items[2], items[3]
+	snap := order.Snapshot([]*TestItem{a, b, c, d})
+
+	// Since the snapshot, c was removed, the list was reordered and x and y were added.
+	x, y := createTestItems(1)[0], createTestItems(1)[0]
+	current := []*TestItem{y, d, b, x, a}
+
+	order.RestoreSnapshot(current, snap)
+	assert.Equal(t, []*TestItem{y, a, b, x, d}, current)
+	assert.True(t, order.IsNormalized(current))
+}
@@ -0,0 +1,72 @@
+package order
+
+import (
+	"cmp"
+	"math/rand/v2"
+	"slices"
+	"strings"
+)
+
+// ByPosition adapts a slice of items to sort.Interface, ordering them by
+// position. Swap only exchanges slice slots; positions are left as they are, so
+// call NormalizePositions after reordering with anything other than sort.Sort,
+// such as rand.Shuffle(len(s), s.Swap).
+type ByPosition[T Orderable] []T
+
+func (s ByPosition[T]) Len() int           { return len(s) }
+func (s ByPosition[T]) Less(i, j int) bool { return s[i].GetPosition() < s[j].GetPosition() }
+func (s ByPosition[T]) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// ComparePositions compares two items by position, for use with slices.SortFunc
+// and slices.SortStableFunc.
+func ComparePositions[T Orderable](a, b T) int {
+	return cmp.Compare(a.GetPosition(), b.GetPosition())
+}
+
+// TieBreaker orders items that share a position. It returns a negative number if
+// a sorts before b. Use one wherever loaded data may contain equal positions, so
+// the resulting order does not depend on the order the database returned.
+type TieBreaker[T any] func(a, b T) int
+
+// TieBreakByID is a TieBreaker ordering items by ID.
+func TieBreakByID[T Orderable](a, b T) int {
+	return strings.Compare(a.GetID(), b.GetID())
+}
+
+// SortByPosition stably sorts items by position, ordering items with equal
+// positions with tie. A nil tie keeps their slice order. Positions are not
+// renumbered.
+func SortByPosition[T Orderable](items []T, tie TieBreaker[T]) {
+	slices.SortStableFunc(items, func(a, b T) int {
+		if c := ComparePositions(a, b); c != 0 || tie == nil {
+			return c
+		}
+		return tie(a, b)
+	})
+}
+
+// SortBy stably sorts items with compare, for example NaturalBy, and renumbers
+// them from 1. It sets the initial order before users start reordering by hand
+// and returns the position changes.
+func SortBy[T Orderable](items []T, compare func(a, b T) int) ChangeSet {
+	slices.SortStableFunc(items, compare)
+	return renumber(items)
+}
+
+// CompareToPosition compares the position of item with position, for use with
+// slices.BinarySearchFunc on a slice sorted by position:
+//
+//	i, found := slices.BinarySearchFunc(items, 3, order.CompareToPosition[*Item])
+func CompareToPosition[T Orderable](item T, position int) int {
+	return cmp.Compare(item.GetPosition(), position)
+}
+
+// Shuffle reorders items pseudo-randomly and renumbers them from 1. The same
+// seed and input order always produce the same order. It returns the position
+// changes to persist.
+func Shuffle[T Orderable](items []T, seed int64) ChangeSet {
+	rand.New(rand.NewPCG(uint64(seed), 0)).Shuffle(len(items), func(i, j int) {
+		items[i], items[j] = items[j], items[i]
+	})
+	return renumber(items)
+}
@@ -0,0 +1,83 @@
+package order_test
+
+import (
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestByPosition(t *testing.T) {
+	items := createTestItems(5)
+	want := slices.Clone(items)
+	rand.New(rand.NewPCG(1, 0)).Shuffle(len(items), order.ByPosition[*TestItem](items).Swap)
+
+	sort.Sort(order.ByPosition[*TestItem](items))
+	assert.Equal(t, want, items)
+	assert.True(t, order.IsNormalized(items))
+}
+
+func TestComparePositions(t *testing.T) {
+	items := createTestItems(4)
+	want := slices.Clone(items)
+	items[0], items[3] = items[3], items[0]
+
+	slices.SortFunc(items, order.ComparePositions[*TestItem])
+	assert.Equal(t, want, items)
+}
+
+func TestCompareToPosition(t *testing.T) {
+	items := createTestItems(4)
+	items[3].SetPosition(10)
+
+	i, found := slices.BinarySearchFunc(items, 3, order.CompareToPosition[*TestItem])
+	assert.True(t, found)
+	assert.Equal(t, 2, i)
+
+	i, found = slices.BinarySearchFunc(items, 7, order.CompareToPosition[*TestItem])
+	assert.False(t, found)
+	assert.Equal(t, 3, i)
+}
+
+func TestShuffle(t *testing.T) {
+	items := createTestItems(10)
+	clone := func() []*TestItem {
+		return order.CloneFunc(items, func(item *TestItem) *TestItem { c := *item; return &c })
+	}
+	again, other := clone(), clone()
+
+	changes := order.Shuffle(items, 42)
+	assert.True(t, order.IsNormalized(items))
+	assert.NotEmpty(t, changes)
+	for _, change := range changes {
+		assert.NotEqual(t, change.OldPosition, change.NewPosition)
+	}
+
+	order.Shuffle(again, 42)
+	assert.Equal(t, items, again)
+	order.Shuffle(other, 7)
+	assert.NotEqual(t, items, other)
+}
+
+func TestSortByPosition(t *testing.T) {
+	items := createTestItems(4)
+	for _, item := range items {
+		item.SetPosition(1)
+	}
+	items[0].SetPosition(2)
+	byID := slices.Clone(items[1:])
+	slices.SortFunc(byID, order.TieBreakByID[*TestItem])
+
+	reversed := slices.Clone(items)
+	slices.Reverse(reversed)
+	order.SortByPosition(reversed, order.TieBreakByID[*TestItem])
+	assert.Equal(t, append(byID, items[0]), reversed)
+
+	want := []*TestItem{items[1], items[2], items[3], items[0]}
+	order.SortByPosition(items, nil)
+	assert.Equal(t, want, items)
+}
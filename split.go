@@ -0,0 +1,30 @@
+package order
+
+// SplitAt splits items after the given position: head holds the first position
+// items and tail the rest, both in order and renumbered from 1. A position
+// outside 0..len(items) is clamped. The input slice is not modified, but
+// positions are updated on the items themselves.
+func SplitAt[T Orderable](items []T, position int) (head, tail []T) {
+	position = min(max(position, 0), len(items))
+	head = append([]T(nil), items[:position]...)
+	tail = append([]T(nil), items[position:]...)
+	renumber(head)
+	renumber(tail)
+	return head, tail
+}
+
+// Partition splits items into those for which keep reports true and the rest,
+// each in their original relative order and renumbered from 1. Like SplitAt, it
+// returns new slices and updates positions on the items themselves.
+func Partition[T Orderable](items []T, keep func(T) bool) (kept, rest []T) {
+	for _, item := range items {
+		if keep(item) {
+			kept = append(kept, item)
+		} else {
+			rest = append(rest, item)
+		}
+	}
+	renumber(kept)
+	renumber(rest)
+	return kept, rest
+}
@@ -0,0 +1,38 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSplitAt(t *testing.T) {
+	items := createTestItems(5)
+	original := append([]*TestItem(nil), items...)
+
+	head, tail := order.SplitAt(items, 3)
+	assert.Equal(t, original[:3], head)
+	assert.Equal(t, original[3:], tail)
+	assert.True(t, order.IsNormalized(head))
+	assert.True(t, order.IsNormalized(tail))
+	assert.Equal(t, original, items)
+
+	head, tail = order.SplitAt(items, 9)
+	assert.Len(t, head, 5)
+	assert.Empty(t, tail)
+}
+
+func TestPartition(t *testing.T) {
+	items := createTestItems(5)
+	even := func(item *TestItem) bool { return item.GetPosition()%2 == 0 }
+	want := []*TestItem{items[1], items[3]}
+	wantRest := []*TestItem{items[0], items[2], items[4]}
+
+	kept, rest := order.Partition(items, even)
+	assert.Equal(t, want, kept)
+	assert.Equal(t, wantRest, rest)
+	assert.True(t, order.IsNormalized(kept))
+	assert.True(t, order.IsNormalized(rest))
+}
@@ -0,0 +1,64 @@
+package order
+
+import "slices"
+
+// OrderStats summarizes the positions of a list, for dashboards and for
+// deciding when to rebalance.
+type OrderStats struct {
+	// Count is the number of items.
+	Count int
+	// MinPosition and MaxPosition are the lowest and highest position, or 0
+	// for an empty list.
+	MinPosition int
+	MaxPosition int
+	// DuplicateIDs and DuplicatePositions count the items whose ID or
+	// position was already taken by an earlier item.
+	DuplicateIDs       int
+	DuplicatePositions int
+	// Gaps is the number of runs of missing positions between 1 and
+	// MaxPosition, as reported by Validate.
+	Gaps int
+	// SmallestGap is the fewest free positions between two neighboring
+	// positions: 0 means some neighbors leave no room for an insert between
+	// them without renumbering. It is -1 if there are fewer than two distinct
+	// positions.
+	SmallestGap int
+}
+
+// Stats computes OrderStats for items. The slice order does not matter.
+func Stats[T Orderable](items []T) OrderStats {
+	stats := OrderStats{Count: len(items), SmallestGap: -1}
+	ids := make(map[string]struct{}, len(items))
+	positions := make([]int, 0, len(items))
+	for _, item := range items {
+		if _, ok := ids[item.GetID()]; ok {
+			stats.DuplicateIDs++
+		}
+		ids[item.GetID()] = struct{}{}
+		positions = append(positions, item.GetPosition())
+	}
+	if len(positions) == 0 {
+		return stats
+	}
+	slices.Sort(positions)
+	stats.MinPosition, stats.MaxPosition = positions[0], positions[len(positions)-1]
+
+	previous := 0
+	for i, position := range positions {
+		if i > 0 && position == positions[i-1] {
+			stats.DuplicatePositions++
+			continue
+		}
+		if position > previous+1 {
+			stats.Gaps++
+		}
+		if i > 0 {
+			free := position - positions[i-1] - 1
+			if stats.SmallestGap < 0 || free < stats.SmallestGap {
+				stats.SmallestGap = free
+			}
+		}
+		previous = max(position, previous)
+	}
+	return stats
+}
@@ -0,0 +1,54 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStats(t *testing.T) {
+	assert.Equal(t, order.OrderStats{SmallestGap: -1}, order.Stats[*ordertest.Item](nil))
+	assert.Equal(t, order.OrderStats{Count: 3, MinPosition: 1, MaxPosition: 3}, order.Stats(ordertest.Items(3)))
+
+	items := []*ordertest.Item{
+		{ID: "a", Position: 3},
+		{ID: "b", Position: 10},
+		{ID: "c", Position: 4},
+		{ID: "a", Position: 10},
+		{ID: "d", Position: 20},
+	}
+	assert.Equal(t, order.OrderStats{
+		Count:              5,
+		MinPosition:        3,
+		MaxPosition:        20,
+		DuplicateIDs:       1,
+		DuplicatePositions: 1,
+		Gaps:               3,
+		SmallestGap:        0,
+	}, order.Stats(items))
+	assert.Equal(t, 3, len(validationGaps(items)))
+
+	gapped := ordertest.Items(3)
+	for i, item := range gapped {
+		item.Position = (i + 1) * 100
+	}
+	stats := order.Stats(gapped)
+	assert.Equal(t, 99, stats.SmallestGap)
+	assert.Equal(t, 3, stats.Gaps)
+
+	single := []*ordertest.Item{{ID: "a", Position: 5}}
+	assert.Equal(t, -1, order.Stats(single).SmallestGap)
+}
+
+func validationGaps(items []*ordertest.Item) []order.ValidationIssue {
+	var gaps []order.ValidationIssue
+	for _, issue := range order.Validate(items) {
+		if issue.Kind == order.IssueGap {
+			gaps = append(gaps, issue)
+		}
+	}
+	return gaps
+}
@@ -0,0 +1,52 @@
+package order
+
+import "context"
+
+// Store persists ordered lists. Every list carries a version that is bumped on
+// each successful write, which lets callers detect concurrent modifications.
+//
+// Implementations must be safe for concurrent use.
+type Store[T Orderable] interface {
+	// Load returns the items of a list sorted by position, together with the
+	// current version of the list. It fails with ErrListNotFound if the list
+	// does not exist.
+	Load(ctx context.Context, listID string) ([]T, int64, error)
+
+	// SavePositions applies changes to the list if it is still at
+	// expectedVersion and returns the new version. It fails with
+	// ErrVersionConflict if the list was modified in the meantime.
+	SavePositions(ctx context.Context, listID string, expectedVersion int64, changes ChangeSet) (int64, error)
+}
+
+// HealthChecker is implemented by stores that can report whether they are
+// usable, for health and readiness endpoints. Health should check what a
+// store needs to serve requests, such as the connection and the presence of
+// its tables, and return nil if it can. PersistentManager.Health calls it.
+type HealthChecker interface {
+	Health(ctx context.Context) error
+}
+
+// Wrapper is implemented by stores that wrap another Store under the same
+// list IDs, such as CachingStore and DualWriteStore. The optional interfaces
+// of a Store, such as HealthChecker, IdempotencyStore, AuditStore and
+// PageLoader, are looked up through wrappers that do not implement them
+// themselves.
+type Wrapper[T Orderable] interface {
+	Unwrap() Store[T]
+}
+
+// lookup returns store, or the first store it wraps, that implements I.
+func lookup[I any, T Orderable](store Store[T]) (I, bool) {
+	for store != nil {
+		if s, ok := any(store).(I); ok {
+			return s, true
+		}
+		w, ok := store.(Wrapper[T])
+		if !ok {
+			break
+		}
+		store = w.Unwrap()
+	}
+	var zero I
+	return zero, false
+}
@@ -0,0 +1,357 @@
+// Package storetest is a conformance suite for implementations of
+// order.Store. Run it from a test of the adapter to check the contract of
+// Load and SavePositions and to hammer the adapter with concurrent moves,
+// injected failures and restarts:
+//
+//	func TestPostgresStore(t *testing.T) {
+//		storetest.Run(t, func(t *testing.T, lists map[string][]*ordertest.Item) order.Store[*ordertest.Item] {
+//			return newTestStore(t, lists) // a fresh schema holding lists
+//		})
+//	}
+//
+// The suite works on ordertest.Item values. Movers never modify the items
+// returned by Load; they move copies and save the changes, so adapters that
+// return shared items are tested fairly.
+package storetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+)
+
+// Factory creates the store under test holding exactly the given lists, with
+// items in slice order at their current positions.
+type Factory func(t *testing.T, lists map[string][]*ordertest.Item) order.Store[*ordertest.Item]
+
+// Option configures Run.
+type Option func(*options)
+
+type options struct {
+	writers     int
+	moves       int
+	failureRate float64
+	seed        int64
+	reopen      func(t *testing.T, store order.Store[*ordertest.Item]) order.Store[*ordertest.Item]
+}
+
+// WithWriters sets how many goroutines move items concurrently in each list,
+// and how many moves each of them makes per round. The default is 8 writers
+// making 25 moves.
+func WithWriters(writers, moves int) Option {
+	return func(o *options) {
+		o.writers, o.moves = writers, moves
+	}
+}
+
+// WithFailureRate sets the fraction of calls, between 0 and 1, that fail with
+// an injected error during the concurrent tests. Half of the failed saves
+// fail before reaching the store, the other half after the store has applied
+// them, like a lost reply. The default is 0.1.
+func WithFailureRate(rate float64) Option {
+	return func(o *options) {
+		o.failureRate = rate
+	}
+}
+
+// WithSeed sets the seed of the random moves and failures. The default is 1.
+func WithSeed(seed int64) Option {
+	return func(o *options) {
+		o.seed = seed
+	}
+}
+
+// WithReopen enables the restart test: reopen must return a new instance of
+// the store that works on the same data as store, as after a restart of the
+// application. It may close store.
+func WithReopen(reopen func(t *testing.T, store order.Store[*ordertest.Item]) order.Store[*ordertest.Item]) Option {
+	return func(o *options) {
+		o.reopen = reopen
+	}
+}
+
+// errInjected is the error of calls failed by the suite.
+var errInjected = errors.New("storetest: injected failure")
+
+// Run runs the conformance suite against stores created with newStore, each
+// part in its own subtest.
+func Run(t *testing.T, newStore Factory, opts ...Option) {
+	o := options{writers: 8, moves: 25, failureRate: 0.1, seed: 1}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	t.Run("Load", func(t *testing.T) { testLoad(t, newStore) })
+	t.Run("SavePositions", func(t *testing.T) { testSavePositions(t, newStore) })
+	t.Run("VersionConflict", func(t *testing.T) { testVersionConflict(t, newStore) })
+	t.Run("ConcurrentMoves", func(t *testing.T) { testConcurrentMoves(t, newStore, o, 1) })
+	if o.reopen != nil {
+		t.Run("Restart", func(t *testing.T) { testConcurrentMoves(t, newStore, o, 3) })
+	}
+}
+
+func testLoad(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, map[string][]*ordertest.Item{
+		"list":  ordertest.Items(5),
+		"other": ordertest.ItemsWithIDs("x", "y"),
+	})
+
+	items, _, err := store.Load(ctx, "list")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3", "item-4", "item-5")
+	ordertest.AssertNormalized(t, items)
+
+	items, _, err = store.Load(ctx, "other")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	ordertest.AssertOrder(t, items, "x", "y")
+
+	if _, _, err := store.Load(ctx, "missing"); !errors.Is(err, order.ErrListNotFound) {
+		t.Errorf("Load of a missing list: got %v, want ErrListNotFound", err)
+	}
+}
+
+func testSavePositions(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, map[string][]*ordertest.Item{"list": ordertest.Items(4), "other": ordertest.Items(4)})
+
+	_, version := mustLoad(t, store, "list")
+	changes := order.ChangeSet{
+		{ItemID: "item-4", OldPosition: 4, NewPosition: 1},
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+		{ItemID: "item-2", OldPosition: 2, NewPosition: 3},
+		{ItemID: "item-3", OldPosition: 3, NewPosition: 4},
+	}
+	saved, err := store.SavePositions(ctx, "list", version, changes)
+	if err != nil {
+		t.Fatalf("SavePositions: %v", err)
+	}
+	if saved <= version {
+		t.Errorf("SavePositions returned version %d, want more than %d", saved, version)
+	}
+
+	items, loaded := mustLoad(t, store, "list")
+	if loaded != saved {
+		t.Errorf("Load returned version %d, SavePositions returned %d", loaded, saved)
+	}
+	ordertest.AssertOrder(t, items, "item-4", "item-1", "item-2", "item-3")
+	ordertest.AssertNormalized(t, items)
+
+	others, _ := mustLoad(t, store, "other")
+	ordertest.AssertOrder(t, others, "item-1", "item-2", "item-3", "item-4")
+
+	if _, err := store.SavePositions(ctx, "missing", 1, changes); !errors.Is(err, order.ErrListNotFound) {
+		t.Errorf("SavePositions of a missing list: got %v, want ErrListNotFound", err)
+	}
+}
+
+func testVersionConflict(t *testing.T, newStore Factory) {
+	ctx := context.Background()
+	store := newStore(t, map[string][]*ordertest.Item{"list": ordertest.Items(3)})
+
+	_, version := mustLoad(t, store, "list")
+	swap := order.ChangeSet{
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+		{ItemID: "item-2", OldPosition: 2, NewPosition: 1},
+	}
+	if _, err := store.SavePositions(ctx, "list", version, swap); err != nil {
+		t.Fatalf("SavePositions: %v", err)
+	}
+	_, err := store.SavePositions(ctx, "list", version, order.ChangeSet{
+		{ItemID: "item-3", OldPosition: 3, NewPosition: 1},
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+		{ItemID: "item-2", OldPosition: 2, NewPosition: 3},
+	})
+	if !errors.Is(err, order.ErrVersionConflict) {
+		t.Fatalf("SavePositions with a stale version: got %v, want ErrVersionConflict", err)
+	}
+	items, _ := mustLoad(t, store, "list")
+	ordertest.AssertOrder(t, items, "item-2", "item-1", "item-3")
+}
+
+// testConcurrentMoves runs writers on two lists through a store that fails
+// calls at random, and checks after every round that no item was lost or
+// duplicated, positions are 1..n, and the store converged. Between rounds the
+// store is reopened.
+func testConcurrentMoves(t *testing.T, newStore Factory, o options, rounds int) {
+	lists := map[string][]*ordertest.Item{"big": ordertest.Items(40), "small": ordertest.Items(3)}
+	store := newStore(t, lists)
+
+	for round := range rounds {
+		if round > 0 {
+			before := snapshot(t, store, lists)
+			store = o.reopen(t, store)
+			if after := snapshot(t, store, lists); !equalSnapshots(before, after) {
+				t.Fatalf("round %d: order changed across restart:\nbefore %v\nafter  %v", round, before, after)
+			}
+		}
+
+		chaos := &chaosStore{next: store, rate: o.failureRate, rnd: rand.New(rand.NewSource(o.seed + int64(round)))}
+		var wg sync.WaitGroup
+		for listID := range lists {
+			for w := range o.writers {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					seed := o.seed + int64(round*o.writers+w)
+					write(t, chaos, listID, o.moves, rand.New(rand.NewSource(seed)))
+				}()
+			}
+		}
+		wg.Wait()
+
+		for listID, seeded := range lists {
+			first, version := mustLoad(t, store, listID)
+			if err := order.Invariants(first); err != nil {
+				t.Errorf("round %d: list %s: %v", round, listID, err)
+			}
+			ids := ordertest.IDs(first)
+			want := ordertest.IDs(seeded)
+			slices.Sort(ids)
+			slices.Sort(want)
+			if !slices.Equal(ids, want) {
+				t.Errorf("round %d: list %s holds %v, want %v", round, listID, ids, want)
+			}
+			again, againVersion := mustLoad(t, store, listID)
+			if againVersion != version || !slices.Equal(ordertest.IDs(first), ordertest.IDs(again)) {
+				t.Errorf("round %d: list %s did not converge: two loads differ", round, listID)
+			}
+		}
+	}
+}
+
+// write makes moves random moves in a list with load, move, save cycles, and
+// starts over from a fresh load whenever a call fails.
+func write(t *testing.T, store order.Store[*ordertest.Item], listID string, moves int, rnd *rand.Rand) {
+	ctx := context.Background()
+	om := order.NewOrderManager[*ordertest.Item]()
+	for done, attempts := 0, 0; done < moves; attempts++ {
+		if attempts > moves*100 {
+			t.Errorf("list %s: writer made only %d of %d moves", listID, done, moves)
+			return
+		}
+		loaded, version, err := store.Load(ctx, listID)
+		if errors.Is(err, errInjected) {
+			continue
+		}
+		if err != nil {
+			t.Errorf("Load %s: %v", listID, err)
+			return
+		}
+		if !slices.IsSortedFunc(loaded, func(a, b *ordertest.Item) int { return a.Position - b.Position }) {
+			t.Errorf("Load %s returned items out of position order", listID)
+		}
+
+		items := make([]*ordertest.Item, len(loaded))
+		before := make(map[string]int, len(loaded))
+		for i, item := range loaded {
+			copied := *item
+			items[i] = &copied
+			before[item.ID] = item.Position
+		}
+		result, err := om.Apply(items, randomMove(rnd, items))
+		if err != nil || !result.Changed {
+			done++
+			continue
+		}
+		changes := make(order.ChangeSet, len(result.Affected))
+		for i, item := range result.Affected {
+			changes[i] = order.PositionChange{ItemID: item.ID, OldPosition: before[item.ID], NewPosition: item.Position}
+		}
+
+		saved, err := store.SavePositions(ctx, listID, version, changes)
+		switch {
+		case err == nil:
+			if saved <= version {
+				t.Errorf("SavePositions %s returned version %d after %d", listID, saved, version)
+			}
+			done++
+		case errors.Is(err, errInjected), errors.Is(err, order.ErrVersionConflict):
+		default:
+			t.Errorf("SavePositions %s: %v", listID, err)
+			return
+		}
+	}
+}
+
+func randomMove(rnd *rand.Rand, items []*ordertest.Item) order.Move[string] {
+	return order.Move[string]{
+		Kind:     order.MoveKind(1 + rnd.Intn(int(order.MoveBelow))),
+		ItemID:   items[rnd.Intn(len(items))].ID,
+		TargetID: items[rnd.Intn(len(items))].ID,
+		Position: 1 + rnd.Intn(len(items)),
+	}
+}
+
+func mustLoad(t *testing.T, store order.Store[*ordertest.Item], listID string) ([]*ordertest.Item, int64) {
+	t.Helper()
+	items, version, err := store.Load(context.Background(), listID)
+	if err != nil {
+		t.Fatalf("Load %s: %v", listID, err)
+	}
+	return items, version
+}
+
+func snapshot(t *testing.T, store order.Store[*ordertest.Item], lists map[string][]*ordertest.Item) map[string][]string {
+	snap := make(map[string][]string, len(lists))
+	for listID := range lists {
+		items, _ := mustLoad(t, store, listID)
+		snap[listID] = ordertest.IDs(items)
+	}
+	return snap
+}
+
+func equalSnapshots(a, b map[string][]string) bool {
+	for listID, ids := range a {
+		if !slices.Equal(ids, b[listID]) {
+			return false
+		}
+	}
+	return len(a) == len(b)
+}
+
+// chaosStore fails a fraction of the calls to next with errInjected.
+type chaosStore struct {
+	next order.Store[*ordertest.Item]
+	rate float64
+
+	mu  sync.Mutex
+	rnd *rand.Rand
+}
+
+func (s *chaosStore) roll() float64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.rnd.Float64()
+}
+
+func (s *chaosStore) Load(ctx context.Context, listID string) ([]*ordertest.Item, int64, error) {
+	if s.roll() < s.rate {
+		return nil, 0, fmt.Errorf("Load %s: %w", listID, errInjected)
+	}
+	return s.next.Load(ctx, listID)
+}
+
+func (s *chaosStore) SavePositions(ctx context.Context, listID string, expectedVersion int64, changes order.ChangeSet) (int64, error) {
+	roll := s.roll()
+	if roll < s.rate/2 {
+		return 0, fmt.Errorf("SavePositions %s: %w", listID, errInjected)
+	}
+	if roll < s.rate {
+		// The store applies the changes, but the reply gets lost.
+		_, _ = s.next.SavePositions(ctx, listID, expectedVersion, changes)
+		return 0, fmt.Errorf("SavePositions %s: %w", listID, errInjected)
+	}
+	return s.next.SavePositions(ctx, listID, expectedVersion, changes)
+}
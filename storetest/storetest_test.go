@@ -0,0 +1,30 @@
+package storetest_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/memstore"
+	"github.com/yacobolo/order/ordertest"
+	"github.com/yacobolo/order/storetest"
+)
+
+func cloneItem(item *ordertest.Item) *ordertest.Item {
+	copied := *item
+	return &copied
+}
+
+func TestMemstore(t *testing.T) {
+	storetest.Run(t, func(t *testing.T, lists map[string][]*ordertest.Item) order.Store[*ordertest.Item] {
+		store := memstore.New(memstore.WithClone(cloneItem))
+		for listID, items := range lists {
+			store.Put(listID, items)
+		}
+		return store
+	}, storetest.WithReopen(func(t *testing.T, store order.Store[*ordertest.Item]) order.Store[*ordertest.Item] {
+		// A restart keeps the data but not the store value.
+		reopened := memstore.New(memstore.WithClone(cloneItem))
+		reopened.Restore(store.(*memstore.Store[*ordertest.Item]).Snapshot())
+		return reopened
+	}))
+}
@@ -0,0 +1,230 @@
+package order
+
+import (
+	"context"
+	"sync"
+)
+
+// Syncer serves a delta sync protocol for clients that reorder offline. A
+// client remembers the version of the list it last saw, queues its moves while
+// offline, and then sends them in a SyncRequest. The Syncer applies the moves
+// and answers with the changes from the client's version to the current one,
+// computed with Diff, so that the client does not need to fetch the whole list
+// again.
+//
+// The Syncer remembers the order at every version it has answered with, up to
+// a history depth per list, together with its Hash. A client whose order is
+// not remembered receives the full order instead. With a PersistentManager in
+// write-behind mode the version only moves when changes are saved, so clients
+// should send the Hash they last received, which tells their orders apart
+// where the version does not.
+type Syncer[T Orderable] struct {
+	manager *PersistentManager[T]
+	opts    syncOptions
+
+	mu      sync.Mutex
+	history map[string][]syncedVersion
+}
+
+type syncedVersion struct {
+	version  int64
+	hash     string
+	snapshot OrderSnapshot
+}
+
+// SyncOption configures a Syncer.
+type SyncOption func(*syncOptions)
+
+type syncOptions struct {
+	depth    int
+	resolver ConflictResolver
+	// retry is nil until set with WithSyncRetry.
+	retry *RetryPolicy
+}
+
+// WithSyncHistory sets how many versions of each list the Syncer remembers.
+// The default is 100.
+func WithSyncHistory(depth int) SyncOption {
+	return func(o *syncOptions) {
+		o.depth = depth
+	}
+}
+
+// WithSyncRetry sets how client moves that fail with ErrVersionConflict are
+// retried before the resolver sees the conflict, in place of the WithRetry
+// policy of the manager. By default the policy of the manager is used, or a
+// single immediate retry if it has none.
+func WithSyncRetry(policy RetryPolicy) SyncOption {
+	return func(o *syncOptions) {
+		o.retry = &policy
+	}
+}
+
+// WithSyncResolver lets resolver decide about client moves that conflict with
+// the current list, typically because the item or target was removed in the
+// meantime; see ConflictResolver. It takes the place of the resolver of the
+// manager, which is used otherwise. Moves the resolver drops are not reported
+// as rejected. Without any resolver, conflicting moves are rejected.
+func WithSyncResolver(resolver ConflictResolver) SyncOption {
+	return func(o *syncOptions) {
+		o.resolver = resolver
+	}
+}
+
+// WithConflictHook is WithSyncResolver with MergeByRule: hook can return a
+// replacement for a conflicting move, or false to reject it.
+func WithConflictHook(hook func(listID string, m Move[string], err error) (Move[string], bool)) SyncOption {
+	return WithSyncResolver(MergeByRule(func(c Conflict) (Move[string], bool) {
+		return hook(c.ListID, c.Move, c.Err)
+	}))
+}
+
+// SyncRequest carries the moves a client made since it last synced.
+type SyncRequest struct {
+	ListID string `json:"list_id"`
+	// BaseVersion is the version of the list the client last received.
+	BaseVersion int64 `json:"base_version"`
+	// BaseHash is the hash of the order the client last received. If it is
+	// set, it selects that order instead of BaseVersion.
+	BaseHash string `json:"base_hash,omitempty"`
+	// Moves are the client's moves in the order they were made.
+	Moves []Move[string] `json:"moves"`
+}
+
+// SyncResponse brings a client up to date.
+type SyncResponse struct {
+	// Version is the current version of the list.
+	Version int64 `json:"version"`
+	// Hash is the OrderSnapshot.Hash of the current order, for the BaseHash
+	// of the next request.
+	Hash string `json:"hash"`
+	// Changes turn the order at the client's BaseVersion into the current
+	// order, see Diff. They include the effect of the client's own moves and
+	// apply to the order the client had at BaseVersion.
+	Changes ChangeSet `json:"changes,omitempty"`
+	// Full is set when BaseVersion is not remembered; IDs then holds the
+	// complete current order and Changes is empty.
+	Full bool     `json:"full,omitempty"`
+	IDs  []string `json:"ids,omitempty"`
+	// Applied lists the client moves that were applied, with their operation
+	// IDs, so that the client can match them to broadcasts of the moves.
+	Applied []AppliedMove `json:"applied,omitempty"`
+	// Rejected lists the client moves that could not be applied.
+	Rejected []RejectedMove `json:"rejected,omitempty"`
+}
+
+// AppliedMove is a client move that was applied, see PersistedResult.OperationID.
+type AppliedMove struct {
+	Move        Move[string] `json:"move"`
+	OperationID string       `json:"operation_id"`
+}
+
+// RejectedMove is a client move that could not be applied.
+type RejectedMove struct {
+	Move  Move[string] `json:"move"`
+	Error string       `json:"error"`
+}
+
+// NewSyncer creates a Syncer that applies moves with manager.
+func NewSyncer[T Orderable](manager *PersistentManager[T], opts ...SyncOption) *Syncer[T] {
+	s := &Syncer[T]{manager: manager, history: make(map[string][]syncedVersion)}
+	s.opts.depth = 100
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+	if s.opts.retry == nil {
+		s.opts.retry = &manager.opts.retry
+		if s.opts.retry.Attempts < 2 {
+			s.opts.retry = &RetryPolicy{Attempts: 2}
+		}
+	}
+	if s.opts.resolver == nil {
+		s.opts.resolver = manager.opts.resolver
+	}
+	return s
+}
+
+// Sync applies the moves of req and returns the changes since req.BaseVersion.
+// It fails only if the list cannot be loaded or saved; moves that conflict
+// with the current list are reported in SyncResponse.Rejected.
+func (s *Syncer[T]) Sync(ctx context.Context, req SyncRequest) (SyncResponse, error) {
+	items, version, err := s.manager.Load(ctx, req.ListID)
+	if err != nil {
+		return SyncResponse{}, err
+	}
+	s.remember(req.ListID, version, Snapshot(items))
+
+	var resp SyncResponse
+	for _, m := range req.Moves {
+		operationID, err := s.apply(ctx, req.ListID, m)
+		switch {
+		case err != nil:
+			resp.Rejected = append(resp.Rejected, RejectedMove{Move: m, Error: err.Error()})
+		case operationID != "":
+			resp.Applied = append(resp.Applied, AppliedMove{Move: m, OperationID: operationID})
+		}
+	}
+
+	items, resp.Version, err = s.manager.Load(ctx, req.ListID)
+	if err != nil {
+		return SyncResponse{}, err
+	}
+	current := Snapshot(items)
+	resp.Hash = current.Hash()
+	s.remember(req.ListID, resp.Version, current)
+	if base, ok := s.lookup(req.ListID, req.BaseVersion, req.BaseHash); ok {
+		resp.Changes = Diff(base, current)
+	} else {
+		resp.Full, resp.IDs = true, current.IDs
+	}
+	return resp, nil
+}
+
+// apply performs a move, retrying it if another writer got in between, and
+// hands remaining conflicts to the resolver. The retry policy and resolver of
+// the Syncer replace those of the manager, so that a conflict goes through
+// each only once. It returns the operation ID of the move, which is empty if
+// the resolver dropped it.
+func (s *Syncer[T]) apply(ctx context.Context, listID string, m Move[string]) (string, error) {
+	result, err := s.manager.applyWith(ctx, listID, m, *s.opts.retry, s.opts.resolver)
+	return result.OperationID, err
+}
+
+// remember records snap as the order at version. Under write-behind one
+// version can have several orders, which are told apart by their hashes.
+func (s *Syncer[T]) remember(listID string, version int64, snap OrderSnapshot) {
+	hash := snap.Hash()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	versions := s.history[listID]
+	for _, v := range versions {
+		if v.version == version && v.hash == hash {
+			return
+		}
+	}
+	versions = append(versions, syncedVersion{version: version, hash: hash, snapshot: snap})
+	if len(versions) > s.opts.depth {
+		versions = versions[len(versions)-s.opts.depth:]
+	}
+	s.history[listID] = versions
+}
+
+// lookup returns the remembered order with hash or, without a hash, the one
+// at version. A version with more than one remembered order is not found.
+func (s *Syncer[T]) lookup(listID string, version int64, hash string) (OrderSnapshot, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var found []OrderSnapshot
+	for _, v := range s.history[listID] {
+		if hash != "" && v.hash == hash {
+			return v.snapshot, true
+		}
+		if hash == "" && v.version == version {
+			found = append(found, v.snapshot)
+		}
+	}
+	if len(found) != 1 {
+		return OrderSnapshot{}, false
+	}
+	return found[0], true
+}
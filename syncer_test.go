@@ -0,0 +1,170 @@
+package order_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/mockstore"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newSyncer(n int, opts ...order.SyncOption) *order.Syncer[*ordertest.Item] {
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(n))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	return order.NewSyncer(pm, opts...)
+}
+
+func TestSyncerReturnsDelta(t *testing.T) {
+	ctx := context.Background()
+	s := newSyncer(4)
+
+	resp, err := s.Sync(ctx, order.SyncRequest{ListID: "list", BaseVersion: 1})
+	require.NoError(t, err)
+	assert.Equal(t, order.SyncResponse{Version: 1, Hash: order.HashIDs([]string{"item-1", "item-2", "item-3", "item-4"})}, resp)
+
+	// Another client moves item-4 to the top.
+	resp, err = s.Sync(ctx, order.SyncRequest{ListID: "list", BaseVersion: 1, Moves: []order.Move[string]{
+		{Kind: order.MoveTop, ItemID: "item-4"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), resp.Version)
+
+	// The offline client still has version 1 and moved item-1 to the bottom.
+	resp, err = s.Sync(ctx, order.SyncRequest{ListID: "list", BaseVersion: 1, Moves: []order.Move[string]{
+		{Kind: order.MoveBottom, ItemID: "item-1"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), resp.Version)
+	assert.False(t, resp.Full)
+	// From item-1..4 to item-4, item-2, item-3, item-1.
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "item-4", OldPosition: 4, NewPosition: 1},
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 4},
+	}, resp.Changes)
+}
+
+func TestSyncerWriteBehind(t *testing.T) {
+	ctx := context.Background()
+	store := ordertest.NewStore[*ordertest.Item]()
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](), order.WithWriteBehind(time.Hour, 0))
+	defer pm.Close(ctx)
+	s := order.NewSyncer(pm)
+
+	first, err := s.Sync(ctx, order.SyncRequest{ListID: "list", BaseVersion: 1, Moves: []order.Move[string]{
+		{Kind: order.MoveTop, ItemID: "item-3"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), first.Version, "the move is not saved yet")
+
+	// The version did not move, the hash tells the client's order apart.
+	resp, err := s.Sync(ctx, order.SyncRequest{ListID: "list", BaseVersion: 1, BaseHash: first.Hash, Moves: []order.Move[string]{
+		{Kind: order.MoveBottom, ItemID: "item-1"},
+	}})
+	require.NoError(t, err)
+	assert.False(t, resp.Full)
+	assert.ElementsMatch(t, order.ChangeSet{
+		{ItemID: "item-1", OldPosition: 2, NewPosition: 3},
+		{ItemID: "item-2", OldPosition: 3, NewPosition: 2},
+	}, resp.Changes)
+
+	// Without a hash, version 1 has several orders.
+	resp, err = s.Sync(ctx, order.SyncRequest{ListID: "list", BaseVersion: 1})
+	require.NoError(t, err)
+	assert.True(t, resp.Full)
+	assert.Equal(t, []string{"item-3", "item-2", "item-1"}, resp.IDs)
+}
+
+func TestSyncerFullResponseForUnknownVersion(t *testing.T) {
+	s := newSyncer(3)
+	resp, err := s.Sync(context.Background(), order.SyncRequest{ListID: "list", BaseVersion: 42})
+	require.NoError(t, err)
+	assert.True(t, resp.Full)
+	assert.Equal(t, []string{"item-1", "item-2", "item-3"}, resp.IDs)
+
+	_, err = s.Sync(context.Background(), order.SyncRequest{ListID: "missing"})
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+}
+
+func TestSyncerConflicts(t *testing.T) {
+	ctx := context.Background()
+	moves := []order.Move[string]{
+		{Kind: order.MoveAbove, ItemID: "item-3", TargetID: "deleted"},
+		{Kind: order.MoveTop, ItemID: "deleted"},
+	}
+
+	resp, err := newSyncer(3).Sync(ctx, order.SyncRequest{ListID: "list", BaseVersion: 1, Moves: moves})
+	require.NoError(t, err)
+	assert.Len(t, resp.Rejected, 2)
+	assert.Empty(t, resp.Changes)
+
+	// The hook turns moves relative to a missing target into moves to the top.
+	hook := func(listID string, m order.Move[string], err error) (order.Move[string], bool) {
+		var notFound *order.NotFoundError
+		if errors.As(err, &notFound) && notFound.ItemID == m.TargetID {
+			return order.Move[string]{Kind: order.MoveTop, ItemID: m.ItemID}, true
+		}
+		return m, false
+	}
+	resp, err = newSyncer(3, order.WithConflictHook(hook)).Sync(ctx, order.SyncRequest{ListID: "list", BaseVersion: 1, Moves: moves})
+	require.NoError(t, err)
+	require.Len(t, resp.Rejected, 1)
+	assert.Equal(t, "deleted", resp.Rejected[0].Move.ItemID)
+	require.Len(t, resp.Applied, 1)
+	assert.Equal(t, moves[0], resp.Applied[0].Move)
+	assert.NotEmpty(t, resp.Applied[0].OperationID)
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "item-3", OldPosition: 3, NewPosition: 1},
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 2},
+		{ItemID: "item-2", OldPosition: 2, NewPosition: 3},
+	}, resp.Changes)
+}
+
+func TestSyncerRetry(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item]())
+	moves := []order.Move[string]{{Kind: order.MoveTop, ItemID: "item-3"}}
+
+	store.FailNext(mockstore.SavePositions, order.ErrVersionConflict)
+	resp, err := order.NewSyncer(pm).Sync(ctx, order.SyncRequest{ListID: "list", Moves: moves})
+	require.NoError(t, err)
+	assert.Len(t, resp.Applied, 1, "retried once by default")
+
+	store.FailNext(mockstore.SavePositions, order.ErrVersionConflict)
+	moves[0].Kind = order.MoveBottom
+	resp, err = order.NewSyncer(pm, order.WithSyncRetry(order.RetryPolicy{Attempts: 1})).Sync(ctx, order.SyncRequest{ListID: "list", Moves: moves})
+	require.NoError(t, err)
+	assert.Len(t, resp.Rejected, 1)
+}
+
+func TestSyncerRetriesAndResolvesOnce(t *testing.T) {
+	ctx := context.Background()
+	store := mockstore.New[*ordertest.Item](nil)
+	store.Put("list", ordertest.Items(3))
+	for range 10 {
+		store.FailNext(mockstore.SavePositions, order.ErrVersionConflict)
+	}
+	var resolved int
+	resolver := order.ConflictResolverFunc(func(context.Context, order.Conflict) (order.Resolution, error) {
+		resolved++
+		return order.Resolution{Kind: order.DropMove}, nil
+	})
+	pm := order.NewPersistentManager[*ordertest.Item](store, order.NewOrderManager[*ordertest.Item](),
+		order.WithRetry(order.RetryPolicy{Attempts: 3}), order.WithConflictResolver(resolver))
+
+	resp, err := order.NewSyncer(pm).Sync(ctx, order.SyncRequest{ListID: "list", Moves: []order.Move[string]{{Kind: order.MoveTop, ItemID: "item-3"}}})
+	require.NoError(t, err)
+	assert.Empty(t, resp.Applied)
+	assert.Empty(t, resp.Rejected, "the manager's resolver dropped the move")
+	assert.Equal(t, 3, store.CallCount(mockstore.SavePositions))
+	assert.Equal(t, 1, resolved)
+}
@@ -0,0 +1,175 @@
+package order
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Tagged adapts a pointer to a struct whose fields carry `order:"id"` and
+// `order:"position"` tags, making it Orderable without writing methods. It is
+// meant for generated models (protobuf, sqlc, OpenAPI) that cannot be extended.
+//
+// The ID field may be a string, any integer type or implement fmt.Stringer;
+// the position field must be an integer type. Use Tag to wrap a slice.
+type Tagged[S any] struct {
+	Value *S
+	plan  *tagPlan
+}
+
+// Tag wraps values for use with an OrderManager[Tagged[S]]. It fails with
+// ErrInvalidTags if S is not a struct with usable `order` tags.
+func Tag[S any](values []*S) ([]Tagged[S], error) {
+	plan, err := planFor(reflect.TypeFor[S]())
+	if err != nil {
+		return nil, err
+	}
+	tagged := make([]Tagged[S], len(values))
+	for i, v := range values {
+		tagged[i] = Tagged[S]{Value: v, plan: plan}
+	}
+	return tagged, nil
+}
+
+// Untag returns the wrapped values in the order of tagged.
+func Untag[S any](tagged []Tagged[S]) []*S {
+	values := make([]*S, len(tagged))
+	for i, t := range tagged {
+		values[i] = t.Value
+	}
+	return values
+}
+
+// GetID implements Orderable.
+func (t Tagged[S]) GetID() string {
+	return t.plan.id(reflect.ValueOf(t.Value).Elem())
+}
+
+// GetPosition implements Orderable.
+func (t Tagged[S]) GetPosition() int {
+	return int(t.plan.position(reflect.ValueOf(t.Value).Elem()).Int())
+}
+
+// SetPosition implements Orderable.
+func (t Tagged[S]) SetPosition(position int) {
+	t.plan.position(reflect.ValueOf(t.Value).Elem()).SetInt(int64(position))
+}
+
+// Clone implements Cloner with a shallow copy of the wrapped struct.
+func (t Tagged[S]) Clone() Tagged[S] {
+	v := *t.Value
+	return Tagged[S]{Value: &v, plan: t.plan}
+}
+
+// tagPlan caches where the tagged fields of a struct type live.
+type tagPlan struct {
+	idIndex       []int
+	positionIndex []int
+	idString      func(reflect.Value) string
+	positionInt   bool
+}
+
+var tagPlans sync.Map // reflect.Type -> *tagPlan
+
+func planFor(t reflect.Type) (*tagPlan, error) {
+	if cached, ok := tagPlans.Load(t); ok {
+		return cached.(*tagPlan), nil
+	}
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("%w: %s is not a struct", ErrInvalidTags, t)
+	}
+
+	plan := &tagPlan{}
+	for _, field := range reflect.VisibleFields(t) {
+		tag := strings.TrimSpace(field.Tag.Get("order"))
+		if tag != "id" && tag != "position" {
+			continue
+		}
+		if !field.IsExported() {
+			return nil, fmt.Errorf("%w: %s field %s.%s is not exported", ErrInvalidTags, tag, t, field.Name)
+		}
+		if throughPointer(t, field.Index) {
+			return nil, fmt.Errorf("%w: %s field %s.%s is promoted through an embedded pointer", ErrInvalidTags, tag, t, field.Name)
+		}
+		switch tag {
+		case "id":
+			if plan.idIndex != nil {
+				return nil, fmt.Errorf("%w: %s has more than one id field", ErrInvalidTags, t)
+			}
+			idString := idFormatter(field.Type)
+			if idString == nil {
+				return nil, fmt.Errorf("%w: id field %s.%s has unsupported type %s", ErrInvalidTags, t, field.Name, field.Type)
+			}
+			plan.idIndex, plan.idString = field.Index, idString
+		case "position":
+			if plan.positionIndex != nil {
+				return nil, fmt.Errorf("%w: %s has more than one position field", ErrInvalidTags, t)
+			}
+			if !isSignedInt(field.Type.Kind()) {
+				return nil, fmt.Errorf("%w: position field %s.%s must be a signed integer, not %s", ErrInvalidTags, t, field.Name, field.Type)
+			}
+			plan.positionIndex = field.Index
+		}
+	}
+	if plan.idIndex == nil || plan.positionIndex == nil {
+		return nil, fmt.Errorf("%w: %s needs fields tagged `order:\"id\"` and `order:\"position\"`", ErrInvalidTags, t)
+	}
+
+	cached, _ := tagPlans.LoadOrStore(t, plan)
+	return cached.(*tagPlan), nil
+}
+
+// throughPointer reports whether the field of t at index is reached through
+// an embedded pointer, which may be nil.
+func throughPointer(t reflect.Type, index []int) bool {
+	for _, i := range index[:len(index)-1] {
+		t = t.Field(i).Type
+		if t.Kind() == reflect.Pointer {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *tagPlan) id(v reflect.Value) string {
+	return p.idString(v.FieldByIndex(p.idIndex))
+}
+
+func (p *tagPlan) position(v reflect.Value) reflect.Value {
+	return v.FieldByIndex(p.positionIndex)
+}
+
+var stringerType = reflect.TypeFor[fmt.Stringer]()
+
+func idFormatter(t reflect.Type) func(reflect.Value) string {
+	switch {
+	case t.Implements(stringerType):
+		return func(v reflect.Value) string { return v.Interface().(fmt.Stringer).String() }
+	case t.Kind() == reflect.String:
+		return func(v reflect.Value) string { return v.String() }
+	case isSignedInt(t.Kind()):
+		return func(v reflect.Value) string { return strconv.FormatInt(v.Int(), 10) }
+	case isUnsignedInt(t.Kind()):
+		return func(v reflect.Value) string { return strconv.FormatUint(v.Uint(), 10) }
+	default:
+		return nil
+	}
+}
+
+func isSignedInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUnsignedInt(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
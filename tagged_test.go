@@ -0,0 +1,97 @@
+package order_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// GeneratedModel stands in for a generated type that cannot be given methods.
+type GeneratedModel struct {
+	Key   uuid.UUID `order:"id"`
+	Rank  int32     `order:"position"`
+	Title string
+}
+
+type embeddedBase struct {
+	ID int64 `order:"id"`
+}
+
+type EmbeddingModel struct {
+	embeddedBase
+	Sort int `order:"position"`
+}
+
+func TestTagged(t *testing.T) {
+	models := []*GeneratedModel{
+		{Key: uuid.New(), Rank: 1, Title: "A"},
+		{Key: uuid.New(), Rank: 2, Title: "B"},
+		{Key: uuid.New(), Rank: 3, Title: "C"},
+	}
+	tagged, err := order.Tag(models)
+	require.NoError(t, err)
+
+	os := order.NewOrderManager[order.Tagged[GeneratedModel]]()
+	_, err = os.Top(tagged, models[2].Key.String())
+	require.NoError(t, err)
+
+	got := order.Untag(tagged)
+	assert.Equal(t, []*GeneratedModel{models[2], models[0], models[1]}, got)
+	assert.Equal(t, int32(1), models[2].Rank)
+	assert.Equal(t, int32(3), models[1].Rank)
+}
+
+func TestTaggedEmbeddedIntegerID(t *testing.T) {
+	models := []*EmbeddingModel{
+		{embeddedBase: embeddedBase{ID: 10}, Sort: 1},
+		{embeddedBase: embeddedBase{ID: 20}, Sort: 2},
+	}
+	tagged, err := order.Tag(models)
+	require.NoError(t, err)
+
+	assert.Equal(t, "20", tagged[1].GetID())
+	_, err = order.NewOrderManager[order.Tagged[EmbeddingModel]]().Up(tagged, "20")
+	require.NoError(t, err)
+	assert.Equal(t, 1, models[1].Sort)
+}
+
+func TestTagInvalid(t *testing.T) {
+	type noTags struct{ ID string }
+	_, err := order.Tag([]*noTags{{ID: "a"}})
+	assert.True(t, errors.Is(err, order.ErrInvalidTags))
+
+	type badPosition struct {
+		ID  string  `order:"id"`
+		Pos float64 `order:"position"`
+	}
+	_, err = order.Tag([]*badPosition{})
+	assert.True(t, errors.Is(err, order.ErrInvalidTags))
+
+	_, err = order.Tag([]*string{})
+	assert.True(t, errors.Is(err, order.ErrInvalidTags))
+}
+
+type pointerBase struct {
+	ID string `order:"id"`
+}
+
+func TestTagRejectsFieldsItCannotReach(t *testing.T) {
+	type unexported struct {
+		ID  string `order:"id"`
+		pos int    `order:"position"`
+	}
+	_, err := order.Tag([]*unexported{{ID: "a"}})
+	assert.ErrorIs(t, err, order.ErrInvalidTags)
+
+	type embedsPointer struct {
+		*pointerBase
+		Pos int `order:"position"`
+	}
+	_, err = order.Tag([]*embedsPointer{{Pos: 1}})
+	assert.ErrorIs(t, err, order.ErrInvalidTags)
+}
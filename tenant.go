@@ -0,0 +1,165 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"sync"
+)
+
+// TenantStore is a Store whose lists are partitioned by tenant. List IDs only
+// need to be unique within a tenant.
+type TenantStore[T Orderable] interface {
+	Load(ctx context.Context, tenantID, listID string) ([]T, int64, error)
+	SavePositions(ctx context.Context, tenantID, listID string, expectedVersion int64, changes ChangeSet) (int64, error)
+}
+
+// TenantScoped is implemented by items that know the tenant they belong to.
+// TenantManager uses it to reject moves that reference another tenant's items.
+type TenantScoped interface {
+	GetTenantID() string
+}
+
+// ForTenant returns a Store that reads and writes the lists of one tenant.
+func ForTenant[T Orderable](store TenantStore[T], tenantID string) Store[T] {
+	return tenantView[T]{store: store, tenantID: tenantID}
+}
+
+type tenantView[T Orderable] struct {
+	store    TenantStore[T]
+	tenantID string
+}
+
+func (v tenantView[T]) Load(ctx context.Context, listID string) ([]T, int64, error) {
+	return v.store.Load(ctx, v.tenantID, listID)
+}
+
+func (v tenantView[T]) SavePositions(ctx context.Context, listID string, expectedVersion int64, changes ChangeSet) (int64, error) {
+	return v.store.SavePositions(ctx, v.tenantID, listID, expectedVersion, changes)
+}
+
+// Health implements HealthChecker if the TenantStore does. A tenantView does
+// not implement Wrapper, since the lists of the TenantStore are keyed by
+// tenant as well.
+func (v tenantView[T]) Health(ctx context.Context) error {
+	if checker, ok := v.store.(HealthChecker); ok {
+		return checker.Health(ctx)
+	}
+	return nil
+}
+
+// Namespaced turns a Store into a TenantStore by storing each list under the
+// key returned by TenantListID. It lets a single-keyed Store hold the lists of
+// many tenants.
+func Namespaced[T Orderable](store Store[T]) TenantStore[T] {
+	return namespaced[T]{store: store}
+}
+
+type namespaced[T Orderable] struct {
+	store Store[T]
+}
+
+func (n namespaced[T]) Load(ctx context.Context, tenantID, listID string) ([]T, int64, error) {
+	return n.store.Load(ctx, TenantListID(tenantID, listID))
+}
+
+func (n namespaced[T]) SavePositions(ctx context.Context, tenantID, listID string, expectedVersion int64, changes ChangeSet) (int64, error) {
+	return n.store.SavePositions(ctx, TenantListID(tenantID, listID), expectedVersion, changes)
+}
+
+// Health implements HealthChecker with the check of the Store, if it has one.
+func (n namespaced[T]) Health(ctx context.Context) error {
+	if checker, ok := lookup[HealthChecker](n.store); ok {
+		return checker.Health(ctx)
+	}
+	return nil
+}
+
+// TenantListID returns the key of a tenant's list in a single-keyed Store:
+// the escaped tenant ID, a slash and the list ID. Escaping keeps keys of
+// different tenants distinct even if tenant IDs contain slashes.
+func TenantListID(tenantID, listID string) string {
+	return url.PathEscape(tenantID) + "/" + listID
+}
+
+// TenantManager applies moves to the lists of many tenants in a TenantStore.
+// Every tenant gets its own PersistentManager, created on first use with the
+// options of the TenantManager, so retries, conflict resolution, write-behind,
+// observers, auditing and idempotency work as they do for a single tenant.
+type TenantManager[T Orderable] struct {
+	store   TenantStore[T]
+	manager *OrderManager[T]
+	opts    []PersistentOption
+
+	mu      sync.Mutex
+	tenants map[string]*PersistentManager[T]
+}
+
+// NewTenantManager creates a TenantManager that loads and saves lists in store
+// and moves items with manager.
+func NewTenantManager[T Orderable](store TenantStore[T], manager *OrderManager[T], opts ...PersistentOption) *TenantManager[T] {
+	return &TenantManager[T]{store: store, manager: manager, opts: opts, tenants: make(map[string]*PersistentManager[T])}
+}
+
+// Tenant returns the PersistentManager of the lists of tenantID. If the items
+// implement TenantScoped, its moves whose item or target belongs to another
+// tenant fail with a *NotFoundError, so that tenants cannot learn about each
+// other's items. Its events carry the tenant ID, and idempotency keys are
+// scoped to the tenant.
+func (tm *TenantManager[T]) Tenant(tenantID string) *PersistentManager[T] {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	if pm, ok := tm.tenants[tenantID]; ok {
+		return pm
+	}
+	pm := NewPersistentManager(ForTenant(tm.store, tenantID), tm.manager, tm.opts...)
+	pm.tenantID = tenantID
+	pm.check = func(items []T, m Move[string]) error {
+		return CheckTenant(items, tenantID, m)
+	}
+	tm.tenants[tenantID] = pm
+	return pm
+}
+
+// Load returns the items and version of a tenant's list.
+func (tm *TenantManager[T]) Load(ctx context.Context, tenantID, listID string) ([]T, int64, error) {
+	return tm.Tenant(tenantID).Load(ctx, listID)
+}
+
+// Apply performs m on a tenant's list with PersistentManager.Apply.
+func (tm *TenantManager[T]) Apply(ctx context.Context, tenantID, listID string, m Move[string]) (PersistedResult[T], error) {
+	return tm.Tenant(tenantID).Apply(ctx, listID, m)
+}
+
+// Close closes the managers of all tenants, see PersistentManager.Close.
+func (tm *TenantManager[T]) Close(ctx context.Context) error {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+	var errs []error
+	for _, pm := range tm.tenants {
+		errs = append(errs, pm.Close(ctx))
+	}
+	return errors.Join(errs...)
+}
+
+// CheckTenant reports a *NotFoundError if the item or target of m is an item
+// of items that implements TenantScoped and belongs to a tenant other than
+// tenantID. IDs that are not in items are left for the move to report.
+func CheckTenant[T Orderable](items []T, tenantID string, m Move[string]) error {
+	ids := []string{m.ItemID}
+	if m.Kind == MoveAbove || m.Kind == MoveBelow {
+		ids = append(ids, m.TargetID)
+	}
+	for _, item := range items {
+		scoped, ok := any(item).(TenantScoped)
+		if !ok || scoped.GetTenantID() == tenantID {
+			continue
+		}
+		for _, id := range ids {
+			if item.GetID() == id {
+				return &NotFoundError{Op: m.Kind.String(), ItemID: id}
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,112 @@
+package order_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TenantItem extends TestItem with the tenant it belongs to.
+type TenantItem struct {
+	TestItem
+	TenantID string
+}
+
+func (ti *TenantItem) GetTenantID() string {
+	return ti.TenantID
+}
+
+func TestTenantListID(t *testing.T) {
+	assert.Equal(t, "acme/board", order.TenantListID("acme", "board"))
+	assert.NotEqual(t, order.TenantListID("a/b", "c"), order.TenantListID("a", "b/c"))
+}
+
+func TestTenantManager(t *testing.T) {
+	ctx := context.Background()
+	backend := ordertest.NewStore[*ordertest.Item]()
+	backend.Put(order.TenantListID("acme", "board"), ordertest.Items(3))
+	backend.Put(order.TenantListID("globex", "board"), ordertest.Items(2))
+	tm := order.NewTenantManager(order.Namespaced[*ordertest.Item](backend), order.NewOrderManager[*ordertest.Item]())
+
+	result, err := tm.Apply(ctx, "acme", "board", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.True(t, result.Changed)
+
+	acme, _, err := tm.Load(ctx, "acme", "board")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, acme, "item-3", "item-1", "item-2")
+	globex, _, err := tm.Load(ctx, "globex", "board")
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, globex, "item-1", "item-2")
+
+	_, err = tm.Apply(ctx, "initech", "board", order.Move[string]{Kind: order.MoveTop, ItemID: "item-1"})
+	assert.ErrorIs(t, err, order.ErrListNotFound)
+}
+
+func TestTenantManagerRejectsCrossTenantTargets(t *testing.T) {
+	ctx := context.Background()
+	base := createTestItems(3)
+	a, b, x := base[0].GetID(), base[1].GetID(), base[2].GetID()
+	backend := ordertest.NewStore[*TenantItem]()
+	backend.Put(order.TenantListID("acme", "shared"), []*TenantItem{
+		{TestItem: *base[0], TenantID: "acme"},
+		{TestItem: *base[1], TenantID: "acme"},
+		{TestItem: *base[2], TenantID: "globex"},
+	})
+	tm := order.NewTenantManager(order.Namespaced[*TenantItem](backend), order.NewOrderManager[*TenantItem]())
+
+	_, err := tm.Apply(ctx, "acme", "shared", order.Move[string]{Kind: order.MoveAbove, ItemID: b, TargetID: x})
+	var notFound *order.NotFoundError
+	require.ErrorAs(t, err, &notFound)
+	assert.Equal(t, x, notFound.ItemID)
+
+	_, err = tm.Apply(ctx, "acme", "shared", order.Move[string]{Kind: order.MoveTop, ItemID: x})
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	assert.Equal(t, 0, backend.Saves())
+
+	_, err = tm.Apply(ctx, "acme", "shared", order.Move[string]{Kind: order.MoveAbove, ItemID: b, TargetID: a})
+	require.NoError(t, err)
+}
+
+func TestTenantManagerUsesPersistentOptions(t *testing.T) {
+	ctx := context.Background()
+	backend := ordertest.NewStore[*ordertest.Item]()
+	backend.Put(order.TenantListID("acme", "board"), ordertest.Items(3))
+	backend.Put(order.TenantListID("globex", "board"), ordertest.Items(3))
+	var events []order.OrderChangedEvent
+	ids := 0
+	tm := order.NewTenantManager(order.Namespaced[*ordertest.Item](backend), order.NewOrderManager[*ordertest.Item](),
+		order.WithOperationIDs(func() string { ids++; return fmt.Sprintf("op-%d", ids) }),
+		order.WithObserver(func(_ context.Context, event order.OrderChangedEvent) { events = append(events, event) }),
+		order.WithAuditStore(order.NewMemoryAuditStore()),
+		order.WithIdempotencyStore(order.NewMemoryIdempotencyStore(0)))
+	assert.Same(t, tm.Tenant("acme"), tm.Tenant("acme"))
+
+	result, err := tm.Apply(ctx, "acme", "board", order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"})
+	require.NoError(t, err)
+	assert.Equal(t, "op-1", result.OperationID)
+	require.Len(t, events, 1)
+	assert.Equal(t, "acme", events[0].TenantID)
+	assert.Equal(t, "board", events[0].ListID)
+
+	// The same key and list ID in another tenant is another request.
+	m := order.Move[string]{Kind: order.MoveDown, ItemID: "item-1"}
+	_, err = tm.Tenant("acme").ApplyIdempotent(ctx, "board", "key", m)
+	require.NoError(t, err)
+	result, err = tm.Tenant("globex").ApplyIdempotent(ctx, "board", "key", m)
+	require.NoError(t, err)
+	assert.False(t, result.Replayed)
+
+	// History only returns the events of the tenant.
+	history, err := tm.Tenant("globex").History(ctx, order.AuditQuery{ListID: "board"})
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, "globex", history[0].TenantID)
+	require.NoError(t, tm.Close(ctx))
+}
@@ -0,0 +1,220 @@
+package order
+
+import (
+	"fmt"
+	"slices"
+)
+
+// Transaction stages a sequence of operations against a working copy of a
+// list, so that a multi-step change either applies as a whole or not at all.
+// Create one with Transaction, run the steps, then Commit or Rollback:
+//
+//	tx := om.Transaction(cards)
+//	tx.Remove(doneID)
+//	tx.Insert(newCard, 1)
+//	tx.Apply(order.Move[string]{Kind: order.MoveBelow, ItemID: urgentID, TargetID: newCard.ID})
+//	cards, changes, err := tx.Commit()
+//
+// Steps work on copies of the items, made as by Propose, so the original items
+// are not touched before Commit. Once a step fails, the later steps are
+// skipped and return the same error, and Commit fails with it.
+type Transaction[T any, ID comparable] struct {
+	manager   KeyedManager[T, ID]
+	originals map[ID]T
+	before    ChangeSet
+	work      []T
+	err       error
+	done      bool
+	saved     []savepoint[T]
+}
+
+// savepoint is the state of the working copy when Savepoint was called.
+type savepoint[T any] struct {
+	name      string
+	items     []T
+	positions []int
+}
+
+// Transaction starts a transaction on items. Steps are not reported to
+// metrics or the logger.
+func (os *KeyedManager[T, ID]) Transaction(items []T) *Transaction[T, ID] {
+	tx := &Transaction[T, ID]{
+		manager:   *os,
+		originals: make(map[ID]T, len(items)),
+		before:    make(ChangeSet, len(items)),
+		work:      make([]T, len(items)),
+	}
+	tx.manager.opts.metrics, tx.manager.opts.logger = nil, nil
+	for i, item := range items {
+		id := os.getID(item)
+		tx.originals[id] = item
+		tx.before[i] = PositionChange{ItemID: formatID(id), OldPosition: os.getPos(item)}
+		tx.work[i] = copyItem(item)
+	}
+	return tx
+}
+
+// Apply stages the move m.
+func (tx *Transaction[T, ID]) Apply(m Move[ID]) error {
+	return tx.step(func() error {
+		_, err := tx.manager.Apply(tx.work, m)
+		return err
+	})
+}
+
+// MoveMany stages moving the items with itemIDs as a block, see
+// KeyedManager.MoveMany.
+func (tx *Transaction[T, ID]) MoveMany(itemIDs []ID, position int) error {
+	return tx.step(func() error {
+		_, err := tx.manager.MoveMany(tx.work, itemIDs, position)
+		return err
+	})
+}
+
+// Insert stages adding item at the position, counted from the index base like
+// those of To, shifting later items down. A position one past the last item
+// appends. An ID that is already in
+// the list fails with a *DuplicateIDError. The item itself is staged, not a
+// copy, so its position changes with later steps.
+func (tx *Transaction[T, ID]) Insert(item T, position int) error {
+	return tx.step(func() error {
+		base := tx.manager.opts.base()
+		if position < base || position > base+len(tx.work) {
+			return &PositionError{Op: "Insert", Requested: position, Min: base, Max: base + len(tx.work)}
+		}
+		id := tx.manager.getID(item)
+		if index := tx.index(id); index >= 0 {
+			return &DuplicateIDError{Op: "Insert", ItemID: formatID(id), FirstIndex: index, SecondIndex: position - base}
+		}
+		tx.work = slices.Insert(tx.work, position-base, item)
+		tx.manager.normalize(tx.work)
+		return nil
+	})
+}
+
+// Remove stages taking the item with itemID out of the list.
+func (tx *Transaction[T, ID]) Remove(itemID ID) error {
+	return tx.step(func() error {
+		index := tx.index(itemID)
+		if index < 0 {
+			return &NotFoundError{Op: "Remove", ItemID: formatID(itemID)}
+		}
+		tx.work = slices.Delete(tx.work, index, index+1)
+		tx.manager.normalize(tx.work)
+		return nil
+	})
+}
+
+// Savepoint records the current state of the working copy under name, so
+// that RollbackTo can return to it and the steps after it can be retried. A
+// savepoint with the name of an earlier one hides it until RollbackTo has
+// gone back past the newer one. Savepoint fails if a step has failed.
+func (tx *Transaction[T, ID]) Savepoint(name string) error {
+	return tx.step(func() error {
+		positions := make([]int, len(tx.work))
+		for i, item := range tx.work {
+			positions[i] = tx.manager.getPos(item)
+		}
+		tx.saved = append(tx.saved, savepoint[T]{name: name, items: slices.Clone(tx.work), positions: positions})
+		return nil
+	})
+}
+
+// RollbackTo undoes the steps since the savepoint name, including a failed
+// one, so the transaction can go on from there. Savepoints made after name
+// are dropped; name itself stays and can be rolled back to again. It fails
+// with ErrSavepointNotFound if there is no such savepoint.
+func (tx *Transaction[T, ID]) RollbackTo(name string) error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+	i := len(tx.saved) - 1
+	for i >= 0 && tx.saved[i].name != name {
+		i--
+	}
+	if i < 0 {
+		return fmt.Errorf("RollbackTo %s: %w", name, ErrSavepointNotFound)
+	}
+	tx.saved = tx.saved[:i+1]
+	sp := tx.saved[i]
+	tx.work = slices.Clone(sp.items)
+	for j := range tx.work {
+		tx.manager.setPos(&tx.work[j], sp.positions[j])
+	}
+	tx.err = nil
+	return nil
+}
+
+// Items returns the working copy in its current order. The items must not be
+// modified.
+func (tx *Transaction[T, ID]) Items() []T {
+	return slices.Clone(tx.work)
+}
+
+// Err returns the error of the step that failed, if any.
+func (tx *Transaction[T, ID]) Err() error {
+	return tx.err
+}
+
+// Commit ends the transaction and, unless a step failed, carries the staged
+// positions over to the original items. It returns the list in its new order,
+// built from the original items and the inserted ones, and the changes from
+// the original list: inserted items have an OldPosition of 0 and removed
+// items a NewPosition of 0, like in Diff. If a step failed, Commit returns its
+// error and leaves the original items as they were.
+func (tx *Transaction[T, ID]) Commit() ([]T, ChangeSet, error) {
+	if tx.done {
+		return nil, nil, ErrTransactionDone
+	}
+	tx.done = true
+	if tx.err != nil {
+		return nil, nil, tx.err
+	}
+
+	old := make(map[string]int, len(tx.before))
+	for _, change := range tx.before {
+		old[change.ItemID] = change.OldPosition
+	}
+	committed := make([]T, len(tx.work))
+	var changes ChangeSet
+	for i, item := range tx.work {
+		id := tx.manager.getID(item)
+		if original, ok := tx.originals[id]; ok {
+			item = original
+		}
+		position := tx.manager.getPos(tx.work[i])
+		tx.manager.setPos(&item, position)
+		committed[i] = item
+
+		key := formatID(id)
+		if oldPosition, ok := old[key]; !ok || oldPosition != position {
+			changes = append(changes, PositionChange{ItemID: key, OldPosition: oldPosition, NewPosition: position})
+		}
+		delete(old, key)
+	}
+	for _, change := range tx.before {
+		if _, removed := old[change.ItemID]; removed {
+			changes = append(changes, change)
+		}
+	}
+	return committed, changes, nil
+}
+
+// Rollback ends the transaction without touching the original items.
+func (tx *Transaction[T, ID]) Rollback() {
+	tx.done = true
+}
+
+func (tx *Transaction[T, ID]) step(run func() error) error {
+	if tx.done {
+		return ErrTransactionDone
+	}
+	if tx.err == nil {
+		tx.err = run()
+	}
+	return tx.err
+}
+
+func (tx *Transaction[T, ID]) index(itemID ID) int {
+	return slices.IndexFunc(tx.work, func(item T) bool { return tx.manager.getID(item) == itemID })
+}
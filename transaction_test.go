@@ -0,0 +1,135 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+	"github.com/yacobolo/order/ordertest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionCommit(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.Items(4)
+	added := &ordertest.Item{ID: "new"}
+
+	tx := om.Transaction(items)
+	require.NoError(t, tx.Remove("item-2"))
+	require.NoError(t, tx.Insert(added, 1))
+	require.NoError(t, tx.Apply(order.Move[string]{Kind: order.MoveBelow, ItemID: "item-1", TargetID: "item-4"}))
+	ordertest.AssertOrder(t, tx.Items(), "new", "item-3", "item-4", "item-1")
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3", "item-4")
+	ordertest.AssertNormalized(t, items)
+
+	committed, changes, err := tx.Commit()
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, committed, "new", "item-3", "item-4", "item-1")
+	ordertest.AssertNormalized(t, committed)
+	assert.Same(t, items[0], committed[3], "committed lists hold the original items")
+	assert.Same(t, added, committed[0])
+	assert.Equal(t, order.ChangeSet{
+		{ItemID: "new", OldPosition: 0, NewPosition: 1},
+		{ItemID: "item-3", OldPosition: 3, NewPosition: 2},
+		{ItemID: "item-4", OldPosition: 4, NewPosition: 3},
+		{ItemID: "item-1", OldPosition: 1, NewPosition: 4},
+		{ItemID: "item-2", OldPosition: 2, NewPosition: 0},
+	}, changes)
+
+	_, _, err = tx.Commit()
+	assert.ErrorIs(t, err, order.ErrTransactionDone)
+	assert.ErrorIs(t, tx.Remove("item-1"), order.ErrTransactionDone)
+}
+
+func TestTransactionFailedStep(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.Items(3)
+
+	tx := om.Transaction(items)
+	require.NoError(t, tx.Apply(order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"}))
+	err := tx.Remove("gone")
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	assert.ErrorIs(t, tx.Apply(order.Move[string]{Kind: order.MoveBottom, ItemID: "item-3"}), order.ErrItemNotFound, "later steps are skipped")
+	ordertest.AssertOrder(t, tx.Items(), "item-3", "item-1", "item-2")
+	assert.Equal(t, err, tx.Err())
+
+	_, _, err = tx.Commit()
+	assert.ErrorIs(t, err, order.ErrItemNotFound)
+	ordertest.AssertOrder(t, items, "item-1", "item-2", "item-3")
+	ordertest.AssertNormalized(t, items)
+}
+
+func TestTransactionInsertAndRollback(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item]()
+	items := ordertest.Items(2)
+
+	tx := om.Transaction(items)
+	var positionErr *order.PositionError
+	assert.ErrorAs(t, tx.Insert(&ordertest.Item{ID: "new"}, 4), &positionErr)
+	assert.Equal(t, 3, positionErr.Max)
+
+	tx = om.Transaction(items)
+	assert.ErrorIs(t, tx.Insert(&ordertest.Item{ID: "item-1"}, 1), order.ErrDuplicateID)
+
+	tx = om.Transaction(items)
+	require.NoError(t, tx.MoveMany([]string{"item-2"}, 1))
+	tx.Rollback()
+	_, _, err := tx.Commit()
+	assert.ErrorIs(t, err, order.ErrTransactionDone)
+	ordertest.AssertOrder(t, items, "item-1", "item-2")
+	ordertest.AssertNormalized(t, items)
+}
+
+func TestTransactionInsertWithIndexBase(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item](order.WithIndexBase(0))
+	items := ordertest.Items(2)
+
+	tx := om.Transaction(items)
+	require.NoError(t, tx.Insert(&ordertest.Item{ID: "new"}, 0))
+	var positionErr *order.PositionError
+	assert.ErrorAs(t, tx.Insert(&ordertest.Item{ID: "late"}, 4), &positionErr)
+	assert.Equal(t, 0, positionErr.Min)
+	assert.Equal(t, 3, positionErr.Max)
+}
+
+func TestTransactionValues(t *testing.T) {
+	items := []plainItem{{"a", 1}, {"b", 2}, {"c", 3}}
+	tx := newPlainManager().Transaction(items)
+	require.NoError(t, tx.Apply(order.Move[string]{Kind: order.MoveTop, ItemID: "c"}))
+	require.NoError(t, tx.Insert(plainItem{Key: "d"}, 4))
+	committed, changes, err := tx.Commit()
+	require.NoError(t, err)
+	assert.Equal(t, []plainItem{{"c", 1}, {"a", 2}, {"b", 3}, {"d", 4}}, committed)
+	assert.Len(t, changes, 4)
+	assert.Equal(t, []plainItem{{"a", 1}, {"b", 2}, {"c", 3}}, items)
+}
+
+func TestTransactionSavepoints(t *testing.T) {
+	om := order.NewOrderManager[*ordertest.Item](order.WithBoundaryErrors())
+	items := ordertest.Items(4)
+
+	tx := om.Transaction(items)
+	require.NoError(t, tx.Apply(order.Move[string]{Kind: order.MoveBottom, ItemID: "item-1"}))
+	require.NoError(t, tx.Savepoint("a"))
+	require.NoError(t, tx.Remove("item-2"))
+	require.NoError(t, tx.Savepoint("b"))
+	require.NoError(t, tx.Apply(order.Move[string]{Kind: order.MoveUp, ItemID: "item-1"}))
+	assert.ErrorIs(t, tx.Apply(order.Move[string]{Kind: order.MoveUp, ItemID: "item-3"}), order.ErrAlreadyAtTop)
+
+	// Retry the sub-sequence after "a" differently.
+	require.NoError(t, tx.RollbackTo("a"))
+	assert.NoError(t, tx.Err())
+	ordertest.AssertOrder(t, tx.Items(), "item-2", "item-3", "item-4", "item-1")
+	ordertest.AssertNormalized(t, tx.Items())
+	assert.ErrorIs(t, tx.RollbackTo("b"), order.ErrSavepointNotFound, "later savepoints are dropped")
+	require.NoError(t, tx.Apply(order.Move[string]{Kind: order.MoveTop, ItemID: "item-4"}))
+	require.NoError(t, tx.RollbackTo("a"), "a savepoint can be rolled back to again")
+	require.NoError(t, tx.Apply(order.Move[string]{Kind: order.MoveTop, ItemID: "item-3"}))
+
+	committed, _, err := tx.Commit()
+	require.NoError(t, err)
+	ordertest.AssertOrder(t, committed, "item-3", "item-2", "item-4", "item-1")
+	ordertest.AssertNormalized(t, committed)
+	assert.ErrorIs(t, tx.RollbackTo("a"), order.ErrTransactionDone)
+}
@@ -0,0 +1,152 @@
+package order
+
+import "math/rand/v2"
+
+// treeList is a sequence backed by an implicit treap: a randomized balanced
+// binary tree ordered by index, where every node knows the size of its
+// subtree. Insertion, removal and lookup by index take O(log n) expected time,
+// and so does finding the index of a node through its parent pointers.
+type treeList[T any] struct {
+	root *treeNode[T]
+}
+
+type treeNode[T any] struct {
+	item                T
+	priority            uint32
+	size                int
+	left, right, parent *treeNode[T]
+}
+
+func newTreeList[T any](items []T) (*treeList[T], []*treeNode[T]) {
+	t := &treeList[T]{}
+	nodes := make([]*treeNode[T], len(items))
+	for i, item := range items {
+		nodes[i] = &treeNode[T]{item: item, priority: newPriority(), size: 1}
+		t.root = merge(t.root, nodes[i])
+	}
+	return t, nodes
+}
+
+// newPriority returns the random heap priority that keeps the tree balanced.
+func newPriority() uint32 {
+	return rand.Uint32()
+}
+
+func (t *treeList[T]) len() int {
+	return size(t.root)
+}
+
+// at returns the node at index.
+func (t *treeList[T]) at(index int) *treeNode[T] {
+	n := t.root
+	for {
+		left := size(n.left)
+		switch {
+		case index < left:
+			n = n.left
+		case index == left:
+			return n
+		default:
+			index -= left + 1
+			n = n.right
+		}
+	}
+}
+
+// index returns the index of n in the list.
+func (t *treeList[T]) index(n *treeNode[T]) int {
+	index := size(n.left)
+	for ; n.parent != nil; n = n.parent {
+		if n == n.parent.right {
+			index += size(n.parent.left) + 1
+		}
+	}
+	return index
+}
+
+// insert places n at index.
+func (t *treeList[T]) insert(n *treeNode[T], index int) {
+	n.left, n.right, n.parent, n.size = nil, nil, nil, 1
+	left, right := split(t.root, index)
+	t.root = merge(merge(left, n), right)
+}
+
+// remove takes the node at index out of the list and returns it.
+func (t *treeList[T]) remove(index int) *treeNode[T] {
+	left, rest := split(t.root, index)
+	n, right := split(rest, 1)
+	t.root = merge(left, right)
+	return n
+}
+
+// items returns the items in order.
+func (t *treeList[T]) items() []T {
+	items := make([]T, 0, t.len())
+	var walk func(n *treeNode[T])
+	walk = func(n *treeNode[T]) {
+		if n == nil {
+			return
+		}
+		walk(n.left)
+		items = append(items, n.item)
+		walk(n.right)
+	}
+	walk(t.root)
+	return items
+}
+
+func size[T any](n *treeNode[T]) int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// update recomputes the size of n and points its children back at it.
+func update[T any](n *treeNode[T]) {
+	n.size = 1 + size(n.left) + size(n.right)
+	if n.left != nil {
+		n.left.parent = n
+	}
+	if n.right != nil {
+		n.right.parent = n
+	}
+}
+
+// split cuts the tree into its first k nodes and the rest.
+func split[T any](n *treeNode[T], k int) (*treeNode[T], *treeNode[T]) {
+	if n == nil {
+		return nil, nil
+	}
+	n.parent = nil
+	if size(n.left) >= k {
+		left, right := split(n.left, k)
+		n.left = right
+		update(n)
+		return left, n
+	}
+	left, right := split(n.right, k-size(n.left)-1)
+	n.right = left
+	update(n)
+	return n, right
+}
+
+// merge joins two trees, all of a's nodes coming before b's.
+func merge[T any](a, b *treeNode[T]) *treeNode[T] {
+	switch {
+	case a == nil:
+		return b
+	case b == nil:
+		return a
+	case a.priority > b.priority:
+		a.right = merge(a.right, b)
+		update(a)
+		a.parent = nil
+		return a
+	default:
+		b.left = merge(a, b.left)
+		update(b)
+		b.parent = nil
+		return b
+	}
+}
@@ -0,0 +1,193 @@
+package order
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// IssueKind identifies the kind of problem reported by Validate.
+type IssueKind int
+
+const (
+	// IssueDuplicateID means two items share the same ID.
+	IssueDuplicateID IssueKind = iota + 1
+	// IssueDuplicatePosition means two items share the same position.
+	IssueDuplicatePosition
+	// IssueNonPositivePosition means an item has a zero or negative position.
+	IssueNonPositivePosition
+	// IssueOutOfOrder means an item has a lower position than the item before it in the slice.
+	IssueOutOfOrder
+	// IssueGap means one or more positions are missing before an item.
+	IssueGap
+)
+
+// String returns a short name for the issue kind.
+func (k IssueKind) String() string {
+	switch k {
+	case IssueDuplicateID:
+		return "duplicate id"
+	case IssueDuplicatePosition:
+		return "duplicate position"
+	case IssueNonPositivePosition:
+		return "non-positive position"
+	case IssueOutOfOrder:
+		return "out of order"
+	case IssueGap:
+		return "gap"
+	default:
+		return fmt.Sprintf("IssueKind(%d)", int(k))
+	}
+}
+
+// ValidationIssue describes a single problem found by Validate.
+type ValidationIssue struct {
+	Kind IssueKind
+	// Index is the slice index of the offending item.
+	Index    int
+	ItemID   string
+	Position int
+	// OtherIndex is the index of the item the offending item conflicts with,
+	// or -1 if the issue does not involve a second item.
+	OtherIndex int
+	Message    string
+}
+
+// String returns the issue message.
+func (i ValidationIssue) String() string {
+	return i.Message
+}
+
+// ValidationError is returned by operations that refuse to work on an invalid
+// ordering. It matches ErrInvalidOrder with errors.Is.
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.Message
+	}
+	return fmt.Sprintf("%v: %s", ErrInvalidOrder, strings.Join(messages, "; "))
+}
+
+func (e *ValidationError) Unwrap() error {
+	return ErrInvalidOrder
+}
+
+// Validate checks items for duplicate IDs, duplicate positions, zero or negative
+// positions, slice order that disagrees with position order, and gaps in the
+// position sequence. It returns nil if the items are consistently ordered and
+// normalized.
+func Validate[T Orderable](items []T) []ValidationIssue {
+	return validate(items, T.GetID, T.GetPosition)
+}
+
+func validate[T any, ID comparable](items []T, getID func(T) ID, getPos func(T) int) []ValidationIssue {
+	var issues []ValidationIssue
+	idIndex := make(map[ID]int, len(items))
+	positionIndex := make(map[int]int, len(items))
+
+	for index, item := range items {
+		key := getID(item)
+		position := getPos(item)
+		// The ID is only formatted once the item turns out to have an issue.
+		var id string
+		newIssue := func(kind IssueKind, otherIndex int) ValidationIssue {
+			if id == "" {
+				id = formatID(key)
+			}
+			return ValidationIssue{Kind: kind, Index: index, ItemID: id, Position: position, OtherIndex: otherIndex}
+		}
+
+		if other, ok := idIndex[key]; ok {
+			issue := newIssue(IssueDuplicateID, other)
+			issue.Message = fmt.Sprintf("item %s at index %d has the same ID as index %d", id, index, other)
+			issues = append(issues, issue)
+		} else {
+			idIndex[key] = index
+		}
+
+		if position < 1 {
+			issue := newIssue(IssueNonPositivePosition, -1)
+			issue.Message = fmt.Sprintf("item %s at index %d has position %d", id, index, position)
+			issues = append(issues, issue)
+		} else if other, ok := positionIndex[position]; ok {
+			issue := newIssue(IssueDuplicatePosition, other)
+			issue.Message = fmt.Sprintf("item %s at index %d has the same position %d as index %d", id, index, position, other)
+			issues = append(issues, issue)
+		} else {
+			positionIndex[position] = index
+		}
+
+		if index > 0 && position < getPos(items[index-1]) {
+			issue := newIssue(IssueOutOfOrder, index-1)
+			issue.Message = fmt.Sprintf("item %s at index %d has position %d, lower than position %d at index %d",
+				id, index, position, getPos(items[index-1]), index-1)
+			issues = append(issues, issue)
+		}
+	}
+
+	positions := make([]int, 0, len(positionIndex))
+	for position := range positionIndex {
+		positions = append(positions, position)
+	}
+	sort.Ints(positions)
+
+	expected := 1
+	for _, position := range positions {
+		if position > expected {
+			index := positionIndex[position]
+			missing := fmt.Sprintf("position %d is", expected)
+			if position-expected > 1 {
+				missing = fmt.Sprintf("positions %d-%d are", expected, position-1)
+			}
+			issues = append(issues, ValidationIssue{
+				Kind:       IssueGap,
+				Index:      index,
+				ItemID:     formatID(getID(items[index])),
+				Position:   position,
+				OtherIndex: -1,
+				Message:    fmt.Sprintf("%s missing before item %s at index %d", missing, formatID(getID(items[index])), index),
+			})
+		}
+		expected = position + 1
+	}
+
+	return issues
+}
+
+// IsNormalized reports whether the positions of items are exactly 1, 2, ..., n
+// in slice order.
+func IsNormalized[T Orderable](items []T) bool {
+	for i, item := range items {
+		if item.GetPosition() != i+1 {
+			return false
+		}
+	}
+	return true
+}
+
+// IsSortedByPosition reports whether the slice order agrees with the positions,
+// that is, no item has a lower position than the item before it. Equal
+// positions and gaps are allowed.
+func IsSortedByPosition[T Orderable](items []T) bool {
+	for i := 1; i < len(items); i++ {
+		if items[i].GetPosition() < items[i-1].GetPosition() {
+			return false
+		}
+	}
+	return true
+}
+
+// Invariants returns a *ValidationError if items violate any invariant that
+// every OrderManager operation establishes: unique IDs and positions exactly
+// 1..n in slice order. It is meant for property-based and differential tests
+// of wrappers and alternative implementations.
+func Invariants[T Orderable](items []T) error {
+	if issues := Validate(items); len(issues) > 0 {
+		return &ValidationError{Issues: issues}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package order_test
+
+import (
+	"testing"
+
+	"github.com/yacobolo/order"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func issueKinds(issues []order.ValidationIssue) []order.IssueKind {
+	kinds := make([]order.IssueKind, len(issues))
+	for i, issue := range issues {
+		kinds[i] = issue.Kind
+	}
+	return kinds
+}
+
+func TestValidateNormalized(t *testing.T) {
+	items := createTestItems(5)
+	assert.Empty(t, order.Validate(items))
+	assert.Empty(t, order.Validate([]*TestItem{}))
+}
+
+func TestValidateDuplicateID(t *testing.T) {
+	items := createTestItems(3)
+	items[2].ID = items[0].ID
+
+	issues := order.Validate(items)
+	require.Len(t, issues, 1)
+	assert.Equal(t, order.IssueDuplicateID, issues[0].Kind)
+	assert.Equal(t, 2, issues[0].Index)
+	assert.Equal(t, 0, issues[0].OtherIndex)
+}
+
+func TestValidateDuplicatePosition(t *testing.T) {
+	items := createTestItems(3)
+	items[1].SetPosition(1)
+	items[2].SetPosition(2)
+
+	issues := order.Validate(items)
+	assert.Equal(t, []order.IssueKind{order.IssueDuplicatePosition}, issueKinds(issues))
+	assert.Equal(t, 1, issues[0].Index)
+	assert.Equal(t, 0, issues[0].OtherIndex)
+}
+
+func TestValidateNonPositivePosition(t *testing.T) {
+	items := createTestItems(2)
+	items[0].SetPosition(0)
+	items[1].SetPosition(1)
+
+	issues := order.Validate(items)
+	assert.Equal(t, []order.IssueKind{order.IssueNonPositivePosition}, issueKinds(issues))
+}
+
+func TestValidateOutOfOrder(t *testing.T) {
+	items := createTestItems(3)
+	items[0].SetPosition(2)
+	items[1].SetPosition(1)
+
+	issues := order.Validate(items)
+	assert.Equal(t, []order.IssueKind{order.IssueOutOfOrder}, issueKinds(issues))
+	assert.Equal(t, 1, issues[0].Index)
+}
+
+func TestValidateGaps(t *testing.T) {
+	items := createTestItems(3)
+	items[0].SetPosition(2)
+	items[1].SetPosition(3)
+	items[2].SetPosition(7)
+
+	issues := order.Validate(items)
+	require.Equal(t, []order.IssueKind{order.IssueGap, order.IssueGap}, issueKinds(issues))
+	assert.Equal(t, 0, issues[0].Index)
+	assert.Equal(t, 2, issues[1].Index)
+	assert.Contains(t, issues[1].Message, "positions 4-6 are missing")
+}
+
+func TestIsNormalized(t *testing.T) {
+	items := createTestItems(3)
+	assert.True(t, order.IsNormalized(items))
+	assert.True(t, order.IsNormalized([]*TestItem{}))
+
+	items[2].SetPosition(4)
+	assert.False(t, order.IsNormalized(items))
+	assert.True(t, order.IsSortedByPosition(items))
+}
+
+func TestIsSortedByPosition(t *testing.T) {
+	items := createTestItems(3)
+	items[0].SetPosition(5)
+	items[1].SetPosition(5)
+	items[2].SetPosition(9)
+	assert.True(t, order.IsSortedByPosition(items))
+	assert.False(t, order.IsNormalized(items))
+
+	items[2].SetPosition(1)
+	assert.False(t, order.IsSortedByPosition(items))
+}
+
+func TestInvariants(t *testing.T) {
+	items := createTestItems(3)
+	assert.NoError(t, order.Invariants(items))
+
+	items[2].SetPosition(5)
+	err := order.Invariants(items)
+	assert.ErrorIs(t, err, order.ErrInvalidOrder)
+
+	var validationErr *order.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, order.IssueGap, validationErr.Issues[0].Kind)
+}